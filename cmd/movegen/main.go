@@ -0,0 +1,79 @@
+// Command movegen generates the Go bindings of a Move package.
+//
+// The normalized modules are fetched from a fullnode, or read from a JSON file of the
+// `sui_getNormalizedMoveModulesByPackage` result for offline builds:
+//
+//	movegen -package 0x123 -rpc https://fullnode.testnet.sui.io -pkg swap -o swap/swap.go
+//	movegen -json swap.json -pkg swap -o swap/swap.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suiclient/conn"
+	"github.com/pattonkan/sui-go/utils/movegen"
+)
+
+func main() {
+	packageIdHex := flag.String("package", "", "the package id to fetch, and to send the MoveCalls to")
+	rpc := flag.String("rpc", conn.TestnetEndpointUrl, "the fullnode to fetch the modules from")
+	jsonPath := flag.String("json", "", "read the normalized modules from the JSON file instead of the fullnode")
+	pkgName := flag.String("pkg", "", "the name of the generated Go package")
+	out := flag.String("o", "", "the output file, defaults to stdout")
+	flag.Parse()
+
+	if err := run(*packageIdHex, *rpc, *jsonPath, *pkgName, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "movegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(packageIdHex, rpc, jsonPath, pkgName, out string) error {
+	var packageId *sui.PackageId
+	if packageIdHex != "" {
+		var err error
+		packageId, err = sui.PackageIdFromHex(packageIdHex)
+		if err != nil {
+			return fmt.Errorf("invalid package id: %w", err)
+		}
+	}
+
+	var modules map[sui.Identifier]*sui.MoveNormalizedModule
+	switch {
+	case jsonPath != "":
+		data, err := os.ReadFile(jsonPath)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &modules); err != nil {
+			return fmt.Errorf("can't parse %s: %w", jsonPath, err)
+		}
+	case packageId != nil:
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		var err error
+		modules, err = suiclient.NewClient(rpc).GetNormalizedMoveModulesByPackage(ctx, packageId)
+		if err != nil {
+			return fmt.Errorf("can't fetch the modules of %s: %w", packageId, err)
+		}
+	default:
+		return fmt.Errorf("either -package or -json is required")
+	}
+
+	source, err := movegen.Generate(modules, movegen.Options{PackageName: pkgName, PackageId: packageId})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(out, source, 0o644)
+}
@@ -0,0 +1,103 @@
+package sui
+
+import "fmt"
+
+// TypeTag returns the type with the type parameters substituted by the type arguments.
+// A reference has no TypeTag, so the referenced type must be taken first.
+func (t *MoveNormalizedType) TypeTag(typeArgs []TypeTag) (*TypeTag, error) {
+	switch {
+	case t.Bool != nil:
+		return &TypeTag{Bool: &EmptyEnum{}}, nil
+	case t.U8 != nil:
+		return &TypeTag{U8: &EmptyEnum{}}, nil
+	case t.U16 != nil:
+		return &TypeTag{U16: &EmptyEnum{}}, nil
+	case t.U32 != nil:
+		return &TypeTag{U32: &EmptyEnum{}}, nil
+	case t.U64 != nil:
+		return &TypeTag{U64: &EmptyEnum{}}, nil
+	case t.U128 != nil:
+		return &TypeTag{U128: &EmptyEnum{}}, nil
+	case t.U256 != nil:
+		return &TypeTag{U256: &EmptyEnum{}}, nil
+	case t.Address != nil:
+		return &TypeTag{Address: &EmptyEnum{}}, nil
+	case t.Signer != nil:
+		return &TypeTag{Signer: &EmptyEnum{}}, nil
+	case t.Vector != nil:
+		elem, err := t.Vector.TypeTag(typeArgs)
+		if err != nil {
+			return nil, err
+		}
+		return &TypeTag{Vector: elem}, nil
+	case t.Struct != nil:
+		structTag := &StructTag{
+			Address: t.Struct.Address,
+			Module:  t.Struct.Module,
+			Name:    t.Struct.Name,
+		}
+		for _, arg := range t.Struct.TypeArguments {
+			tag, err := arg.TypeTag(typeArgs)
+			if err != nil {
+				return nil, err
+			}
+			structTag.TypeParams = append(structTag.TypeParams, *tag)
+		}
+		return &TypeTag{Struct: structTag}, nil
+	case t.TypeParameter != nil:
+		if int(*t.TypeParameter) >= len(typeArgs) {
+			return nil, fmt.Errorf("type parameter %d is out of %d type arguments", *t.TypeParameter, len(typeArgs))
+		}
+		tag := typeArgs[*t.TypeParameter]
+		return &tag, nil
+	case t.Reference != nil, t.MutableReference != nil:
+		return nil, fmt.Errorf("reference has no TypeTag")
+	default:
+		return nil, fmt.Errorf("empty MoveNormalizedType")
+	}
+}
+
+// Dereference returns the referenced type of a reference, and the type itself otherwise
+func (t *MoveNormalizedType) Dereference() *MoveNormalizedType {
+	switch {
+	case t.Reference != nil:
+		return t.Reference
+	case t.MutableReference != nil:
+		return t.MutableReference
+	default:
+		return t
+	}
+}
+
+// IsTxContext reports whether the type is `0x2::tx_context::TxContext` or a reference to it,
+// which is passed by the runtime instead of the callers
+func (t *MoveNormalizedType) IsTxContext() bool {
+	s := t.Dereference().Struct
+	return s != nil && s.Address != nil && *s.Address == *SuiPackageIdSuiFramework &&
+		s.Module == "tx_context" && s.Name == "TxContext"
+}
+
+// Has reports whether the ability set contains the ability
+func (s MoveAbilitySet) Has(ability MoveAbility) bool {
+	for _, a := range s.Abilities {
+		if a == ability {
+			return true
+		}
+	}
+	return false
+}
+
+func (a MoveAbility) String() string {
+	switch a {
+	case MoveAbilityCopy:
+		return "copy"
+	case MoveAbilityDrop:
+		return "drop"
+	case MoveAbilityStore:
+		return "store"
+	case MoveAbilityKey:
+		return "key"
+	default:
+		return "none"
+	}
+}
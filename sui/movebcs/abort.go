@@ -0,0 +1,72 @@
+package movebcs
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// the names of the abort codes, by `address::module` with the full address
+var abortCodes = struct {
+	sync.RWMutex
+	names map[string]map[uint64]string
+}{names: map[string]map[uint64]string{}}
+
+func init() {
+	MustRegisterAbortCode("0x2::balance", EBalanceNonZero, "ENonZero")
+	MustRegisterAbortCode("0x2::balance", EBalanceOverflow, "EOverflow")
+	MustRegisterAbortCode("0x2::balance", EBalanceNotEnough, "ENotEnough")
+	MustRegisterAbortCode("0x2::balance", EBalanceNotSystemAddress, "ENotSystemAddress")
+	MustRegisterAbortCode("0x2::balance", EBalanceNotSUI, "ENotSUI")
+	MustRegisterAbortCode("0x2::borrow", EBorrowWrongBorrow, "EWrongBorrow")
+	MustRegisterAbortCode("0x2::borrow", EBorrowWrongValue, "EWrongValue")
+	MustRegisterAbortCode("0x2::clock", EClockNotSystemAddress, "ENotSystemAddress")
+	MustRegisterAbortCode("0x2::coin", ECoinBadWitness, "EBadWitness")
+	MustRegisterAbortCode("0x2::coin", ECoinInvalidArg, "EInvalidArg")
+	MustRegisterAbortCode("0x2::coin", ECoinNotEnough, "ENotEnough")
+	// ECoinGlobalPauseNotAllowed is an #[error] constant, so it doesn't abort with its own value
+}
+
+// RegisterAbortCode names the abort code of the Move module, e.g.
+// `RegisterAbortCode("0x2::coin", ECoinNotEnough, "ENotEnough")`
+func RegisterAbortCode(module string, code uint64, name string) error {
+	key, err := abortCodeKey(module)
+	if err != nil {
+		return err
+	}
+	abortCodes.Lock()
+	defer abortCodes.Unlock()
+	if abortCodes.names[key] == nil {
+		abortCodes.names[key] = map[uint64]string{}
+	}
+	abortCodes.names[key][code] = name
+	return nil
+}
+
+func MustRegisterAbortCode(module string, code uint64, name string) {
+	if err := RegisterAbortCode(module, code, name); err != nil {
+		panic(err)
+	}
+}
+
+func abortCodeKey(module string) (string, error) {
+	parts := strings.Split(module, "::")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid module %q, expected `address::module`", module)
+	}
+	address, err := sui.AddressFromHex(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid address of %q: %w", module, err)
+	}
+	return fmt.Sprintf("%s::%s", address, parts[1]), nil
+}
+
+// AbortCodeName returns the registered name of the abort code of the Move module
+func AbortCodeName(address *sui.Address, module sui.Identifier, code uint64) (string, bool) {
+	abortCodes.RLock()
+	defer abortCodes.RUnlock()
+	name, ok := abortCodes.names[fmt.Sprintf("%s::%s", address, module)][code]
+	return name, ok
+}
@@ -0,0 +1,25 @@
+package movebcs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+)
+
+func TestAbortCodeName(t *testing.T) {
+	name, ok := movebcs.AbortCodeName(sui.MustAddressFromHex("0x2"), "balance", movebcs.EBalanceNotEnough)
+	require.True(t, ok)
+	require.Equal(t, "ENotEnough", name)
+
+	_, ok = movebcs.AbortCodeName(sui.MustAddressFromHex("0x42"), "pool", 7)
+	require.False(t, ok)
+	require.NoError(t, movebcs.RegisterAbortCode("0x42::pool", 7, "EPaused"))
+	name, ok = movebcs.AbortCodeName(sui.MustAddressFromHex("0x0042"), "pool", 7)
+	require.True(t, ok)
+	require.Equal(t, "EPaused", name)
+
+	require.Error(t, movebcs.RegisterAbortCode("0x42", 7, "EPaused"))
+}
@@ -0,0 +1,102 @@
+package movebcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+)
+
+// the top bit of an abort code marks a clever error
+const cleverErrorTag = uint64(1) << 63
+
+// CleverErrorNone is the identifier or the constant index of a clever error which has none
+const CleverErrorNone = 0xffff
+
+// CleverErrorCode is the abort code of a Move 2024 `#[error]` constant, or of an `assert!` without a code,
+// which packs the source line of the abort and the indexes of the constant and of its name in the module
+type CleverErrorCode struct {
+	Line            uint16
+	IdentifierIndex uint16
+	ConstantIndex   uint16
+}
+
+// ParseCleverErrorCode unpacks the abort code, and returns false if it isn't a clever error
+func ParseCleverErrorCode(code uint64) (*CleverErrorCode, bool) {
+	if code&cleverErrorTag == 0 {
+		return nil, false
+	}
+	return &CleverErrorCode{
+		Line:            uint16(code >> 32),
+		IdentifierIndex: uint16(code >> 16),
+		ConstantIndex:   uint16(code),
+	}, true
+}
+
+// CleverError is a clever error resolved by the module which aborted
+type CleverError struct {
+	Line uint16
+	// the name of the error constant, empty if the abort has no constant
+	Name     string
+	Constant *Constant
+}
+
+// ResolveCleverError resolves the clever error code by the module which aborted with it
+func ResolveCleverError(module *CompiledModule, code uint64) (*CleverError, error) {
+	cleverCode, ok := ParseCleverErrorCode(code)
+	if !ok {
+		return nil, fmt.Errorf("abort code %d isn't a clever error", code)
+	}
+	cleverError := &CleverError{Line: cleverCode.Line}
+	if cleverCode.IdentifierIndex != CleverErrorNone {
+		if int(cleverCode.IdentifierIndex) >= len(module.Identifiers) {
+			return nil, fmt.Errorf("identifier %d is out of the module", cleverCode.IdentifierIndex)
+		}
+		cleverError.Name = module.Identifiers[cleverCode.IdentifierIndex]
+	}
+	if cleverCode.ConstantIndex != CleverErrorNone {
+		if int(cleverCode.ConstantIndex) >= len(module.Constants) {
+			return nil, fmt.Errorf("constant %d is out of the module", cleverCode.ConstantIndex)
+		}
+		cleverError.Constant = module.Constants[cleverCode.ConstantIndex]
+	}
+	return cleverError, nil
+}
+
+// Value renders the value of the error constant, a `vector<u8>` in UTF-8 is rendered as a string
+func (e *CleverError) Value() (string, error) {
+	if e.Constant == nil {
+		return "", nil
+	}
+	if e.Constant.Type.Vector != nil && e.Constant.Type.Vector.U8 != nil {
+		r := bytes.NewReader(e.Constant.Data)
+		if b, err := readBytes(r); err == nil && r.Len() == 0 && utf8.Valid(b) {
+			return string(b), nil
+		}
+	}
+	value, err := NewDecoder(nil).Decode(context.Background(), e.Constant.Type, e.Constant.Data)
+	if err != nil {
+		return "", err
+	}
+	rendered, err := value.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// String renders the clever error like `EName: value`, or like `abort at line 12` without a constant
+func (e *CleverError) String() string {
+	if e.Name == "" {
+		return fmt.Sprintf("abort at line %d", e.Line)
+	}
+	if e.Constant == nil {
+		return e.Name
+	}
+	value, err := e.Value()
+	if err != nil {
+		value = "0x" + hex.EncodeToString(e.Constant.Data)
+	}
+	return fmt.Sprintf("%s: %s", e.Name, value)
+}
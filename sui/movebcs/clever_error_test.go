@@ -0,0 +1,67 @@
+package movebcs_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui/movebcs"
+)
+
+// a module with the identifiers [EPaused, pool] and the constants [b"Pool is paused", 7u64]
+const pausedModule = "oRzrCwYAAAACBwANBg0cB0VQYXVzZWQEcG9vbAoCDw5Qb29sIGlzIHBhdXNlZAMIBwAAAAAAAAA="
+
+func TestParseCompiledModule(t *testing.T) {
+	data, err := os.ReadFile("../../contracts/testcoin/contract_base64.json")
+	require.NoError(t, err)
+	var contract struct{ Modules [][]byte }
+	require.NoError(t, json.Unmarshal(data, &contract))
+	module, err := movebcs.ParseCompiledModule(contract.Modules[0])
+	require.NoError(t, err)
+	require.Contains(t, module.Identifiers, "TESTCOIN")
+	require.Contains(t, module.Identifiers, "create_currency")
+
+	_, err = movebcs.ParseCompiledModule([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	require.Error(t, err)
+}
+
+func TestCleverError(t *testing.T) {
+	bytecode, err := base64.StdEncoding.DecodeString(pausedModule)
+	require.NoError(t, err)
+	module, err := movebcs.ParseCompiledModule(bytecode)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EPaused", "pool"}, module.Identifiers)
+	require.Len(t, module.Constants, 2)
+	require.Equal(t, "vector<u8>", module.Constants[0].Type.String())
+
+	_, ok := movebcs.ParseCleverErrorCode(movebcs.ECoinNotEnough)
+	require.False(t, ok)
+	_, err = movebcs.ResolveCleverError(module, movebcs.ECoinNotEnough)
+	require.Error(t, err)
+
+	// line 42, identifier 0, constant 0
+	code, ok := movebcs.ParseCleverErrorCode(0x8000002a00000000)
+	require.True(t, ok)
+	require.Equal(t, &movebcs.CleverErrorCode{Line: 42}, code)
+	cleverError, err := movebcs.ResolveCleverError(module, 0x8000002a00000000)
+	require.NoError(t, err)
+	require.Equal(t, "EPaused", cleverError.Name)
+	require.Equal(t, "EPaused: Pool is paused", cleverError.String())
+
+	// line 42, identifier 1, constant 1
+	cleverError, err = movebcs.ResolveCleverError(module, 0x8000002a00010001)
+	require.NoError(t, err)
+	require.Equal(t, `pool: "7"`, cleverError.String())
+
+	// an assert! without a code only has the line
+	cleverError, err = movebcs.ResolveCleverError(module, 0x8000002affffffff)
+	require.NoError(t, err)
+	require.Nil(t, cleverError.Constant)
+	require.Equal(t, "abort at line 42", cleverError.String())
+
+	_, err = movebcs.ResolveCleverError(module, 0x8000002a00000005)
+	require.ErrorContains(t, err, "constant 5")
+}
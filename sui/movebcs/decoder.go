@@ -0,0 +1,197 @@
+package movebcs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// the max depth of the nested values, which is the max value depth of Move
+const maxValueDepth = 128
+
+// StructResolver returns the layouts of the Move structs, which is implemented by `suiclient.ClientImpl`
+type StructResolver interface {
+	GetNormalizedMoveStruct(
+		ctx context.Context,
+		packageId *sui.PackageId,
+		module sui.Identifier,
+		object sui.Identifier,
+	) (*sui.MoveNormalizedStruct, error)
+}
+
+// Decoder decodes the BCS bytes of Move values without Go structs, by the struct layouts from the
+// resolver. The layouts are cached, so a Decoder should be reused. It is safe for concurrent use.
+type Decoder struct {
+	resolver StructResolver
+
+	mu      sync.Mutex
+	layouts map[string]*sui.MoveNormalizedStruct
+}
+
+func NewDecoder(resolver StructResolver) *Decoder {
+	return &Decoder{
+		resolver: resolver,
+		layouts:  map[string]*sui.MoveNormalizedStruct{},
+	}
+}
+
+// Decode decodes the value of the type, which must consume all the bytes
+func (d *Decoder) Decode(ctx context.Context, typeTag *sui.TypeTag, data []byte) (*MoveValue, error) {
+	r := bytes.NewReader(data)
+	value, err := d.decode(ctx, r, typeTag, 0)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode %s: %w", typeTag, err)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("can't decode %s: %d trailing bytes", typeTag, r.Len())
+	}
+	return value, nil
+}
+
+// Layout returns the fields of the struct with the type parameters substituted by the type arguments
+func (d *Decoder) Layout(ctx context.Context, structTag *sui.StructTag) ([]*MoveField, error) {
+	layout, err := d.layout(ctx, structTag)
+	if err != nil {
+		return nil, err
+	}
+	if len(layout.TypeParameters) != len(structTag.TypeParams) {
+		return nil, fmt.Errorf("%s::%s expects %d type arguments, got %d",
+			structTag.Module, structTag.Name, len(layout.TypeParameters), len(structTag.TypeParams))
+	}
+	fields := make([]*MoveField, len(layout.Fields))
+	for i, field := range layout.Fields {
+		fieldType, err := field.Type.TypeTag(structTag.TypeParams)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		fields[i] = &MoveField{Name: field.Name, Value: &MoveValue{Type: fieldType}}
+	}
+	return fields, nil
+}
+
+func (d *Decoder) layout(ctx context.Context, structTag *sui.StructTag) (*sui.MoveNormalizedStruct, error) {
+	key := fmt.Sprintf("%s::%s::%s", structTag.Address, structTag.Module, structTag.Name)
+	d.mu.Lock()
+	layout, ok := d.layouts[key]
+	d.mu.Unlock()
+	if ok {
+		return layout, nil
+	}
+	layout, err := d.resolver.GetNormalizedMoveStruct(ctx, structTag.Address, structTag.Module, structTag.Name)
+	if err != nil {
+		return nil, fmt.Errorf("can't get the layout of %s::%s: %w", structTag.Module, structTag.Name, err)
+	}
+	d.mu.Lock()
+	d.layouts[key] = layout
+	d.mu.Unlock()
+	return layout, nil
+}
+
+func (d *Decoder) decode(ctx context.Context, r *bytes.Reader, tag *sui.TypeTag, depth int) (*MoveValue, error) {
+	if depth > maxValueDepth {
+		return nil, fmt.Errorf("value is nested deeper than %d", maxValueDepth)
+	}
+	value := &MoveValue{Type: tag}
+	switch {
+	case tag.Bool != nil:
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b > 1 {
+			return nil, fmt.Errorf("invalid bool: %d", b)
+		}
+		v := b == 1
+		value.Bool = &v
+	case numberSize(tag) > 0:
+		b := make([]byte, numberSize(tag))
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		reverse(b)
+		value.Number = new(big.Int).SetBytes(b)
+	case tag.Address != nil, isUID(tag), isID(tag):
+		var address sui.Address
+		if _, err := io.ReadFull(r, address[:]); err != nil {
+			return nil, err
+		}
+		value.Address = &address
+	case isString(tag):
+		length, err := readLength(r)
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, length)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		s := string(b)
+		value.String = &s
+	case isOption(tag):
+		if len(tag.Struct.TypeParams) != 1 {
+			return nil, fmt.Errorf("0x1::option::Option expects 1 type argument, got %d", len(tag.Struct.TypeParams))
+		}
+		length, err := readLength(r)
+		if err != nil {
+			return nil, err
+		}
+		value.Option = &MoveOption{}
+		switch length {
+		case 0:
+		case 1:
+			value.Option.Some, err = d.decode(ctx, r, &tag.Struct.TypeParams[0], depth+1)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid Option length: %d", length)
+		}
+	case tag.Vector != nil:
+		length, err := readLength(r)
+		if err != nil {
+			return nil, err
+		}
+		value.Vector = make([]*MoveValue, 0, length)
+		for i := 0; i < length; i++ {
+			elem, err := d.decode(ctx, r, tag.Vector, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			value.Vector = append(value.Vector, elem)
+		}
+	case tag.Struct != nil:
+		fields, err := d.Layout(ctx, tag.Struct)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			field.Value, err = d.decode(ctx, r, field.Value.Type, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		value.Struct = fields
+	default:
+		return nil, fmt.Errorf("can't decode a value of %s", tag)
+	}
+	return value, nil
+}
+
+// readLength reads the length of a vector, which can't exceed the remaining bytes as every Move value
+// takes at least one byte
+func readLength(r *bytes.Reader) (int, error) {
+	length, _, err := bcs.ULEB128Decode[int](r)
+	if err != nil {
+		return 0, err
+	}
+	if length < 0 || length > r.Len() {
+		return 0, fmt.Errorf("invalid length: %d", length)
+	}
+	return length, nil
+}
@@ -0,0 +1,157 @@
+package movebcs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+)
+
+const moduleMagic = 0xA11CEB0B
+
+// the kinds of the tables of a compiled module
+const (
+	tableConstantPool = 0x6
+	tableIdentifiers  = 0x7
+)
+
+// the signature tokens of the constant types
+const (
+	tokenBool    = 0x1
+	tokenU8      = 0x2
+	tokenU64     = 0x3
+	tokenU128    = 0x4
+	tokenAddress = 0x5
+	tokenVector  = 0xA
+	tokenU16     = 0xD
+	tokenU32     = 0xE
+	tokenU256    = 0xF
+)
+
+// CompiledModule is the part of the bytecode of a Move module which names and values its constants
+type CompiledModule struct {
+	Identifiers []string
+	Constants   []*Constant
+}
+
+// Constant is a constant of a Move module, whose Data is the BCS of its value
+type Constant struct {
+	Type *sui.TypeTag
+	Data []byte
+}
+
+// ParseCompiledModule reads the identifiers and the constants of the bytecode of a Move module,
+// e.g. a module of the ModuleMap of a package
+func ParseCompiledModule(bytecode []byte) (*CompiledModule, error) {
+	if len(bytecode) < 8 || binary.BigEndian.Uint32(bytecode) != moduleMagic {
+		return nil, fmt.Errorf("not a Move module")
+	}
+	r := bytes.NewReader(bytecode[8:])
+	tableCount, _, err := bcs.ULEB128Decode[int](r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid table count: %w", err)
+	}
+	type table struct {
+		kind   byte
+		offset int
+		length int
+	}
+	tables := make([]table, 0, tableCount)
+	for i := 0; i < tableCount; i++ {
+		var t table
+		if t.kind, err = r.ReadByte(); err != nil {
+			return nil, fmt.Errorf("invalid table header: %w", err)
+		}
+		if t.offset, _, err = bcs.ULEB128Decode[int](r); err != nil {
+			return nil, fmt.Errorf("invalid table header: %w", err)
+		}
+		if t.length, _, err = bcs.ULEB128Decode[int](r); err != nil {
+			return nil, fmt.Errorf("invalid table header: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	// the offsets are relative to the end of the table headers
+	content := bytecode[len(bytecode)-r.Len():]
+
+	module := &CompiledModule{}
+	for _, t := range tables {
+		if t.offset < 0 || t.length < 0 || t.offset+t.length > len(content) {
+			return nil, fmt.Errorf("table %d is out of the module", t.kind)
+		}
+		data := bytes.NewReader(content[t.offset : t.offset+t.length])
+		switch t.kind {
+		case tableIdentifiers:
+			for data.Len() > 0 {
+				identifier, err := readBytes(data)
+				if err != nil {
+					return nil, fmt.Errorf("invalid identifier: %w", err)
+				}
+				module.Identifiers = append(module.Identifiers, string(identifier))
+			}
+		case tableConstantPool:
+			for data.Len() > 0 {
+				tag, err := readConstantType(data, 0)
+				if err != nil {
+					return nil, fmt.Errorf("invalid constant %d: %w", len(module.Constants), err)
+				}
+				value, err := readBytes(data)
+				if err != nil {
+					return nil, fmt.Errorf("invalid constant %d: %w", len(module.Constants), err)
+				}
+				module.Constants = append(module.Constants, &Constant{Type: tag, Data: value})
+			}
+		}
+	}
+	return module, nil
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	length, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func readConstantType(r *bytes.Reader, depth int) (*sui.TypeTag, error) {
+	if depth > maxValueDepth {
+		return nil, fmt.Errorf("type is nested deeper than %d", maxValueDepth)
+	}
+	token, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case tokenBool:
+		return &sui.TypeTag{Bool: &sui.EmptyEnum{}}, nil
+	case tokenU8:
+		return &sui.TypeTag{U8: &sui.EmptyEnum{}}, nil
+	case tokenU16:
+		return &sui.TypeTag{U16: &sui.EmptyEnum{}}, nil
+	case tokenU32:
+		return &sui.TypeTag{U32: &sui.EmptyEnum{}}, nil
+	case tokenU64:
+		return &sui.TypeTag{U64: &sui.EmptyEnum{}}, nil
+	case tokenU128:
+		return &sui.TypeTag{U128: &sui.EmptyEnum{}}, nil
+	case tokenU256:
+		return &sui.TypeTag{U256: &sui.EmptyEnum{}}, nil
+	case tokenAddress:
+		return &sui.TypeTag{Address: &sui.EmptyEnum{}}, nil
+	case tokenVector:
+		elem, err := readConstantType(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return &sui.TypeTag{Vector: elem}, nil
+	default:
+		return nil, fmt.Errorf("unsupported constant type token %#x", token)
+	}
+}
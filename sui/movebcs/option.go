@@ -0,0 +1,28 @@
+package movebcs
+
+// Option is `0x1::option::Option<T>`, which is a vector of at most one element in Move.
+// Unlike `bcs.Option`, it is encoded and decoded correctly as a struct field and by value.
+type Option[T any] struct {
+	Vec []T
+}
+
+func Some[T any](v T) Option[T] {
+	return Option[T]{Vec: []T{v}}
+}
+
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+func (o Option[T]) IsSome() bool {
+	return len(o.Vec) > 0
+}
+
+// Get returns the value and whether it is some
+func (o Option[T]) Get() (T, bool) {
+	if len(o.Vec) == 0 {
+		var zero T
+		return zero, false
+	}
+	return o.Vec[0], true
+}
@@ -0,0 +1,36 @@
+package movebcs_test
+
+import (
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOption(t *testing.T) {
+	type memo struct {
+		Text  movebcs.Option[string]
+		Count uint8
+	}
+	for _, tt := range []struct {
+		value memo
+		bytes []byte
+	}{
+		{value: memo{Text: movebcs.Some("hi"), Count: 7}, bytes: []byte{1, 2, 'h', 'i', 7}},
+		{value: memo{Text: movebcs.None[string](), Count: 7}, bytes: []byte{0, 7}},
+	} {
+		b, err := bcs.Marshal(tt.value)
+		require.NoError(t, err)
+		require.Equal(t, tt.bytes, b)
+
+		var decoded memo
+		_, err = bcs.Unmarshal(b, &decoded)
+		require.NoError(t, err)
+		require.Equal(t, tt.value.Text.IsSome(), decoded.Text.IsSome())
+		text, _ := decoded.Text.Get()
+		expected, _ := tt.value.Text.Get()
+		require.Equal(t, expected, text)
+		require.Equal(t, tt.value.Count, decoded.Count)
+	}
+}
@@ -0,0 +1,143 @@
+package movebcs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// the Go types of the Move structs, by `address::module::Name` with the full address, and the Go types
+// of Option[T] by the Go type of T, since a generic type can't be instantiated by reflect
+var registry = struct {
+	sync.RWMutex
+	types   map[string]reflect.Type
+	options map[reflect.Type]reflect.Type
+}{types: map[string]reflect.Type{}, options: map[reflect.Type]reflect.Type{}}
+
+func init() {
+	registerOption[bool]()
+	registerOption[uint8]()
+	registerOption[uint16]()
+	registerOption[uint32]()
+	registerOption[uint64]()
+	registerOption[bcs.Uint128]()
+	registerOption[[32]uint8]()
+	registerOption[*sui.Address]()
+	registerOption[string]()
+	registerOption[[]uint8]()
+	MustRegister[MoveCoin]("0x2::coin::Coin")
+	MustRegister[MoveCoinMetadata]("0x2::coin::CoinMetadata")
+	MustRegister[MoveRegulatedCoinMetadata]("0x2::coin::RegulatedCoinMetadata")
+	MustRegister[MoveTreasuryCap]("0x2::coin::TreasuryCap")
+	MustRegister[MoveDenyCapV2]("0x2::coin::DenyCapV2")
+	MustRegister[MoveBalance]("0x2::balance::Balance")
+	MustRegister[MoveSupply]("0x2::balance::Supply")
+	MustRegister[Clock]("0x2::clock::Clock")
+	MustRegister[Borrow]("0x2::borrow::Borrow")
+}
+
+// Register binds the Go struct T to the Move struct, e.g. `Register[MoveCoin]("0x2::coin::Coin")`,
+// so NewValue can decode the values of the Move struct. The type arguments of the Move struct are
+// ignored, so T must decode the struct for all of them.
+func Register[T any](structType string) error {
+	key, err := registryKey(structType)
+	if err != nil {
+		return err
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.types[key] = reflect.TypeOf((*T)(nil)).Elem()
+	registry.options[reflect.TypeOf((*T)(nil)).Elem()] = reflect.TypeOf(Option[T]{})
+	return nil
+}
+
+func registerOption[T any]() {
+	registry.options[reflect.TypeOf((*T)(nil)).Elem()] = reflect.TypeOf(Option[T]{})
+}
+
+func MustRegister[T any](structType string) {
+	if err := Register[T](structType); err != nil {
+		panic(err)
+	}
+}
+
+func registryKey(structType string) (string, error) {
+	parts := strings.Split(structType, "::")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid struct type %q, expected `address::module::Name`", structType)
+	}
+	address, err := sui.AddressFromHex(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("invalid address of %q: %w", structType, err)
+	}
+	return fmt.Sprintf("%s::%s::%s", address, parts[1], parts[2]), nil
+}
+
+// GoType returns the Go type which decodes the BCS of the Move type. The primitives, vectors, strings,
+// object ids, options and the registered structs are supported. u256 is decoded into a little endian `[32]uint8`.
+// `Option<T>` is Option[T] if T is a primitive, `vector<u8>`, a string, an id or a registered struct,
+// otherwise a struct of the same fields, which converts to Option[T].
+func GoType(tag *sui.TypeTag) (reflect.Type, error) {
+	switch {
+	case tag.Bool != nil:
+		return reflect.TypeOf(false), nil
+	case tag.U8 != nil:
+		return reflect.TypeOf(uint8(0)), nil
+	case tag.U16 != nil:
+		return reflect.TypeOf(uint16(0)), nil
+	case tag.U32 != nil:
+		return reflect.TypeOf(uint32(0)), nil
+	case tag.U64 != nil:
+		return reflect.TypeOf(uint64(0)), nil
+	case tag.U128 != nil:
+		return reflect.TypeOf(bcs.Uint128{}), nil
+	case tag.U256 != nil:
+		return reflect.TypeOf([32]uint8{}), nil
+	case tag.Address != nil, isUID(tag), isID(tag):
+		return reflect.TypeOf(&sui.Address{}), nil
+	case isString(tag):
+		return reflect.TypeOf(""), nil
+	case tag.Vector != nil:
+		elem, err := GoType(tag.Vector)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case isOption(tag) && len(tag.Struct.TypeParams) == 1:
+		elem, err := GoType(&tag.Struct.TypeParams[0])
+		if err != nil {
+			return nil, err
+		}
+		registry.RLock()
+		t, ok := registry.options[elem]
+		registry.RUnlock()
+		if !ok {
+			t = reflect.StructOf([]reflect.StructField{{Name: "Vec", Type: reflect.SliceOf(elem)}})
+		}
+		return t, nil
+	case tag.Struct != nil:
+		key := fmt.Sprintf("%s::%s::%s", tag.Struct.Address, tag.Struct.Module, tag.Struct.Name)
+		registry.RLock()
+		t, ok := registry.types[key]
+		registry.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("no Go type is registered for %s", tag)
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("no Go type for %s", tag)
+	}
+}
+
+// NewValue returns a pointer to a new zero value of the Go type of the Move type, to decode into
+func NewValue(tag *sui.TypeTag) (any, error) {
+	t, err := GoType(tag)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.New(t).Interface(), nil
+}
@@ -0,0 +1,87 @@
+package movebcs_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/stretchr/testify/require"
+)
+
+type testPosition struct {
+	Id     *sui.ObjectId
+	Amount uint64
+}
+
+func TestRegistry(t *testing.T) {
+	require.NoError(t, movebcs.Register[testPosition]("0x42::pool::Position"))
+	require.Error(t, movebcs.Register[testPosition]("0x42::Position"))
+
+	goType, err := movebcs.GoType(sui.MustNewTypeTag("vector<0x42::pool::Position<0x2::sui::SUI>>"))
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeOf([]testPosition{}), goType)
+
+	goType, err = movebcs.GoType(sui.MustNewTypeTag("0x2::coin::Coin<0x2::sui::SUI>"))
+	require.NoError(t, err)
+	require.Equal(t, reflect.TypeOf(movebcs.MoveCoin{}), goType)
+
+	_, err = movebcs.GoType(sui.MustNewTypeTag("0x42::pool::Unknown"))
+	require.Error(t, err)
+
+	position := testPosition{Id: sui.MustObjectIdFromHex("0x1"), Amount: 5}
+	b, err := bcs.Marshal(position)
+	require.NoError(t, err)
+	v, err := movebcs.NewValue(sui.MustNewTypeTag("0x0000000000000000000000000000000000000000000000000000000000000042::pool::Position"))
+	require.NoError(t, err)
+	_, err = bcs.Unmarshal(b, v)
+	require.NoError(t, err)
+	require.Equal(t, &position, v)
+}
+
+func TestRegistryOption(t *testing.T) {
+	require.NoError(t, movebcs.Register[testPosition]("0x42::pool::Position"))
+
+	tests := []struct {
+		typeTag string
+		goType  reflect.Type
+	}{
+		{"0x1::option::Option<u64>", reflect.TypeOf(movebcs.Option[uint64]{})},
+		{"0x1::option::Option<0x1::string::String>", reflect.TypeOf(movebcs.Option[string]{})},
+		{"0x1::option::Option<0x2::object::ID>", reflect.TypeOf(movebcs.Option[*sui.Address]{})},
+		{"0x1::option::Option<vector<u8>>", reflect.TypeOf(movebcs.Option[[]uint8]{})},
+		{"0x1::option::Option<0x42::pool::Position>", reflect.TypeOf(movebcs.Option[testPosition]{})},
+		{"vector<0x1::option::Option<u8>>", reflect.TypeOf([]movebcs.Option[uint8]{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeTag, func(t *testing.T) {
+			goType, err := movebcs.GoType(sui.MustNewTypeTag(tt.typeTag))
+			require.NoError(t, err)
+			require.Equal(t, tt.goType, goType)
+		})
+	}
+
+	some := movebcs.Some(testPosition{Id: sui.MustObjectIdFromHex("0x1"), Amount: 5})
+	b, err := bcs.Marshal(some)
+	require.NoError(t, err)
+	v, err := movebcs.NewValue(sui.MustNewTypeTag("0x1::option::Option<0x42::pool::Position>"))
+	require.NoError(t, err)
+	_, err = bcs.Unmarshal(b, v)
+	require.NoError(t, err)
+	require.Equal(t, &some, v)
+
+	// an option of another type is decoded into a struct of the same fields
+	nested := movebcs.Some([]uint64{1, 2})
+	b, err = bcs.Marshal(nested)
+	require.NoError(t, err)
+	v, err = movebcs.NewValue(sui.MustNewTypeTag("0x1::option::Option<vector<u64>>"))
+	require.NoError(t, err)
+	_, err = bcs.Unmarshal(b, v)
+	require.NoError(t, err)
+	converted := reflect.ValueOf(v).Elem().Convert(reflect.TypeOf(movebcs.Option[[]uint64]{}))
+	require.Equal(t, nested, converted.Interface())
+
+	_, err = movebcs.GoType(sui.MustNewTypeTag("0x1::option::Option<0x42::pool::Unknown>"))
+	require.Error(t, err)
+}
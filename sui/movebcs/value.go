@@ -0,0 +1,258 @@
+package movebcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// MoveValue is a Move value decoded by the layout of its type. Type is always set, and one of the
+// other fields is set by the kind of the type.
+type MoveValue struct {
+	Type *sui.TypeTag
+
+	Bool *bool
+	// u8 to u256
+	Number *big.Int
+	// address, `0x2::object::ID` and `0x2::object::UID`
+	Address *sui.Address
+	// `0x1::string::String` and `0x1::ascii::String`
+	String *string
+	Vector []*MoveValue
+	// `0x1::option::Option`
+	Option *MoveOption
+	Struct []*MoveField
+}
+
+type MoveOption struct {
+	// nil for none
+	Some *MoveValue
+}
+
+type MoveField struct {
+	Name  sui.Identifier
+	Value *MoveValue
+}
+
+// Field returns the value of the struct field, or nil if there is no such field
+func (v *MoveValue) Field(name sui.Identifier) *MoveValue {
+	for _, field := range v.Struct {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+// MarshalBCS encodes the value by its type, so the decoded values can be encoded back
+func (v *MoveValue) MarshalBCS() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := v.encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (v *MoveValue) encode(buf *bytes.Buffer) error {
+	if v == nil || v.Type == nil {
+		return fmt.Errorf("can't encode a MoveValue without type")
+	}
+	tag := v.Type
+	switch {
+	case tag.Bool != nil:
+		if v.Bool == nil {
+			return mismatch(v)
+		}
+		if *v.Bool {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case numberSize(tag) > 0:
+		size := numberSize(tag)
+		if v.Number == nil || v.Number.Sign() < 0 || v.Number.BitLen() > size*8 {
+			return fmt.Errorf("%v doesn't fit in %s", v.Number, tag)
+		}
+		b := make([]byte, size)
+		v.Number.FillBytes(b)
+		reverse(b)
+		buf.Write(b)
+	case tag.Address != nil, isUID(tag), isID(tag):
+		if v.Address == nil {
+			return mismatch(v)
+		}
+		buf.Write(v.Address[:])
+	case isString(tag):
+		if v.String == nil {
+			return mismatch(v)
+		}
+		buf.Write(bcs.ULEB128Encode(len(*v.String)))
+		buf.WriteString(*v.String)
+	case isOption(tag):
+		if v.Option == nil {
+			return mismatch(v)
+		}
+		if v.Option.Some == nil {
+			buf.WriteByte(0)
+			return nil
+		}
+		buf.WriteByte(1)
+		return v.Option.Some.encode(buf)
+	case tag.Vector != nil:
+		buf.Write(bcs.ULEB128Encode(len(v.Vector)))
+		for _, elem := range v.Vector {
+			if err := elem.encode(buf); err != nil {
+				return err
+			}
+		}
+	case tag.Struct != nil:
+		if v.Struct == nil {
+			return mismatch(v)
+		}
+		for _, field := range v.Struct {
+			if err := field.Value.encode(buf); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("can't encode a value of %s", tag)
+	}
+	return nil
+}
+
+// MarshalJSON renders the value like the Move object content returned by the Sui RPC, where the
+// integers larger than u32 are strings, and the struct fields keep their order
+func (v *MoveValue) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := v.renderJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (v *MoveValue) renderJSON(buf *bytes.Buffer) error {
+	if v == nil || v.Type == nil {
+		return fmt.Errorf("can't render a MoveValue without type")
+	}
+	tag := v.Type
+	switch {
+	case tag.Bool != nil && v.Bool != nil:
+		return writeJSON(buf, *v.Bool)
+	case v.Number != nil:
+		if tag.U8 != nil || tag.U16 != nil || tag.U32 != nil {
+			buf.WriteString(v.Number.String())
+			return nil
+		}
+		return writeJSON(buf, v.Number.String())
+	case isUID(tag) && v.Address != nil:
+		buf.WriteString(`{"id":`)
+		if err := writeJSON(buf, v.Address.String()); err != nil {
+			return err
+		}
+		buf.WriteString("}")
+	case v.Address != nil:
+		return writeJSON(buf, v.Address.String())
+	case v.String != nil:
+		return writeJSON(buf, *v.String)
+	case v.Option != nil:
+		if v.Option.Some == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return v.Option.Some.renderJSON(buf)
+	case tag.Vector != nil:
+		buf.WriteString("[")
+		for i, elem := range v.Vector {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := elem.renderJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	case tag.Struct != nil && v.Struct != nil:
+		buf.WriteString("{")
+		for i, field := range v.Struct {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := writeJSON(buf, field.Name); err != nil {
+				return err
+			}
+			buf.WriteString(":")
+			if err := field.Value.renderJSON(buf); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+		buf.WriteString("}")
+	default:
+		return mismatch(v)
+	}
+	return nil
+}
+
+func writeJSON(buf *bytes.Buffer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+func mismatch(v *MoveValue) error {
+	return fmt.Errorf("MoveValue doesn't match its type %s", v.Type)
+}
+
+// numberSize is the number of bytes of an integer type, and 0 for the other types
+func numberSize(tag *sui.TypeTag) int {
+	switch {
+	case tag.U8 != nil:
+		return 1
+	case tag.U16 != nil:
+		return 2
+	case tag.U32 != nil:
+		return 4
+	case tag.U64 != nil:
+		return 8
+	case tag.U128 != nil:
+		return 16
+	case tag.U256 != nil:
+		return 32
+	default:
+		return 0
+	}
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+func isStructOf(tag *sui.TypeTag, address *sui.Address, module sui.Identifier, name sui.Identifier) bool {
+	s := tag.Struct
+	return s != nil && s.Address != nil && *s.Address == *address && s.Module == module && s.Name == name
+}
+
+func isString(tag *sui.TypeTag) bool {
+	return isStructOf(tag, sui.SuiPackageIdMoveStdlib, "string", "String") ||
+		isStructOf(tag, sui.SuiPackageIdMoveStdlib, "ascii", "String")
+}
+
+func isOption(tag *sui.TypeTag) bool {
+	return isStructOf(tag, sui.SuiPackageIdMoveStdlib, "option", "Option")
+}
+
+func isUID(tag *sui.TypeTag) bool {
+	return isStructOf(tag, sui.SuiPackageIdSuiFramework, "object", "UID")
+}
+
+func isID(tag *sui.TypeTag) bool {
+	return isStructOf(tag, sui.SuiPackageIdSuiFramework, "object", "ID")
+}
@@ -0,0 +1,156 @@
+package movebcs_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver serves the struct layouts from JSON, like `sui_getNormalizedMoveStruct`
+type fakeResolver struct {
+	structs map[string]string
+	calls   int
+}
+
+func (r *fakeResolver) GetNormalizedMoveStruct(
+	ctx context.Context,
+	packageId *sui.PackageId,
+	module sui.Identifier,
+	object sui.Identifier,
+) (*sui.MoveNormalizedStruct, error) {
+	r.calls++
+	data, ok := r.structs[fmt.Sprintf("%s::%s", module, object)]
+	if !ok {
+		return nil, fmt.Errorf("struct %s::%s not found", module, object)
+	}
+	var s sui.MoveNormalizedStruct
+	return &s, json.Unmarshal([]byte(data), &s)
+}
+
+var testResolver = map[string]string{
+	"vault::Vault": `{
+		"abilities": {"abilities": ["Key"]},
+		"typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}, {"constraints": {"abilities": []}, "isPhantom": false}],
+		"fields": [
+			{"name": "id", "type": {"Struct": {"address": "0x2", "module": "object", "name": "UID", "typeArguments": []}}},
+			{"name": "balance", "type": {"Struct": {"address": "0x2", "module": "balance", "name": "Balance", "typeArguments": [{"TypeParameter": 0}]}}},
+			{"name": "entries", "type": {"Vector": {"TypeParameter": 1}}},
+			{"name": "memo", "type": {"Struct": {"address": "0x1", "module": "option", "name": "Option", "typeArguments": [{"Struct": {"address": "0x1", "module": "string", "name": "String", "typeArguments": []}}]}}},
+			{"name": "owner", "type": "Address"},
+			{"name": "total", "type": "U256"}
+		]
+	}`,
+	"vault::Entry": `{
+		"abilities": {"abilities": ["Store"]},
+		"typeParameters": [],
+		"fields": [
+			{"name": "key", "type": {"Vector": "U8"}},
+			{"name": "amount", "type": "U64"},
+			{"name": "frozen", "type": "Bool"}
+		]
+	}`,
+	"balance::Balance": `{
+		"abilities": {"abilities": ["Store"]},
+		"typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}],
+		"fields": [{"name": "value", "type": "U64"}]
+	}`,
+}
+
+type testEntry struct {
+	Key    []byte
+	Amount uint64
+	Frozen bool
+}
+
+type testVault struct {
+	Id      *sui.ObjectId
+	Balance movebcs.MoveBalance
+	Entries []testEntry
+	Memo    movebcs.Option[string]
+	Owner   *sui.Address
+	Total   [32]byte
+}
+
+func TestDecodeMoveValue(t *testing.T) {
+	vault := testVault{
+		Id:      sui.MustObjectIdFromHex("0x123"),
+		Balance: movebcs.MoveBalance{Value: 1000},
+		Entries: []testEntry{{Key: []byte("a"), Amount: 1, Frozen: true}, {Key: []byte{}, Amount: 2}},
+		Memo:    movebcs.Some("hello"),
+		Owner:   sui.MustAddressFromHex("0xabc"),
+	}
+	vault.Total[0], vault.Total[31] = 1, 2
+	data, err := bcs.Marshal(&vault)
+	require.NoError(t, err)
+
+	resolver := &fakeResolver{structs: testResolver}
+	decoder := movebcs.NewDecoder(resolver)
+	typeTag := sui.MustNewTypeTag("0x42::vault::Vault<0x2::sui::SUI, 0x42::vault::Entry>")
+	value, err := decoder.Decode(context.Background(), typeTag, data)
+	require.NoError(t, err)
+
+	require.Equal(t, *sui.MustAddressFromHex("0x123"), *value.Field("id").Address)
+	require.Equal(t, big.NewInt(1000), value.Field("balance").Field("value").Number)
+	require.Len(t, value.Field("entries").Vector, 2)
+	require.True(t, *value.Field("entries").Vector[0].Field("frozen").Bool)
+	require.Equal(t, "hello", *value.Field("memo").Option.Some.String)
+	total := new(big.Int).Lsh(big.NewInt(2), 248)
+	total.Add(total, big.NewInt(1))
+	require.Equal(t, total, value.Field("total").Number)
+	require.Nil(t, value.Field("missing"))
+
+	// the layouts are cached
+	require.Equal(t, 3, resolver.calls)
+	_, err = decoder.Decode(context.Background(), typeTag, data)
+	require.NoError(t, err)
+	require.Equal(t, 3, resolver.calls)
+
+	encoded, err := bcs.Marshal(value)
+	require.NoError(t, err)
+	require.Equal(t, data, encoded)
+
+	rendered, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.JSONEq(t, fmt.Sprintf(`{
+		"id": {"id": "%s"},
+		"balance": {"value": "1000"},
+		"entries": [{"key": [97], "amount": "1", "frozen": true}, {"key": [], "amount": "2", "frozen": false}],
+		"memo": "hello",
+		"owner": "%s",
+		"total": "%s"
+	}`, sui.MustAddressFromHex("0x123"), sui.MustAddressFromHex("0xabc"), total), string(rendered))
+}
+
+func TestDecodeMoveValueErrors(t *testing.T) {
+	decoder := movebcs.NewDecoder(&fakeResolver{structs: testResolver})
+	ctx := context.Background()
+
+	_, err := decoder.Decode(ctx, sui.MustNewTypeTag("u64"), []byte{1, 0, 0, 0, 0, 0, 0, 0, 0})
+	require.ErrorContains(t, err, "trailing bytes")
+	_, err = decoder.Decode(ctx, sui.MustNewTypeTag("u64"), []byte{1})
+	require.Error(t, err)
+	_, err = decoder.Decode(ctx, sui.MustNewTypeTag("bool"), []byte{2})
+	require.ErrorContains(t, err, "invalid bool")
+	_, err = decoder.Decode(ctx, sui.MustNewTypeTag("vector<u8>"), []byte{100, 1})
+	require.ErrorContains(t, err, "invalid length")
+	_, err = decoder.Decode(ctx, sui.MustNewTypeTag("0x42::vault::Vault<0x2::sui::SUI>"), []byte{})
+	require.ErrorContains(t, err, "expects 2 type arguments")
+	_, err = decoder.Decode(ctx, sui.MustNewTypeTag("0x42::vault::Missing"), []byte{})
+	require.ErrorContains(t, err, "not found")
+
+	// a value is encoded by its type
+	value := &movebcs.MoveValue{Type: sui.MustNewTypeTag("u8"), Number: big.NewInt(256)}
+	_, err = bcs.Marshal(value)
+	require.Error(t, err)
+	value = &movebcs.MoveValue{Type: sui.MustNewTypeTag("u16"), Number: big.NewInt(258)}
+	b, err := bcs.Marshal(value)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2, 1}, b)
+}
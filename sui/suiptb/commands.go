@@ -22,6 +22,26 @@ type Command struct {
 
 func (c Command) IsBcsEnum() {}
 
+// Arguments returns all the arguments the command takes
+func (c Command) Arguments() []Argument {
+	switch {
+	case c.MoveCall != nil:
+		return c.MoveCall.Arguments
+	case c.TransferObjects != nil:
+		return append(append([]Argument{}, c.TransferObjects.Objects...), c.TransferObjects.Address)
+	case c.SplitCoins != nil:
+		return append([]Argument{c.SplitCoins.Coin}, c.SplitCoins.Amounts...)
+	case c.MergeCoins != nil:
+		return append([]Argument{c.MergeCoins.Destination}, c.MergeCoins.Sources...)
+	case c.MakeMoveVec != nil:
+		return c.MakeMoveVec.Objects
+	case c.Upgrade != nil:
+		return []Argument{c.Upgrade.Ticket}
+	default:
+		return nil
+	}
+}
+
 type Argument struct {
 	// The gas coin. The gas coin can only be used by-ref, except for with
 	// `TransferObjects`, which can use it by-value.
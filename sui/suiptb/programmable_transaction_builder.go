@@ -15,6 +15,9 @@ import (
 type ProgrammableTransactionBuilder struct {
 	Inputs   *indexmap.IndexMap[BuilderArg, CallArg] //maybe has hash clash
 	Commands []Command
+
+	// the inputs added by their object ids only, see `ObjId()`
+	unresolved map[sui.ObjectId]*UnresolvedObject
 }
 
 // ProgrammableTransaction is the packed immediate transaction type which will be encoded
@@ -24,6 +27,29 @@ type ProgrammableTransaction struct {
 	Commands []Command
 }
 
+// InputObjectIds returns the ids of the objects the transaction takes as inputs
+func (p *ProgrammableTransaction) InputObjectIds() []*sui.ObjectId {
+	var ids []*sui.ObjectId
+	for _, input := range p.Inputs {
+		if input.Object != nil {
+			ids = append(ids, input.Object.id())
+		}
+	}
+	return ids
+}
+
+// UsesGasCoin reports whether any of the commands takes the gas coin as an argument
+func (p *ProgrammableTransaction) UsesGasCoin() bool {
+	for _, command := range p.Commands {
+		for _, arg := range command.Arguments() {
+			if arg.GasCoin != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 type BuilderArg struct {
 	Object              *sui.ObjectId
 	Pure                *[]uint8
@@ -36,7 +62,25 @@ func NewTransactionDataTransactionBuilder() *ProgrammableTransactionBuilder {
 	}
 }
 
+// ErrUnresolvedObjects is returned by `FinishChecked()` if an input added by its object id only isn't resolved
+var ErrUnresolvedObjects = errors.New("object inputs are unresolved")
+
+// Finish builds the ProgrammableTransaction. It panics if an input added by its object id only, e.g. by `ObjId()`,
+// isn't resolved yet, since the input would be encoded as an empty object. See `FinishChecked()`.
 func (p *ProgrammableTransactionBuilder) Finish() ProgrammableTransaction {
+	pt, err := p.FinishChecked()
+	if err != nil {
+		panic(err.Error())
+	}
+	return pt
+}
+
+// FinishChecked builds the ProgrammableTransaction like `Finish()`, but returns ErrUnresolvedObjects
+// instead of panicking.
+func (p *ProgrammableTransactionBuilder) FinishChecked() (ProgrammableTransaction, error) {
+	if len(p.unresolved) > 0 {
+		return ProgrammableTransaction{}, fmt.Errorf("%d %w, e.g. %s", len(p.unresolved), ErrUnresolvedObjects, p.UnresolvedObjects()[0].Id)
+	}
 	var inputs []CallArg
 	p.Inputs.ForEach(func(k BuilderArg, v CallArg) {
 		inputs = append(inputs, v)
@@ -44,7 +88,7 @@ func (p *ProgrammableTransactionBuilder) Finish() ProgrammableTransaction {
 	return ProgrammableTransaction{
 		Inputs:   inputs,
 		Commands: p.Commands,
-	}
+	}, nil
 }
 
 func (p *ProgrammableTransactionBuilder) Pure(value any) (Argument, error) {
@@ -66,6 +110,20 @@ func (p *ProgrammableTransactionBuilder) MustPure(value any) Argument {
 // refer crates/sui-types/src/programmable_transaction_builder.rs
 func (p *ProgrammableTransactionBuilder) Obj(objArg ObjectArg) (Argument, error) {
 	id := objArg.id()
+	if unresolved, ok := p.unresolved[*id]; ok {
+		// the object is known now, so the input added by its id is resolved, with the mutability it was added with
+		if objArg.SharedObject != nil && unresolved.Mutable != nil {
+			shared := *objArg.SharedObject
+			shared.Mutable = shared.Mutable || *unresolved.Mutable
+			objArg = ObjectArg{SharedObject: &shared}
+		}
+		if err := p.ResolveObject(objArg); err != nil {
+			return Argument{}, err
+		}
+		i, _ := p.Inputs.Find(BuilderArg{Object: id})
+		idx := uint16(i)
+		return Argument{Input: &idx}, nil
+	}
 	var oj ObjectArg
 	if oldValue, ok := p.Inputs.Get(BuilderArg{Object: id}); ok {
 		var oldObjArg ObjectArg
@@ -0,0 +1,208 @@
+package suiptb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+)
+
+var ErrPureTypeMismatch = errors.New("pure argument type mismatch")
+
+// IsPureType reports whether a value of the type can be passed as a pure argument
+func IsPureType(tag *sui.TypeTag) bool {
+	switch {
+	case tag.Bool != nil, tag.U8 != nil, tag.U16 != nil, tag.U32 != nil, tag.U64 != nil, tag.U128 != nil, tag.U256 != nil, tag.Address != nil:
+		return true
+	case tag.Vector != nil:
+		return IsPureType(tag.Vector)
+	case tag.Struct != nil:
+		switch {
+		case isStdStruct(tag.Struct, "string", "String"), isStdStruct(tag.Struct, "ascii", "String"), isObjectId(tag.Struct):
+			return true
+		case isStdStruct(tag.Struct, "option", "Option"):
+			return len(tag.Struct.TypeParams) == 1 && IsPureType(&tag.Struct.TypeParams[0])
+		}
+	}
+	return false
+}
+
+// EncodePure BCS-encodes the Go value as a pure argument of the Move type. The Go type must match exactly:
+//   - bool, u8, u16, u32 and u64 take bool, uint8, uint16, uint32 and uint64
+//   - u128 and u256 take *big.Int or *sui.BigInt, and u128 also takes bcs.Uint128
+//   - address and 0x2::object::ID take sui.Address or *sui.Address
+//   - 0x1::string::String and 0x1::ascii::String take string
+//   - vector<T> takes a slice or an array of the values of T, e.g. []byte for vector<u8>
+//   - 0x1::option::Option<T> takes nil for None, and a pointer to the value of T for Some
+func EncodePure(tag *sui.TypeTag, value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodePure(&buf, tag, reflect.ValueOf(value)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePure(buf *bytes.Buffer, tag *sui.TypeTag, v reflect.Value) error {
+	mismatch := func() error {
+		if !v.IsValid() {
+			return fmt.Errorf("%w: expected %s, got nil", ErrPureTypeMismatch, tag)
+		}
+		return fmt.Errorf("%w: expected %s, got %s", ErrPureTypeMismatch, tag, v.Type())
+	}
+	if !v.IsValid() && !(tag.Struct != nil && isStdStruct(tag.Struct, "option", "Option")) {
+		return mismatch()
+	}
+
+	switch {
+	case tag.Bool != nil:
+		b, ok := v.Interface().(bool)
+		if !ok {
+			return mismatch()
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case tag.U8 != nil:
+		n, ok := v.Interface().(uint8)
+		if !ok {
+			return mismatch()
+		}
+		buf.WriteByte(n)
+	case tag.U16 != nil:
+		n, ok := v.Interface().(uint16)
+		if !ok {
+			return mismatch()
+		}
+		buf.Write(binary.LittleEndian.AppendUint16(nil, n))
+	case tag.U32 != nil:
+		n, ok := v.Interface().(uint32)
+		if !ok {
+			return mismatch()
+		}
+		buf.Write(binary.LittleEndian.AppendUint32(nil, n))
+	case tag.U64 != nil:
+		n, ok := v.Interface().(uint64)
+		if !ok {
+			return mismatch()
+		}
+		buf.Write(binary.LittleEndian.AppendUint64(nil, n))
+	case tag.U128 != nil:
+		if n, ok := v.Interface().(bcs.Uint128); ok {
+			b, err := n.MarshalBCS()
+			if err != nil {
+				return err
+			}
+			buf.Write(b)
+			return nil
+		}
+		return encodeBigUint(buf, tag, v, 16, mismatch)
+	case tag.U256 != nil:
+		return encodeBigUint(buf, tag, v, 32, mismatch)
+	case tag.Address != nil:
+		return encodeAddress(buf, v, mismatch)
+	case tag.Vector != nil:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return mismatch()
+		}
+		buf.Write(bcs.ULEB128Encode(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := encodePure(buf, tag.Vector, v.Index(i)); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+	case tag.Struct != nil && (isStdStruct(tag.Struct, "string", "String") || isStdStruct(tag.Struct, "ascii", "String")):
+		s, ok := v.Interface().(string)
+		if !ok {
+			return mismatch()
+		}
+		if tag.Struct.Module == "ascii" {
+			for _, c := range []byte(s) {
+				if c > 0x7f {
+					return fmt.Errorf("%w: %q is not an ASCII string", ErrPureTypeMismatch, s)
+				}
+			}
+		} else if !utf8.ValidString(s) {
+			return fmt.Errorf("%w: %q is not a UTF-8 string", ErrPureTypeMismatch, s)
+		}
+		buf.Write(bcs.ULEB128Encode(len(s)))
+		buf.WriteString(s)
+	case tag.Struct != nil && isObjectId(tag.Struct):
+		return encodeAddress(buf, v, mismatch)
+	case tag.Struct != nil && isStdStruct(tag.Struct, "option", "Option") && len(tag.Struct.TypeParams) == 1:
+		if !v.IsValid() {
+			buf.WriteByte(0)
+			return nil
+		}
+		if v.Kind() != reflect.Pointer {
+			return mismatch()
+		}
+		if v.IsNil() {
+			buf.WriteByte(0)
+			return nil
+		}
+		buf.WriteByte(1)
+		return encodePure(buf, &tag.Struct.TypeParams[0], v.Elem())
+	default:
+		return fmt.Errorf("%w: %s can't be a pure argument", ErrPureTypeMismatch, tag)
+	}
+	return nil
+}
+
+func encodeBigUint(buf *bytes.Buffer, tag *sui.TypeTag, v reflect.Value, size int, mismatch func() error) error {
+	var n *big.Int
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		n = x
+	case *sui.BigInt:
+		if x != nil {
+			n = x.Int
+		}
+	default:
+		return mismatch()
+	}
+	if n == nil {
+		return mismatch()
+	}
+	if n.Sign() < 0 || n.BitLen() > size*8 {
+		return fmt.Errorf("%w: %s is out of the range of %s", ErrPureTypeMismatch, n, tag)
+	}
+	b := make([]byte, size)
+	n.FillBytes(b)
+	// BCS integers are little endian
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	buf.Write(b)
+	return nil
+}
+
+func encodeAddress(buf *bytes.Buffer, v reflect.Value, mismatch func() error) error {
+	switch a := v.Interface().(type) {
+	case sui.Address:
+		buf.Write(a[:])
+	case *sui.Address:
+		if a == nil {
+			return mismatch()
+		}
+		buf.Write(a[:])
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+func isStdStruct(s *sui.StructTag, module string, name string) bool {
+	return s.Address != nil && *s.Address == *sui.SuiPackageIdMoveStdlib && s.Module == module && s.Name == name
+}
+
+func isObjectId(s *sui.StructTag) bool {
+	return s.Address != nil && *s.Address == *sui.SuiPackageIdSuiFramework && s.Module == "object" && s.Name == "ID"
+}
@@ -0,0 +1,90 @@
+package suiptb_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+func TestEncodePure(t *testing.T) {
+	addr := sui.MustAddressFromHex("0x2")
+	u128, err := bcs.NewUint128("340282366920938463463374607431768211455")
+	require.NoError(t, err)
+	some := uint64(7)
+	var none *uint64
+
+	tests := []struct {
+		typeTag string
+		value   any
+		// the value encoded by go-bcs, which must be the same
+		expected any
+	}{
+		{"bool", true, true},
+		{"u8", uint8(1), uint8(1)},
+		{"u16", uint16(0x1234), uint16(0x1234)},
+		{"u32", uint32(0x12345678), uint32(0x12345678)},
+		{"u64", uint64(1 << 40), uint64(1 << 40)},
+		{"u128", *u128, *u128},
+		{"u128", big.NewInt(258), *bcs.NewUint128FromUint64(258, 0)},
+		{"address", addr, addr},
+		{"0x2::object::ID", *addr, addr},
+		{"0x1::string::String", "héllo", "héllo"},
+		{"0x1::ascii::String", "hello", "hello"},
+		{"vector<u8>", []byte{1, 2, 3}, []byte{1, 2, 3}},
+		{"vector<vector<u64>>", [][]uint64{{1}, {2, 3}}, [][]uint64{{1}, {2, 3}}},
+		{"0x1::option::Option<u64>", &some, []uint64{7}},
+		{"0x1::option::Option<u64>", none, []uint64{}},
+		{"0x1::option::Option<u64>", nil, []uint64{}},
+		{"vector<0x1::string::String>", []string{"a", "bc"}, []string{"a", "bc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeTag, func(t *testing.T) {
+			tag := sui.MustNewTypeTag(tt.typeTag)
+			require.True(t, suiptb.IsPureType(tag))
+			b, err := suiptb.EncodePure(tag, tt.value)
+			require.NoError(t, err)
+			expected, err := bcs.Marshal(tt.expected)
+			require.NoError(t, err)
+			require.Equal(t, expected, b)
+		})
+	}
+
+	t.Run("u256", func(t *testing.T) {
+		b, err := suiptb.EncodePure(sui.MustNewTypeTag("u256"), big.NewInt(0x0102))
+		require.NoError(t, err)
+		require.Equal(t, append([]byte{0x02, 0x01}, make([]byte, 30)...), b)
+	})
+}
+
+func TestEncodePureMismatch(t *testing.T) {
+	tests := []struct {
+		typeTag string
+		value   any
+	}{
+		{"u64", 1},
+		{"u64", uint32(1)},
+		{"u8", -1},
+		{"vector<u8>", "not bytes"},
+		{"0x1::string::String", []byte("bytes")},
+		{"0x1::ascii::String", "héllo"},
+		{"0x1::string::String", "h\xffllo"},
+		{"u128", big.NewInt(-1)},
+		{"u128", new(big.Int).Lsh(big.NewInt(1), 128)},
+		{"address", "0x2"},
+		{"vector<u64>", []int{1, 2}},
+		{"0x1::option::Option<u64>", uint64(1)},
+		{"0x2::coin::Coin<0x2::sui::SUI>", uint64(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeTag, func(t *testing.T) {
+			_, err := suiptb.EncodePure(sui.MustNewTypeTag(tt.typeTag), tt.value)
+			require.ErrorIs(t, err, suiptb.ErrPureTypeMismatch)
+		})
+	}
+	require.False(t, suiptb.IsPureType(sui.MustNewTypeTag("0x2::coin::Coin<0x2::sui::SUI>")))
+}
@@ -0,0 +1,24 @@
+package suiptb
+
+// Result is the Argument of a command result, which is a Move value of the Go type T, e.g. the results
+// returned by the generated Move bindings. T is only a hint for decoding, and is not checked.
+type Result[T any] struct {
+	Argument
+}
+
+// NewResult wraps the result argument
+func NewResult[T any](arg Argument) Result[T] {
+	return Result[T]{Argument: arg}
+}
+
+// NestedResults splits the result of a command, which returns n values, into n nested results
+func NestedResults(arg Argument, n int) []Argument {
+	if arg.Result == nil {
+		return nil
+	}
+	results := make([]Argument, n)
+	for i := range results {
+		results[i] = Argument{NestedResult: &NestedResult{Cmd: *arg.Result, Result: uint16(i)}}
+	}
+	return results
+}
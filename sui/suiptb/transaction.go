@@ -2,6 +2,7 @@ package suiptb
 
 import (
 	"github.com/pattonkan/sui-go/sui"
+	"golang.org/x/crypto/blake2b"
 )
 
 var (
@@ -24,6 +25,14 @@ type TransactionData struct {
 
 func (t TransactionData) IsBcsEnum() {}
 
+// TransactionDigest returns the digest of the BCS encoded TransactionData, which is known before the
+// transaction is submitted
+func TransactionDigest(txBytes []byte) *sui.TransactionDigest {
+	hash := blake2b.Sum256(append([]byte("TransactionData::"), txBytes...))
+	digest := sui.TransactionDigest(hash[:])
+	return &digest
+}
+
 type TransactionDataV1 struct {
 	Kind       TransactionKind
 	Sender     sui.Address
@@ -0,0 +1,286 @@
+package suiptb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// UnmarshalBCS decodes the BCS encoded `TransactionData`, so `bcs.Unmarshal(txBytes, &txData)` works.
+// go-bcs can't decode into the nil pointers to enums, like `CallArg.Object` and `TypeTag.Vector`,
+// nor into the nil `*sui.EmptyEnum` variants, so the enums are decoded here by hand.
+// Only the programmable transactions are supported, which are the ones users can submit.
+func (t *TransactionData) UnmarshalBCS(r io.Reader) (int, error) {
+	if t == nil {
+		return 0, errors.New("can't decode into nil TransactionData")
+	}
+	d := &txDecoder{r: r, bcs: bcs.NewDecoder(r)}
+	if variant := d.enumIndex(); d.err == nil && variant != 0 {
+		return d.n, fmt.Errorf("unsupported TransactionData version: %d", variant)
+	}
+	v1 := &TransactionDataV1{}
+	if err := d.transactionKind(&v1.Kind); err != nil {
+		return d.n, err
+	}
+	d.decode(&v1.Sender)
+	d.decode(&v1.GasData)
+	v1.Expiration = d.expiration()
+	if d.err != nil {
+		return d.n, fmt.Errorf("can't decode TransactionData: %w", d.err)
+	}
+	t.V1 = v1
+	return d.n, nil
+}
+
+// UnmarshalBCS decodes the BCS encoded `TransactionKind`, e.g. the transaction kind sent to a sponsor.
+// Only the programmable transactions are supported.
+func (t *TransactionKind) UnmarshalBCS(r io.Reader) (int, error) {
+	if t == nil {
+		return 0, errors.New("can't decode into nil TransactionKind")
+	}
+	d := &txDecoder{r: r, bcs: bcs.NewDecoder(r)}
+	var kind TransactionKind
+	if err := d.transactionKind(&kind); err != nil {
+		return d.n, err
+	}
+	if d.err != nil {
+		return d.n, fmt.Errorf("can't decode TransactionKind: %w", d.err)
+	}
+	*t = kind
+	return d.n, nil
+}
+
+// txDecoder keeps the first error and the number of bytes read
+type txDecoder struct {
+	r   io.Reader
+	bcs *bcs.Decoder
+	n   int
+	err error
+}
+
+func (d *txDecoder) decode(v any) {
+	if d.err != nil {
+		return
+	}
+	k, err := d.bcs.Decode(v)
+	d.n += k
+	d.err = err
+}
+
+func (d *txDecoder) uleb128() int {
+	if d.err != nil {
+		return 0
+	}
+	v, k, err := bcs.ULEB128Decode[int](d.r)
+	d.n += k
+	d.err = err
+	return v
+}
+
+func (d *txDecoder) enumIndex() int {
+	return d.uleb128()
+}
+
+func (d *txDecoder) length() int {
+	length := d.uleb128()
+	// a length can't be larger than the remaining bytes, which guards against huge allocations
+	if d.err == nil && length > 1<<20 {
+		d.err = fmt.Errorf("invalid length: %d", length)
+	}
+	return length
+}
+
+func (d *txDecoder) transactionKind(kind *TransactionKind) error {
+	if variant := d.enumIndex(); d.err == nil && variant != 0 {
+		return fmt.Errorf("unsupported TransactionKind: %d", variant)
+	}
+	kind.ProgrammableTransaction = d.programmableTransaction()
+	return nil
+}
+
+func (d *txDecoder) programmableTransaction() *ProgrammableTransaction {
+	pt := &ProgrammableTransaction{}
+	inputs := d.length()
+	for i := 0; i < inputs && d.err == nil; i++ {
+		pt.Inputs = append(pt.Inputs, d.callArg())
+	}
+	commands := d.length()
+	for i := 0; i < commands && d.err == nil; i++ {
+		pt.Commands = append(pt.Commands, d.command())
+	}
+	return pt
+}
+
+func (d *txDecoder) callArg() CallArg {
+	switch variant := d.enumIndex(); {
+	case d.err != nil:
+		return CallArg{}
+	case variant == 0:
+		var pure []byte
+		d.decode(&pure)
+		if pure == nil {
+			pure = []byte{}
+		}
+		return CallArg{Pure: &pure}
+	case variant == 1:
+		obj := &ObjectArg{}
+		d.decode(obj)
+		return CallArg{Object: obj}
+	default:
+		d.err = fmt.Errorf("unknown CallArg variant: %d", variant)
+		return CallArg{}
+	}
+}
+
+func (d *txDecoder) command() Command {
+	switch variant := d.enumIndex(); {
+	case d.err != nil:
+		return Command{}
+	case variant == 0:
+		moveCall := &ProgrammableMoveCall{Package: &sui.PackageId{}}
+		d.decode(moveCall.Package)
+		d.decode(&moveCall.Module)
+		d.decode(&moveCall.Function)
+		moveCall.TypeArguments = d.typeTags()
+		moveCall.Arguments = d.arguments()
+		return Command{MoveCall: moveCall}
+	case variant == 1:
+		transferObjects := &ProgrammableTransferObjects{}
+		transferObjects.Objects = d.arguments()
+		transferObjects.Address = d.argument()
+		return Command{TransferObjects: transferObjects}
+	case variant == 2:
+		splitCoins := &ProgrammableSplitCoins{}
+		splitCoins.Coin = d.argument()
+		splitCoins.Amounts = d.arguments()
+		return Command{SplitCoins: splitCoins}
+	case variant == 3:
+		mergeCoins := &ProgrammableMergeCoins{}
+		mergeCoins.Destination = d.argument()
+		mergeCoins.Sources = d.arguments()
+		return Command{MergeCoins: mergeCoins}
+	case variant == 4:
+		publish := &ProgrammablePublish{}
+		d.decode(publish)
+		return Command{Publish: publish}
+	case variant == 5:
+		makeMoveVec := &ProgrammableMakeMoveVec{}
+		var isSome byte
+		d.decode(&isSome)
+		if isSome != 0 {
+			makeMoveVec.Type = d.typeTag()
+		}
+		makeMoveVec.Objects = d.arguments()
+		return Command{MakeMoveVec: makeMoveVec}
+	case variant == 6:
+		upgrade := &ProgrammableUpgrade{PackageId: &sui.PackageId{}}
+		d.decode(&upgrade.Modules)
+		d.decode(&upgrade.Dependencies)
+		d.decode(upgrade.PackageId)
+		upgrade.Ticket = d.argument()
+		return Command{Upgrade: upgrade}
+	default:
+		d.err = fmt.Errorf("unknown Command variant: %d", variant)
+		return Command{}
+	}
+}
+
+func (d *txDecoder) arguments() []Argument {
+	var args []Argument
+	length := d.length()
+	for i := 0; i < length && d.err == nil; i++ {
+		args = append(args, d.argument())
+	}
+	return args
+}
+
+func (d *txDecoder) argument() Argument {
+	switch variant := d.enumIndex(); {
+	case d.err != nil:
+		return Argument{}
+	case variant == 0:
+		return Argument{GasCoin: &sui.EmptyEnum{}}
+	case variant == 1:
+		var input uint16
+		d.decode(&input)
+		return Argument{Input: &input}
+	case variant == 2:
+		var result uint16
+		d.decode(&result)
+		return Argument{Result: &result}
+	case variant == 3:
+		nested := &NestedResult{}
+		d.decode(nested)
+		return Argument{NestedResult: nested}
+	default:
+		d.err = fmt.Errorf("unknown Argument variant: %d", variant)
+		return Argument{}
+	}
+}
+
+func (d *txDecoder) expiration() TransactionExpiration {
+	switch variant := d.enumIndex(); {
+	case d.err != nil:
+		return TransactionExpiration{}
+	case variant == 0:
+		return TransactionExpiration{None: &sui.EmptyEnum{}}
+	case variant == 1:
+		var epoch sui.EpochId
+		d.decode(&epoch)
+		return TransactionExpiration{Epoch: &epoch}
+	default:
+		d.err = fmt.Errorf("unknown TransactionExpiration variant: %d", variant)
+		return TransactionExpiration{}
+	}
+}
+
+func (d *txDecoder) typeTags() []sui.TypeTag {
+	var tags []sui.TypeTag
+	length := d.length()
+	for i := 0; i < length && d.err == nil; i++ {
+		tags = append(tags, *d.typeTag())
+	}
+	return tags
+}
+
+func (d *txDecoder) typeTag() *sui.TypeTag {
+	variant := d.enumIndex()
+	if d.err != nil {
+		return &sui.TypeTag{}
+	}
+	switch variant {
+	case 0:
+		return &sui.TypeTag{Bool: &sui.EmptyEnum{}}
+	case 1:
+		return &sui.TypeTag{U8: &sui.EmptyEnum{}}
+	case 2:
+		return &sui.TypeTag{U64: &sui.EmptyEnum{}}
+	case 3:
+		return &sui.TypeTag{U128: &sui.EmptyEnum{}}
+	case 4:
+		return &sui.TypeTag{Address: &sui.EmptyEnum{}}
+	case 5:
+		return &sui.TypeTag{Signer: &sui.EmptyEnum{}}
+	case 6:
+		return &sui.TypeTag{Vector: d.typeTag()}
+	case 7:
+		structTag := &sui.StructTag{Address: &sui.Address{}}
+		d.decode(structTag.Address)
+		d.decode(&structTag.Module)
+		d.decode(&structTag.Name)
+		structTag.TypeParams = d.typeTags()
+		return &sui.TypeTag{Struct: structTag}
+	case 8:
+		return &sui.TypeTag{U16: &sui.EmptyEnum{}}
+	case 9:
+		return &sui.TypeTag{U32: &sui.EmptyEnum{}}
+	case 10:
+		return &sui.TypeTag{U256: &sui.EmptyEnum{}}
+	default:
+		d.err = fmt.Errorf("unknown TypeTag variant: %d", variant)
+		return &sui.TypeTag{}
+	}
+}
@@ -0,0 +1,81 @@
+package suiptb_test
+
+import (
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+func TestTransactionDataUnmarshalBCS(t *testing.T) {
+	sender := sui.MustAddressFromHex("0x1a02d61c6434b4d0ff252a880c04050b5f27c8b574026c98dd72268865c0ede5")
+	recipient := sui.MustAddressFromHex("0x2")
+	objRef := &sui.ObjectRef{
+		ObjectId: sui.MustObjectIdFromHex("0x1234"),
+		Version:  3,
+		Digest:   sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"),
+	}
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.MustObj(suiptb.SuiSystemMutObj)
+	vecTag, err := sui.NewTypeTag("vector<0x2::coin::Coin<0x2::sui::SUI>>")
+	require.NoError(t, err)
+	u64Tag, err := sui.NewTypeTag("u64")
+	require.NoError(t, err)
+	ptb.ProgrammableMoveCall(
+		sui.MustPackageIdFromHex("0x3"),
+		"module",
+		"function",
+		[]sui.TypeTag{*vecTag, *u64Tag},
+		[]suiptb.Argument{ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: objRef}), ptb.MustPure(uint64(42))},
+	)
+	coins := ptb.Command(suiptb.Command{
+		SplitCoins: &suiptb.ProgrammableSplitCoins{
+			Coin:    suiptb.Argument{GasCoin: &sui.EmptyEnum{}},
+			Amounts: []suiptb.Argument{ptb.MustPure(uint64(100))},
+		},
+	})
+	ptb.Command(suiptb.Command{
+		MakeMoveVec: &suiptb.ProgrammableMakeMoveVec{
+			Type:    u64Tag,
+			Objects: []suiptb.Argument{ptb.MustPure(uint64(1))},
+		},
+	})
+	ptb.TransferArg(recipient, coins)
+	tx := suiptb.NewTransactionData(sender, ptb.Finish(), []*sui.ObjectRef{objRef}, 1000000, 1000)
+
+	txBytes, err := bcs.Marshal(tx)
+	require.NoError(t, err)
+
+	var decoded suiptb.TransactionData
+	n, err := bcs.Unmarshal(txBytes, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, len(txBytes), n)
+	require.Equal(t, tx, decoded)
+
+	reencoded, err := bcs.Marshal(decoded)
+	require.NoError(t, err)
+	require.Equal(t, txBytes, reencoded)
+
+	_, err = bcs.Unmarshal(txBytes[:len(txBytes)-3], &decoded)
+	require.Error(t, err)
+}
+
+func TestTransactionKindUnmarshalBCS(t *testing.T) {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	amount := uint64(100)
+	require.NoError(t, ptb.TransferSui(sui.MustAddressFromHex("0x2"), &amount))
+	pt := ptb.Finish()
+	kind := suiptb.TransactionKind{ProgrammableTransaction: &pt}
+
+	kindBytes, err := bcs.Marshal(kind)
+	require.NoError(t, err)
+
+	var decoded suiptb.TransactionKind
+	_, err = bcs.Unmarshal(kindBytes, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, kind, decoded)
+}
@@ -0,0 +1,79 @@
+package suiptb
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// UnresolvedObject is an object input known only by its id. It is resolved into an ObjectArg with the version,
+// the digest and the kind of the input from the object on chain, e.g. by `suiclient.ClientImpl.ResolveObjects()`.
+type UnresolvedObject struct {
+	Id *sui.ObjectId
+	// the input is a Receiving object, which is otherwise inferred from the Move functions taking it
+	Receiving bool
+	// the mutability of a shared object, which is inferred from the Move functions taking it if nil
+	Mutable *bool
+}
+
+// ObjId adds the object as an input by its id only. The input must be resolved before `Finish()`.
+func (p *ProgrammableTransactionBuilder) ObjId(id *sui.ObjectId) Argument {
+	return p.unresolvedObj(&UnresolvedObject{Id: id})
+}
+
+// ReceivingObjId adds the object to be received by another object as an input by its id only
+func (p *ProgrammableTransactionBuilder) ReceivingObjId(id *sui.ObjectId) Argument {
+	return p.unresolvedObj(&UnresolvedObject{Id: id, Receiving: true})
+}
+
+// SharedObjId adds the shared object as an input by its id only, with the given mutability
+func (p *ProgrammableTransactionBuilder) SharedObjId(id *sui.ObjectId, mutable bool) Argument {
+	return p.unresolvedObj(&UnresolvedObject{Id: id, Mutable: &mutable})
+}
+
+func (p *ProgrammableTransactionBuilder) unresolvedObj(obj *UnresolvedObject) Argument {
+	key := BuilderArg{Object: obj.Id}
+	if i, ok := p.Inputs.Find(key); ok {
+		if old, ok := p.unresolved[*obj.Id]; ok {
+			old.Receiving = old.Receiving || obj.Receiving
+			if obj.Mutable != nil && (old.Mutable == nil || *obj.Mutable) {
+				old.Mutable = obj.Mutable
+			}
+		}
+		idx := uint16(i)
+		return Argument{Input: &idx}
+	}
+	if p.unresolved == nil {
+		p.unresolved = make(map[sui.ObjectId]*UnresolvedObject)
+	}
+	p.unresolved[*obj.Id] = obj
+	// the placeholder keeps the position of the input until it is resolved
+	i := uint16(p.Inputs.InsertFull(key, CallArg{Object: &ObjectArg{}}))
+	return Argument{Input: &i}
+}
+
+// UnresolvedObjects returns the inputs added by their ids only, in the order of the inputs
+func (p *ProgrammableTransactionBuilder) UnresolvedObjects() []*UnresolvedObject {
+	objs := make([]*UnresolvedObject, 0, len(p.unresolved))
+	for _, obj := range p.unresolved {
+		objs = append(objs, obj)
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		a, _ := p.Inputs.Find(BuilderArg{Object: objs[i].Id})
+		b, _ := p.Inputs.Find(BuilderArg{Object: objs[j].Id})
+		return a < b
+	})
+	return objs
+}
+
+// ResolveObject replaces the input added by its id with the resolved ObjectArg
+func (p *ProgrammableTransactionBuilder) ResolveObject(objArg ObjectArg) error {
+	id := objArg.id()
+	if _, ok := p.unresolved[*id]; !ok {
+		return fmt.Errorf("object %s is not an unresolved input", id)
+	}
+	p.Inputs.Insert(BuilderArg{Object: id}, CallArg{Object: &objArg})
+	delete(p.unresolved, *id)
+	return nil
+}
@@ -0,0 +1,60 @@
+package suiptb_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+func TestUnresolvedObjects(t *testing.T) {
+	owned := sui.MustObjectIdFromHex("0x11")
+	shared := sui.MustObjectIdFromHex("0x22")
+	ref := &sui.ObjectRef{ObjectId: owned, Version: 3, Digest: sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi")}
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.MustPure(uint64(1))
+	sharedArg := ptb.SharedObjId(shared, false)
+	ownedArg := ptb.ObjId(owned)
+	require.Equal(t, uint16(1), *sharedArg.Input)
+	require.Equal(t, uint16(2), *ownedArg.Input)
+
+	// the same object is the same input, and the mutability is the strongest of the uses
+	require.Equal(t, sharedArg, ptb.SharedObjId(shared, true))
+	unresolved := ptb.UnresolvedObjects()
+	require.Len(t, unresolved, 2)
+	require.Equal(t, shared, unresolved[0].Id)
+	require.True(t, *unresolved[0].Mutable)
+	require.Equal(t, owned, unresolved[1].Id)
+
+	// an unresolved input would be encoded as an empty object
+	require.PanicsWithValue(t, "2 object inputs are unresolved, e.g. "+shared.String(), func() { ptb.Finish() })
+	_, err := ptb.FinishChecked()
+	require.ErrorIs(t, err, suiptb.ErrUnresolvedObjects)
+
+	// adding the known object resolves the input in place
+	require.Equal(t, ownedArg, ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: ref}))
+	require.NoError(t, ptb.ResolveObject(suiptb.ObjectArg{SharedObject: &suiptb.SharedObjectArg{Id: shared, InitialSharedVersion: 5, Mutable: true}}))
+	require.Empty(t, ptb.UnresolvedObjects())
+	require.Error(t, ptb.ResolveObject(suiptb.ObjectArg{ImmOrOwnedObject: ref}))
+
+	pt, err := ptb.FinishChecked()
+	require.NoError(t, err)
+	require.Len(t, pt.Inputs, 3)
+	require.Equal(t, uint64(5), uint64(pt.Inputs[1].Object.SharedObject.InitialSharedVersion))
+	require.Equal(t, ref, pt.Inputs[2].Object.ImmOrOwnedObject)
+}
+
+func TestUnresolvedSharedObjectMutability(t *testing.T) {
+	shared := sui.MustObjectIdFromHex("0x22")
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	arg := ptb.SharedObjId(shared, false)
+	ptb.SharedObjId(shared, true)
+
+	// the object added as immutable resolves the input, which stays mutable
+	require.Equal(t, arg, ptb.MustObj(suiptb.ObjectArg{SharedObject: &suiptb.SharedObjectArg{Id: shared, InitialSharedVersion: 5}}))
+	pt := ptb.Finish()
+	require.Equal(t, &suiptb.SharedObjectArg{Id: shared, InitialSharedVersion: 5, Mutable: true}, pt.Inputs[0].Object.SharedObject)
+}
@@ -6,8 +6,9 @@ import (
 )
 
 type ClientImpl struct {
-	http      *conn.HttpClient
-	websocket *conn.WebsocketClient
+	http        *conn.HttpClient
+	websocket   *conn.WebsocketClient
+	objectCache *ObjectRefCache
 }
 
 func NewClient(url string) *ClientImpl {
@@ -47,6 +48,17 @@ func (i *ClientImpl) WithWebsocket(url string) {
 	i.websocket = conn.NewWebsocketClient(url)
 }
 
+// WithObjectRefCache makes the client keep the refs of the objects in the cache, which is updated with the
+// effects of the executed transactions, and is looked up first when the objects of a transaction are resolved
+// and its gas coins are selected. The effects are applied only if the response shows them.
+func (i *ClientImpl) WithObjectRefCache(cache *ObjectRefCache) {
+	i.objectCache = cache
+}
+
+func (i *ClientImpl) ObjectRefCache() *ObjectRefCache {
+	return i.objectCache
+}
+
 func NewSuiWebsocketClient(url string) *ClientImpl {
 	return &ClientImpl{
 		websocket: conn.NewWebsocketClient(url),
@@ -54,5 +54,7 @@ func (s *ClientImpl) ExecuteTransactionBlock(
 	req *ExecuteTransactionBlockRequest,
 ) (*SuiTransactionBlockResponse, error) {
 	resp := SuiTransactionBlockResponse{}
-	return &resp, s.http.CallContext(ctx, &resp, executeTransactionBlock, req.TxDataBytes, req.Signatures, req.Options, req.RequestType)
+	err := s.http.CallContext(ctx, &resp, executeTransactionBlock, req.TxDataBytes, req.Signatures, req.Options, req.RequestType)
+	s.updateObjectCache(req.TxDataBytes, &resp, err)
+	return &resp, err
 }
@@ -5,10 +5,13 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/fardream/go-bcs/bcs"
 	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
 	"github.com/pattonkan/sui-go/suiclient"
 	"github.com/pattonkan/sui-go/suiclient/conn"
 	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/bls12381"
 	"github.com/stretchr/testify/require"
 )
 
@@ -78,3 +81,68 @@ func TestRequestWithdrawDelegation(t *testing.T) {
 	require.Equal(t, "", simulate.Effects.Data.V1.Status.Error)
 	require.True(t, simulate.Effects.Data.IsSuccess())
 }
+
+func TestBCS_RequestAddValidatorCandidate(t *testing.T) {
+	keypair, err := bls12381.GenerateKeyPair()
+	require.NoError(t, err)
+	pop, err := keypair.ProofOfPossession(suisigner.TEST_ADDRESS)
+	require.NoError(t, err)
+
+	candidate := &suiclient.ValidatorCandidate{
+		ProtocolPubKey:    keypair.PublicKey.Bytes(),
+		NetworkPubKey:     make([]byte, 32),
+		WorkerPubKey:      make([]byte, 32),
+		ProofOfPossession: pop.Bytes(),
+		Name:              "validator",
+		NetAddress:        "/dns/validator.example.com/tcp/8080/http",
+		P2pAddress:        "/dns/validator.example.com/udp/8084",
+		PrimaryAddress:    "/dns/validator.example.com/udp/8081",
+		WorkerAddress:     "/dns/validator.example.com/udp/8082",
+		GasPrice:          1000,
+		CommissionRate:    200,
+	}
+	gas := []*sui.ObjectRef{{
+		ObjectId: sui.MustObjectIdFromHex("0x1234"),
+		Version:  1,
+		Digest:   sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"),
+	}}
+	txBytes, err := suiclient.BCS_RequestAddValidatorCandidate(
+		suisigner.TEST_ADDRESS,
+		candidate,
+		gas,
+		suiclient.DefaultGasBudget,
+		suiclient.DefaultGasPrice,
+	)
+	require.NoError(t, err)
+
+	var tx suiptb.TransactionData
+	_, err = bcs.Unmarshal(txBytes, &tx)
+	require.NoError(t, err)
+	require.Equal(t, *suisigner.TEST_ADDRESS, tx.V1.Sender)
+	pt := tx.V1.Kind.ProgrammableTransaction
+	require.Len(t, pt.Commands, 1)
+	call := pt.Commands[0].MoveCall
+	require.Equal(t, sui.SuiPackageIdSuiSystem, call.Package)
+	require.Equal(t, sui.SuiSystemModuleName, call.Module)
+	require.Equal(t, sui.Identifier("request_add_validator_candidate"), call.Function)
+
+	// the system state and the fields of the candidate in the order of the Move function
+	require.Len(t, call.Arguments, 15)
+	require.Equal(t, suiptb.SuiSystemMutObj, *pt.Inputs[*call.Arguments[0].Input].Object)
+	values := []any{
+		candidate.ProtocolPubKey, candidate.NetworkPubKey, candidate.WorkerPubKey, candidate.ProofOfPossession,
+		candidate.Name, candidate.Description, candidate.ImageUrl, candidate.ProjectUrl,
+		candidate.NetAddress, candidate.P2pAddress, candidate.PrimaryAddress, candidate.WorkerAddress,
+		candidate.GasPrice, candidate.CommissionRate,
+	}
+	for i, value := range values {
+		pure, err := bcs.Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, pure, *pt.Inputs[*call.Arguments[i+1].Input].Pure, "argument %d", i+1)
+	}
+	// the pure inputs with the same bytes share one input: the network and worker keys, and the empty
+	// description, image url and project url
+	require.Len(t, pt.Inputs, 12)
+	require.Equal(t, call.Arguments[2], call.Arguments[3])
+	require.Equal(t, call.Arguments[6], call.Arguments[8])
+}
@@ -14,3 +14,9 @@ func (err HTTPError) Error() string {
 	}
 	return fmt.Sprintf("%v: %s", err.Status, err.Body)
 }
+
+// RPCError is an error returned by the JSON-RPC server, unlike the errors of the transport
+type RPCError interface {
+	error
+	ErrorCode() int
+}
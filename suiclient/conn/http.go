@@ -91,7 +91,7 @@ func (c *HttpClient) CallContext(ctx context.Context, result interface{}, method
 		return fmt.Errorf("could not unmarshal response body: %w", err)
 	}
 	if respmsg.Error != nil {
-		return fmt.Errorf("sui returned error: %s", respmsg.Error)
+		return fmt.Errorf("sui returned error: %w", respmsg.Error)
 	}
 	if len(respmsg.Result) == 0 {
 		return ErrNoResult
@@ -0,0 +1,193 @@
+package suiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+var ErrDevInspectFailed = errors.New("dev inspect failed")
+
+// ReturnValueType is a value returned by a command, which is the JSON pair `[bcsBytes, typeString]`
+type ReturnValueType struct {
+	Bcs  []byte
+	Type *sui.TypeTag
+}
+
+// MutableReferenceOutputType is the value of a `&mut` argument after a command, which is the JSON
+// triple `[argument, bcsBytes, typeString]`
+type MutableReferenceOutputType struct {
+	Argument suiptb.Argument
+	Bcs      []byte
+	Type     *sui.TypeTag
+}
+
+func (r *ReturnValueType) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("can't decode return value: %w", err)
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("return value has %d elements, expected [bcs, type]", len(raw))
+	}
+	bcsBytes, typeTag, err := parseBcsAndType(raw[0], raw[1])
+	if err != nil {
+		return fmt.Errorf("can't decode return value: %w", err)
+	}
+	*r = ReturnValueType{Bcs: bcsBytes, Type: typeTag}
+	return nil
+}
+
+func (o *MutableReferenceOutputType) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("can't decode mutable reference output: %w", err)
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("mutable reference output has %d elements, expected [argument, bcs, type]", len(raw))
+	}
+	arg, err := parseArgument(raw[0])
+	if err != nil {
+		return fmt.Errorf("can't decode mutable reference output: %w", err)
+	}
+	bcsBytes, typeTag, err := parseBcsAndType(raw[1], raw[2])
+	if err != nil {
+		return fmt.Errorf("can't decode mutable reference output: %w", err)
+	}
+	*o = MutableReferenceOutputType{Argument: arg, Bcs: bcsBytes, Type: typeTag}
+	return nil
+}
+
+func parseBcsAndType(rawBcs json.RawMessage, rawType json.RawMessage) ([]byte, *sui.TypeTag, error) {
+	var ints []uint8
+	if err := json.Unmarshal(rawBcs, &ints); err != nil {
+		return nil, nil, fmt.Errorf("invalid bcs bytes: %w", err)
+	}
+	var typeString string
+	if err := json.Unmarshal(rawType, &typeString); err != nil {
+		return nil, nil, fmt.Errorf("invalid type: %w", err)
+	}
+	typeTag, err := sui.NewTypeTag(typeString)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid type %q: %w", typeString, err)
+	}
+	return ints, typeTag, nil
+}
+
+// parseArgument parses the JSON of an argument, e.g. `"GasCoin"`, `{"Input":0}` or `{"NestedResult":[0,1]}`
+func parseArgument(data json.RawMessage) (suiptb.Argument, error) {
+	var gasCoin string
+	if err := json.Unmarshal(data, &gasCoin); err == nil {
+		if gasCoin != "GasCoin" {
+			return suiptb.Argument{}, fmt.Errorf("invalid argument: %s", gasCoin)
+		}
+		return suiptb.Argument{GasCoin: &sui.EmptyEnum{}}, nil
+	}
+	var arg struct {
+		Input        *uint16
+		Result       *uint16
+		NestedResult []uint16
+	}
+	if err := json.Unmarshal(data, &arg); err != nil {
+		return suiptb.Argument{}, fmt.Errorf("invalid argument: %w", err)
+	}
+	switch {
+	case arg.Input != nil:
+		return suiptb.Argument{Input: arg.Input}, nil
+	case arg.Result != nil:
+		return suiptb.Argument{Result: arg.Result}, nil
+	case len(arg.NestedResult) == 2:
+		return suiptb.Argument{NestedResult: &suiptb.NestedResult{Cmd: arg.NestedResult[0], Result: arg.NestedResult[1]}}, nil
+	default:
+		return suiptb.Argument{}, fmt.Errorf("invalid argument: %s", data)
+	}
+}
+
+// Decode decodes the BCS bytes of the return value into v, which must consume all the bytes
+func (r *ReturnValueType) Decode(v any) error {
+	n, err := bcs.Unmarshal(r.Bcs, v)
+	if err != nil {
+		return fmt.Errorf("can't decode %s: %w", r.Type, err)
+	}
+	if n != len(r.Bcs) {
+		return fmt.Errorf("can't decode %s: %d trailing bytes", r.Type, len(r.Bcs)-n)
+	}
+	return nil
+}
+
+// Value decodes the return value into a new value of the Go type of its Move type, which is
+// a primitive, a vector, a string, an address or a struct registered by movebcs.Register()
+func (r *ReturnValueType) Value() (any, error) {
+	v, err := movebcs.NewValue(r.Type)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Decode(v); err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(v).Elem().Interface(), nil
+}
+
+// DevInspectMoveCall runs a single Move function by DevInspect, and returns its return values. The arguments
+// are the ones of AddMoveCall(), and the objects given by id are resolved. A nil sender is the zero address.
+func (s *ClientImpl) DevInspectMoveCall(
+	ctx context.Context,
+	sender *sui.Address,
+	packageId *sui.PackageId,
+	module sui.Identifier,
+	function sui.Identifier,
+	typeArgs []sui.TypeTag,
+	args ...any,
+) ([]ReturnValueType, error) {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	if _, err := s.AddMoveCall(ctx, ptb, packageId, module, function, typeArgs, args...); err != nil {
+		return nil, err
+	}
+	if err := s.ResolveObjects(ctx, ptb); err != nil {
+		return nil, err
+	}
+	pt, err := ptb.FinishChecked()
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.devInspect(ctx, sender, pt)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("dev inspect returned %d results for 1 command", len(results))
+	}
+	return results[0].ReturnValues, nil
+}
+
+// devInspect runs the PTB by DevInspect, and returns the results of the commands
+func (s *ClientImpl) devInspect(ctx context.Context, sender *sui.Address, pt suiptb.ProgrammableTransaction) ([]ExecutionResultType, error) {
+	if sender == nil {
+		sender = &sui.Address{}
+	}
+	kindBytes, err := bcs.Marshal(suiptb.TransactionKind{ProgrammableTransaction: &pt})
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal transaction kind: %w", err)
+	}
+	resp, err := s.DevInspectTransactionBlock(ctx, &DevInspectTransactionBlockRequest{
+		SenderAddress: sender,
+		TxKindBytes:   kindBytes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		if failure, ok := ParseExecutionFailure(resp.Error); ok {
+			return nil, fmt.Errorf("%w: %w", ErrDevInspectFailed, s.decodeFailure(ctx, failure, &pt))
+		}
+		return nil, fmt.Errorf("%w: %s", ErrDevInspectFailed, resp.Error)
+	}
+	return resp.Results, nil
+}
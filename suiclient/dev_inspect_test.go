@@ -0,0 +1,106 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// balance<T>(pool: &Pool<T>): (u64, Coin<T>)
+const balanceFunction = `{
+	"visibility":"Public","isEntry":false,
+	"typeParameters":[{"abilities":[]}],
+	"parameters":[{"Reference":{"Struct":{"address":"0x1234","module":"pool","name":"Pool","typeArguments":[{"TypeParameter":0}]}}}],
+	"return":["U64",{"Struct":{"address":"0x2","module":"coin","name":"Coin","typeArguments":[{"TypeParameter":0}]}}]
+}`
+
+func TestDevInspectResults(t *testing.T) {
+	coin := movebcs.MoveCoin{Id: sui.MustObjectIdFromHex("0x99"), Balance: 7}
+	coinBytes, err := bcs.Marshal(coin)
+	require.NoError(t, err)
+	// the BCS bytes are arrays of numbers
+	coinInts, err := json.Marshal(bytesToInts(coinBytes))
+	require.NoError(t, err)
+	results := fmt.Sprintf(`[{
+		"mutableReferenceOutputs": [[{"Input": 0}, [1], "bool"]],
+		"returnValues": [[[42, 0, 0, 0, 0, 0, 0, 0], "u64"], [%s, "0x2::coin::Coin<0x2::sui::SUI>"]]
+	}]`, coinInts)
+
+	pool := sui.MustObjectIdFromHex("0x55")
+	rpc, client := newFakeRPC(t)
+	rpc.result("sui_getNormalizedMoveFunction", balanceFunction)
+	rpc.result("sui_getNormalizedMoveStruct", `{"abilities":{"abilities":["Drop"]},"typeParameters":[],"fields":[]}`)
+	rpc.result("sui_multiGetObjects", []map[string]any{{"data": map[string]any{
+		"objectId": pool.String(), "version": "3", "digest": testDigest.String(),
+		"owner": map[string]any{"Shared": map[string]any{"initial_shared_version": 2}},
+	}}})
+	var sender sui.Address
+	rpc.handle("sui_devInspectTransactionBlock", func(params []json.RawMessage) (any, error) {
+		require.NoError(t, json.Unmarshal(params[0], &sender))
+		var kindBytes sui.Base64Data
+		require.NoError(t, json.Unmarshal(params[1], &kindBytes))
+		var kind suiptb.TransactionKind
+		_, err := bcs.Unmarshal(kindBytes, &kind)
+		require.NoError(t, err)
+		require.Len(t, kind.ProgrammableTransaction.Commands, 1)
+		// the pool is only borrowed immutably
+		require.False(t, kind.ProgrammableTransaction.Inputs[0].Object.SharedObject.Mutable)
+		return map[string]any{
+			"effects": map[string]any{"messageVersion": "v1", "status": map[string]any{"status": "success"}},
+			"events":  []any{},
+			"results": json.RawMessage(results),
+		}, nil
+	})
+
+	values, err := client.DevInspectMoveCall(context.Background(), nil, sui.MustPackageIdFromHex("0x1234"), "pool", "balance",
+		[]sui.TypeTag{*sui.MustNewTypeTag("0x2::sui::SUI")}, pool)
+	require.NoError(t, err)
+	require.Equal(t, sui.Address{}, sender)
+	require.Len(t, values, 2)
+	require.Equal(t, "u64", values[0].Type.String())
+	require.Equal(t, sui.MustNewTypeTag("0x2::coin::Coin<0x2::sui::SUI>"), values[1].Type)
+
+	amount, err := values[0].Value()
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), amount)
+	decodedCoin, err := values[1].Value()
+	require.NoError(t, err)
+	require.Equal(t, coin, decodedCoin)
+	var balance uint64
+	require.NoError(t, values[0].Decode(&balance))
+	require.Equal(t, uint64(42), balance)
+	var short uint32
+	require.ErrorContains(t, values[0].Decode(&short), "trailing bytes")
+
+	var resp suiclient.DevInspectTransactionBlockResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"effects":{"messageVersion":"v1","status":{"status":"success"}},"events":[],"results":[{
+		"mutableReferenceOutputs":[["GasCoin",[1],"bool"],[{"NestedResult":[1,2]},[0],"bool"]],
+		"returnValues":[]}]}`), &resp))
+	outputs := resp.Results[0].MutableReferenceOutputs
+	require.NotNil(t, outputs[0].Argument.GasCoin)
+	require.Equal(t, &suiptb.NestedResult{Cmd: 1, Result: 2}, outputs[1].Argument.NestedResult)
+	require.Equal(t, []byte{0}, outputs[1].Bcs)
+
+	rpc.result("sui_devInspectTransactionBlock", `{"effects":{"messageVersion":"v1","status":{"status":"failure"}},"events":[],"error":"MoveAbort(...) in command 0"}`)
+	_, err = client.DevInspectMoveCall(context.Background(), nil, sui.MustPackageIdFromHex("0x1234"), "pool", "balance",
+		[]sui.TypeTag{*sui.MustNewTypeTag("0x2::sui::SUI")}, pool)
+	require.True(t, errors.Is(err, suiclient.ErrDevInspectFailed))
+}
+
+func bytesToInts(b []byte) []int {
+	ints := make([]int, len(b))
+	for i, v := range b {
+		ints[i] = int(v)
+	}
+	return ints
+}
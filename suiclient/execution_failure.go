@@ -0,0 +1,146 @@
+package suiclient
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ExecutionFailureKind is the variant of the ExecutionFailureStatus of a failed transaction
+type ExecutionFailureKind string
+
+const (
+	ExecutionFailureInsufficientGas                               ExecutionFailureKind = "InsufficientGas"
+	ExecutionFailureInvalidGasObject                              ExecutionFailureKind = "InvalidGasObject"
+	ExecutionFailureInvariantViolation                            ExecutionFailureKind = "InvariantViolation"
+	ExecutionFailureFeatureNotYetSupported                        ExecutionFailureKind = "FeatureNotYetSupported"
+	ExecutionFailureMoveObjectTooBig                              ExecutionFailureKind = "MoveObjectTooBig"
+	ExecutionFailureMovePackageTooBig                             ExecutionFailureKind = "MovePackageTooBig"
+	ExecutionFailureCircularObjectOwnership                       ExecutionFailureKind = "CircularObjectOwnership"
+	ExecutionFailureInsufficientCoinBalance                       ExecutionFailureKind = "InsufficientCoinBalance"
+	ExecutionFailureCoinBalanceOverflow                           ExecutionFailureKind = "CoinBalanceOverflow"
+	ExecutionFailurePublishErrorNonZeroAddress                    ExecutionFailureKind = "PublishErrorNonZeroAddress"
+	ExecutionFailureSuiMoveVerificationError                      ExecutionFailureKind = "SuiMoveVerificationError"
+	ExecutionFailureMovePrimitiveRuntimeError                     ExecutionFailureKind = "MovePrimitiveRuntimeError"
+	ExecutionFailureMoveAbort                                     ExecutionFailureKind = "MoveAbort"
+	ExecutionFailureVMVerificationOrDeserializationError          ExecutionFailureKind = "VMVerificationOrDeserializationError"
+	ExecutionFailureVMInvariantViolation                          ExecutionFailureKind = "VMInvariantViolation"
+	ExecutionFailureFunctionNotFound                              ExecutionFailureKind = "FunctionNotFound"
+	ExecutionFailureArityMismatch                                 ExecutionFailureKind = "ArityMismatch"
+	ExecutionFailureTypeArityMismatch                             ExecutionFailureKind = "TypeArityMismatch"
+	ExecutionFailureNonEntryFunctionInvoked                       ExecutionFailureKind = "NonEntryFunctionInvoked"
+	ExecutionFailureCommandArgumentError                          ExecutionFailureKind = "CommandArgumentError"
+	ExecutionFailureTypeArgumentError                             ExecutionFailureKind = "TypeArgumentError"
+	ExecutionFailureUnusedValueWithoutDrop                        ExecutionFailureKind = "UnusedValueWithoutDrop"
+	ExecutionFailureInvalidPublicFunctionReturnType               ExecutionFailureKind = "InvalidPublicFunctionReturnType"
+	ExecutionFailureInvalidTransferObject                         ExecutionFailureKind = "InvalidTransferObject"
+	ExecutionFailureEffectsTooLarge                               ExecutionFailureKind = "EffectsTooLarge"
+	ExecutionFailurePublishUpgradeMissingDependency               ExecutionFailureKind = "PublishUpgradeMissingDependency"
+	ExecutionFailurePublishUpgradeDependencyDowngrade             ExecutionFailureKind = "PublishUpgradeDependencyDowngrade"
+	ExecutionFailurePackageUpgradeError                           ExecutionFailureKind = "PackageUpgradeError"
+	ExecutionFailureWrittenObjectsTooLarge                        ExecutionFailureKind = "WrittenObjectsTooLarge"
+	ExecutionFailureCertificateDenied                             ExecutionFailureKind = "CertificateDenied"
+	ExecutionFailureSuiMoveVerificationTimedout                   ExecutionFailureKind = "SuiMoveVerificationTimedout"
+	ExecutionFailureSharedObjectOperationNotAllowed               ExecutionFailureKind = "SharedObjectOperationNotAllowed"
+	ExecutionFailureInputObjectDeleted                            ExecutionFailureKind = "InputObjectDeleted"
+	ExecutionFailureExecutionCancelledDueToSharedObjectCongestion ExecutionFailureKind = "ExecutionCancelledDueToSharedObjectCongestion"
+	ExecutionFailureAddressDeniedForCoin                          ExecutionFailureKind = "AddressDeniedForCoin"
+	ExecutionFailureCoinTypeGlobalPause                           ExecutionFailureKind = "CoinTypeGlobalPause"
+	ExecutionFailureExecutionCancelledDueToRandomnessUnavailable  ExecutionFailureKind = "ExecutionCancelledDueToRandomnessUnavailable"
+)
+
+// ExecutionFailure is the parsed error of a failed execution, e.g. `InsufficientGas` or
+// `CommandArgumentError { arg_idx: 0, kind: TypeMismatch } in command 1`
+type ExecutionFailure struct {
+	Kind ExecutionFailureKind
+	// the fields of the variant as the fullnode prints them, e.g. `{ arg_idx: 0, kind: TypeMismatch }`
+	Details string
+	// the index of the argument of a CommandArgumentError, or of the type argument of a TypeArgumentError
+	Argument *int
+	// the kind of a CommandArgumentError or a TypeArgumentError, e.g. `TypeMismatch` or `IndexOutOfBounds { idx: 2 }`
+	ArgumentKind string
+	// the index of the failed command, nil if the error doesn't tell
+	Command *int
+	// set if Kind is ExecutionFailureMoveAbort
+	MoveAbort *MoveAbortError
+	// the error returned by the fullnode
+	Raw string
+}
+
+func (e *ExecutionFailure) Error() string {
+	if e.MoveAbort != nil {
+		return e.MoveAbort.Error()
+	}
+	if e.Raw == "" {
+		return "execution failed"
+	}
+	return e.Raw
+}
+
+// Unwrap returns the *MoveAbortError of a Move abort, so errors.As() can take it
+func (e *ExecutionFailure) Unwrap() error {
+	if e.MoveAbort == nil {
+		return nil
+	}
+	return e.MoveAbort
+}
+
+// executionFailureRegex matches the Debug format of a variant: `Name`, `Name { .. }` or `Name(..)`
+var executionFailureRegex = regexp.MustCompile(`(?s)^([A-Z][A-Za-z0-9]*)( \{.*\}|\(.*\))?(?: in command (\d+))?$`)
+
+// argumentErrorRegex matches the fields of CommandArgumentError and TypeArgumentError
+var argumentErrorRegex = regexp.MustCompile(`(?s)^\{ (?:arg_idx|argument_idx): (\d+), kind: (.*) \}$`)
+
+// ParseExecutionFailure parses the error of an execution status or a DevInspect, and returns false if
+// the error isn't a variant in the Debug format, e.g. `Error checking transaction input objects: ..`.
+// The variants unknown to the SDK are kept in Kind too.
+func ParseExecutionFailure(errorString string) (*ExecutionFailure, bool) {
+	m := executionFailureRegex.FindStringSubmatch(strings.TrimSpace(errorString))
+	if m == nil {
+		return nil, false
+	}
+	failure := &ExecutionFailure{
+		Kind:    ExecutionFailureKind(m[1]),
+		Details: strings.TrimSpace(m[2]),
+		Raw:     errorString,
+	}
+	if m[3] != "" {
+		command, err := strconv.Atoi(m[3])
+		if err != nil {
+			return nil, false
+		}
+		failure.Command = &command
+	}
+	switch failure.Kind {
+	case ExecutionFailureCommandArgumentError, ExecutionFailureTypeArgumentError:
+		m := argumentErrorRegex.FindStringSubmatch(failure.Details)
+		if m == nil {
+			return nil, false
+		}
+		argument, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, false
+		}
+		failure.Argument = &argument
+		failure.ArgumentKind = m[2]
+	case ExecutionFailureMoveAbort:
+		abort, ok := ParseMoveAbort(errorString)
+		if !ok {
+			return nil, false
+		}
+		failure.MoveAbort = abort
+	}
+	return failure, true
+}
+
+// Failure returns the parsed error of a failed execution, nil if the execution succeeded. An error which
+// can't be parsed is returned with an empty Kind.
+func (s ExecutionStatus) Failure() *ExecutionFailure {
+	if s.Status == ExecutionStatusSuccess {
+		return nil
+	}
+	if failure, ok := ParseExecutionFailure(s.Error); ok {
+		return failure
+	}
+	return &ExecutionFailure{Raw: s.Error}
+}
@@ -0,0 +1,107 @@
+package suiclient_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+const coinAbortError = `MoveAbort(MoveLocation { module: ModuleId { address: 0000000000000000000000000000000000000000000000000000000000000002, ` +
+	`name: Identifier("coin") }, function: 3, instruction: 9, function_name: Some("split") }, 2) in command 0`
+
+func TestParseExecutionFailure(t *testing.T) {
+	failure, ok := suiclient.ParseExecutionFailure("InsufficientGas")
+	require.True(t, ok)
+	require.Equal(t, suiclient.ExecutionFailureInsufficientGas, failure.Kind)
+	require.Nil(t, failure.Command)
+	require.Nil(t, failure.MoveAbort)
+	require.Equal(t, "InsufficientGas", failure.Error())
+
+	failure, ok = suiclient.ParseExecutionFailure("CommandArgumentError { arg_idx: 0, kind: TypeMismatch } in command 1")
+	require.True(t, ok)
+	require.Equal(t, suiclient.ExecutionFailureCommandArgumentError, failure.Kind)
+	require.Equal(t, "{ arg_idx: 0, kind: TypeMismatch }", failure.Details)
+	require.Equal(t, 0, *failure.Argument)
+	require.Equal(t, "TypeMismatch", failure.ArgumentKind)
+	require.Equal(t, 1, *failure.Command)
+
+	failure, ok = suiclient.ParseExecutionFailure("CommandArgumentError { arg_idx: 2, kind: IndexOutOfBounds { idx: 3 } } in command 0")
+	require.True(t, ok)
+	require.Equal(t, 2, *failure.Argument)
+	require.Equal(t, "IndexOutOfBounds { idx: 3 }", failure.ArgumentKind)
+	require.Equal(t, 0, *failure.Command)
+
+	failure, ok = suiclient.ParseExecutionFailure("TypeArgumentError { argument_idx: 1, kind: TypeNotFound } in command 4")
+	require.True(t, ok)
+	require.Equal(t, suiclient.ExecutionFailureTypeArgumentError, failure.Kind)
+	require.Equal(t, 1, *failure.Argument)
+	require.Equal(t, "TypeNotFound", failure.ArgumentKind)
+	require.Equal(t, 4, *failure.Command)
+
+	failure, ok = suiclient.ParseExecutionFailure("InsufficientCoinBalance in command 2")
+	require.True(t, ok)
+	require.Equal(t, suiclient.ExecutionFailureInsufficientCoinBalance, failure.Kind)
+	require.Nil(t, failure.Argument)
+	require.Equal(t, 2, *failure.Command)
+
+	failure, ok = suiclient.ParseExecutionFailure(coinAbortError)
+	require.True(t, ok)
+	require.Equal(t, suiclient.ExecutionFailureMoveAbort, failure.Kind)
+	require.Equal(t, 0, *failure.Command)
+	require.Equal(t, uint64(movebcs.ECoinNotEnough), failure.MoveAbort.Code)
+	require.Equal(t, "ENotEnough", failure.MoveAbort.Name)
+	require.Equal(t, "move abort in 0x2::coin::split with code 2 (ENotEnough) in command 0", failure.Error())
+
+	// a variant added after the SDK is kept
+	failure, ok = suiclient.ParseExecutionFailure("SomeNewFailure { reason: 1 }")
+	require.True(t, ok)
+	require.Equal(t, suiclient.ExecutionFailureKind("SomeNewFailure"), failure.Kind)
+
+	_, ok = suiclient.ParseExecutionFailure("MoveAbort(...)")
+	require.False(t, ok)
+	_, ok = suiclient.ParseExecutionFailure("CommandArgumentError { kind: TypeMismatch }")
+	require.False(t, ok)
+
+	// errors which aren't variants
+	for _, errorString := range []string{
+		"Error checking transaction input objects: ObjectNotFound { object_id: 0x5, version: None }",
+		"Transaction timed out",
+		"InsufficientGas: out of gas",
+		"insufficientGas",
+		"",
+	} {
+		_, ok = suiclient.ParseExecutionFailure(errorString)
+		require.False(t, ok, errorString)
+		require.Empty(t, suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusFailure, Error: errorString}.Failure().Kind)
+	}
+
+	require.Nil(t, suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess}.Failure())
+	failure = suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusFailure, Error: "InvalidGasObject"}.Failure()
+	require.Equal(t, suiclient.ExecutionFailureInvalidGasObject, failure.Kind)
+}
+
+func TestSignAndExecuteTransactionFailure(t *testing.T) {
+	rpc, client := newFakeRPC(t)
+	rpc.result("sui_executeTransactionBlock", fmt.Sprintf(`{"digest":%q,"effects":{"messageVersion":"v1",
+		"status":{"status":"failure","error":%q}}}`, testDigest, coinAbortError))
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+
+	resp, err := client.SignAndExecuteTransaction(context.Background(), signer, sui.Base64Data{1, 2, 3},
+		&suiclient.SuiTransactionBlockResponseOptions{ShowEffects: true})
+	require.NotNil(t, resp)
+	var failure *suiclient.ExecutionFailure
+	require.True(t, errors.As(err, &failure))
+	require.Equal(t, suiclient.ExecutionFailureMoveAbort, failure.Kind)
+	var abort *suiclient.MoveAbortError
+	require.True(t, errors.As(err, &abort))
+	require.Equal(t, sui.Identifier("split"), abort.Function)
+}
@@ -0,0 +1,326 @@
+// Package executor runs many transactions of one address in parallel. Each transaction gets its own gas coin
+// from a pool, and the owned objects it uses are locked until its effects come back, so no object is used
+// by two transactions at once. Transactions that conflict are queued.
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suiclient/gasstation"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+var ErrGasCoinInput = errors.New("gas coin of the pool is used as an input")
+
+// Client is the part of suiclient.ClientImpl the executor uses
+type Client interface {
+	GetCoins(ctx context.Context, req *suiclient.GetCoinsRequest) (*suiclient.CoinPage, error)
+	GetReferenceGasPrice(ctx context.Context) (*sui.BigInt, error)
+	ExecuteTransactionBlock(ctx context.Context, req *suiclient.ExecuteTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error)
+}
+
+var _ Client = (*suiclient.ClientImpl)(nil)
+
+type Config struct {
+	Signer suisigner.TransactionSigner
+	Client Client
+	// optional, the reference gas price is used if zero
+	GasPrice uint64
+	// optional, the cache of the object refs, e.g. the one of the client, so the refs are shared with the
+	// transactions built outside of the executor, and the immutable objects resolved by the client aren't locked
+	Cache *suiclient.ObjectRefCache
+}
+
+type Executor struct {
+	signer   suisigner.TransactionSigner
+	client   Client
+	gasPrice uint64
+	pool     *gasstation.GasPool
+
+	mu sync.Mutex
+	// the owned objects used by the transactions in flight
+	locked map[sui.ObjectId]bool
+	// the latest refs of the objects, from the effects of the executed transactions
+	refs *suiclient.ObjectRefCache
+	// closed and replaced whenever objects or gas coins are released, to wake up the queued transactions
+	released chan struct{}
+}
+
+func NewExecutor(config *Config) *Executor {
+	refs := config.Cache
+	if refs == nil {
+		refs = suiclient.NewObjectRefCache()
+	}
+	return &Executor{
+		signer:   config.Signer,
+		client:   config.Client,
+		gasPrice: config.GasPrice,
+		pool:     gasstation.NewGasPool(config.Signer.GetAddress()),
+		locked:   make(map[sui.ObjectId]bool),
+		refs:     refs,
+		released: make(chan struct{}),
+	}
+}
+
+func (e *Executor) Pool() *gasstation.GasPool {
+	return e.pool
+}
+
+// Sync loads the SUI coins of the signer into the gas pool. The coins in flight, and the ones whose versions
+// from the fullnode aren't newer than the ones from the effects, are kept as they are.
+func (e *Executor) Sync(ctx context.Context) error {
+	var cursor *sui.ObjectId
+	for {
+		page, err := e.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: e.signer.GetAddress(), Cursor: cursor})
+		if err != nil {
+			return fmt.Errorf("failed to get gas coins: %w", err)
+		}
+		for _, coin := range page.Data {
+			e.pool.Add(coin.Ref(), coin.Balance.Uint64())
+		}
+		if !page.HasNextPage || page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	e.notify()
+	return nil
+}
+
+// SplitGasCoins splits a coin of the pool into `count` coins of `amount`, so `count` transactions can run
+// in parallel, and adds the new coins to the pool
+func (e *Executor) SplitGasCoins(ctx context.Context, count int, amount uint64, gasBudget uint64) error {
+	sender := e.signer.GetAddress()
+	recipients := make([]*sui.Address, count)
+	amounts := make([]uint64, count)
+	for i := range recipients {
+		recipients[i] = sender
+		amounts[i] = amount
+	}
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	if err := ptb.PaySui(recipients, amounts); err != nil {
+		return err
+	}
+	// the split is paid by a coin which covers the amounts too, since PaySui splits the gas coin
+	resp, err := e.execute(ctx, ptb.Finish(), uint64(count)*amount+gasBudget, gasBudget)
+	if err != nil {
+		return err
+	}
+	// the fullnode may not have indexed the split yet, so the pool is updated from the effects instead of Sync
+	effects := resp.Effects.Data.V1
+	e.pool.Debit(effects.GasObject.Reference.ObjectId, uint64(count)*amount)
+	for _, created := range effects.Created {
+		if owner := created.Owner.Data.AddressOwner; owner != nil && *owner == *sender {
+			e.pool.Add(created.Reference.Ref(), amount)
+		}
+	}
+	e.notify()
+	return nil
+}
+
+// Execute signs and executes the programmable transaction paid by a gas coin of the pool, and returns its response
+// with the effects. The refs of the owned objects are replaced by the latest ones the executor knows, and the
+// transaction waits until no other transaction in flight uses its owned objects and a gas coin is available.
+// It is safe to call from many goroutines.
+func (e *Executor) Execute(ctx context.Context, pt suiptb.ProgrammableTransaction, gasBudget uint64) (*suiclient.SuiTransactionBlockResponse, error) {
+	return e.execute(ctx, pt, gasBudget, gasBudget)
+}
+
+func (e *Executor) execute(
+	ctx context.Context,
+	pt suiptb.ProgrammableTransaction,
+	gasBalance uint64,
+	gasBudget uint64,
+) (*suiclient.SuiTransactionBlockResponse, error) {
+	owned := e.ownedObjectIds(&pt)
+	for _, id := range owned {
+		if e.pool.Contains(id) {
+			return nil, fmt.Errorf("%w: %s", ErrGasCoinInput, id)
+		}
+	}
+	gasCoin, err := e.acquire(ctx, owned, gasBalance)
+	if err != nil {
+		return nil, err
+	}
+	pt = e.latestRefs(pt)
+
+	gasPrice, err := e.referenceGasPrice(ctx)
+	if err != nil {
+		e.release(owned, gasCoin, nil)
+		return nil, err
+	}
+	tx := suiptb.NewTransactionData(e.signer.GetAddress(), pt, []*sui.ObjectRef{gasCoin}, gasBudget, gasPrice)
+	txBytes, err := bcs.Marshal(tx)
+	if err != nil {
+		e.release(owned, gasCoin, nil)
+		return nil, err
+	}
+	signature, err := e.signer.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	if err != nil {
+		e.release(owned, gasCoin, nil)
+		return nil, fmt.Errorf("failed to sign transaction block: %w", err)
+	}
+
+	resp, err := e.client.ExecuteTransactionBlock(ctx, &suiclient.ExecuteTransactionBlockRequest{
+		TxDataBytes: txBytes,
+		Signatures:  []*suisigner.Signature{&signature},
+		Options:     &suiclient.SuiTransactionBlockResponseOptions{ShowEffects: true},
+		RequestType: suiclient.TxnRequestTypeWaitForEffectsCert,
+	})
+	if err == nil && (resp.Effects == nil || resp.Effects.Data.V1 == nil) {
+		err = errors.New("no effects returned")
+	}
+	if err != nil {
+		// the result is unknown, so the gas coin and the refs of the objects can't be trusted anymore
+		e.pool.Remove(gasCoin.ObjectId)
+		e.release(owned, nil, nil)
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	// the gas is charged and the owned objects get new versions even if the execution fails
+	e.release(owned, gasCoin, resp.Effects.Data.V1)
+	if !resp.Effects.Data.IsSuccess() {
+		return resp, fmt.Errorf("failed to execute transaction: %w", resp.Effects.Data.V1.Status.Failure())
+	}
+	return resp, nil
+}
+
+// acquire locks the owned objects and reserves a gas coin, waiting until they are all available
+func (e *Executor) acquire(ctx context.Context, owned []*sui.ObjectId, gasBalance uint64) (*sui.ObjectRef, error) {
+	for {
+		e.mu.Lock()
+		released := e.released
+		if !e.anyLocked(owned) {
+			gasCoin, err := e.pool.Reserve(gasBalance)
+			if err == nil {
+				for _, id := range owned {
+					e.locked[*id] = true
+				}
+				e.mu.Unlock()
+				return gasCoin, nil
+			}
+			if !errors.Is(err, gasstation.ErrPoolExhausted) || !e.anyReserved() {
+				// no transaction in flight will release a coin
+				e.mu.Unlock()
+				return nil, err
+			}
+		}
+		e.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// release unlocks the owned objects and applies the effects of the transaction, if it was executed.
+// The gas coin is released with its new ref, or as it was if the transaction wasn't submitted. Without both,
+// the result of the transaction is unknown, and the refs of the owned objects are dropped.
+func (e *Executor) release(owned []*sui.ObjectId, gasCoin *sui.ObjectRef, effects *suiclient.SuiTransactionBlockEffectsV1) {
+	e.mu.Lock()
+	// the refs are updated before the objects are unlocked, so the next transaction gets the new ones
+	switch {
+	case effects != nil:
+		e.refs.ApplyEffects(effects)
+	case gasCoin == nil:
+		e.refs.Invalidate(owned...)
+	}
+	for _, id := range owned {
+		delete(e.locked, *id)
+	}
+	e.mu.Unlock()
+
+	switch {
+	case effects != nil:
+		e.pool.Reconcile(effects)
+	case gasCoin != nil:
+		e.pool.Release(gasCoin.ObjectId)
+	}
+	e.notify()
+}
+
+// latestRefs replaces the refs of the owned objects with the latest ones from the effects
+func (e *Executor) latestRefs(pt suiptb.ProgrammableTransaction) suiptb.ProgrammableTransaction {
+	inputs := make([]suiptb.CallArg, len(pt.Inputs))
+	for i, input := range pt.Inputs {
+		inputs[i] = input
+		if input.Object == nil {
+			continue
+		}
+		switch {
+		case input.Object.ImmOrOwnedObject != nil:
+			if ref, _, ok := e.refs.Get(input.Object.ImmOrOwnedObject.ObjectId); ok {
+				inputs[i] = suiptb.CallArg{Object: &suiptb.ObjectArg{ImmOrOwnedObject: ref}}
+			}
+		case input.Object.Receiving != nil:
+			if ref, _, ok := e.refs.Get(input.Object.Receiving.ObjectId); ok {
+				inputs[i] = suiptb.CallArg{Object: &suiptb.ObjectArg{Receiving: ref}}
+			}
+		}
+	}
+	pt.Inputs = inputs
+	return pt
+}
+
+// anyLocked must be called with the mutex held
+func (e *Executor) anyLocked(ids []*sui.ObjectId) bool {
+	for _, id := range ids {
+		if e.locked[*id] {
+			return true
+		}
+	}
+	return false
+}
+
+// anyReserved reports whether a transaction in flight holds a gas coin
+func (e *Executor) anyReserved() bool {
+	total, available := e.pool.Len()
+	return available < total
+}
+
+func (e *Executor) notify() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	close(e.released)
+	e.released = make(chan struct{})
+}
+
+func (e *Executor) referenceGasPrice(ctx context.Context) (uint64, error) {
+	if e.gasPrice != 0 {
+		return e.gasPrice, nil
+	}
+	price, err := e.client.GetReferenceGasPrice(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reference gas price: %w", err)
+	}
+	return price.Uint64(), nil
+}
+
+// ownedObjectIds returns the objects of the inputs which are owned, or received, by an address. The objects known
+// to be immutable from the cache, e.g. a frozen CoinMetadata, can be read by many transactions at once, so they
+// aren't locked, while the objects whose owners are unknown are.
+func (e *Executor) ownedObjectIds(pt *suiptb.ProgrammableTransaction) []*sui.ObjectId {
+	var ids []*sui.ObjectId
+	for _, input := range pt.Inputs {
+		switch {
+		case input.Object == nil:
+		case input.Object.ImmOrOwnedObject != nil:
+			id := input.Object.ImmOrOwnedObject.ObjectId
+			if _, owner, ok := e.refs.Get(id); ok && owner != nil && owner.Immutable != nil {
+				continue
+			}
+			ids = append(ids, id)
+		case input.Object.Receiving != nil:
+			ids = append(ids, input.Object.Receiving.ObjectId)
+		}
+	}
+	return ids
+}
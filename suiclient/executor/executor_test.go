@@ -0,0 +1,156 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suiclient/executor"
+	"github.com/pattonkan/sui-go/suiclient/gasstation"
+	"github.com/pattonkan/sui-go/suiclient/internal/fakechain"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+const gasFee = fakechain.GasFee
+
+var (
+	testPackage = sui.MustPackageIdFromHex("0x1234")
+	testDigest  = sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi")
+)
+
+func moveCall(objects ...*sui.ObjectRef) suiptb.ProgrammableTransaction {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	args := make([]suiptb.Argument, len(objects))
+	for i, object := range objects {
+		args[i] = ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: object})
+	}
+	ptb.ProgrammableMoveCall(testPackage, "game", "play", nil, args)
+	return ptb.Finish()
+}
+
+func TestExecutor(t *testing.T) {
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(signer.Address)
+	chain.Create(1000 * gasFee)
+	// the owned objects, which are passed with their initial refs every time
+	objects := []*sui.ObjectRef{chain.Create(0), chain.Create(0)}
+	ctx := context.Background()
+
+	cache := suiclient.NewObjectRefCache()
+	exec := executor.NewExecutor(&executor.Config{Signer: signer, Client: chain, Cache: cache})
+	require.NoError(t, exec.Sync(ctx))
+	// the new coins are known from the effects of the split
+	chain.Lagging.Store(true)
+	require.NoError(t, exec.SplitGasCoins(ctx, 4, 50*gasFee, suiclient.DefaultGasBudget))
+	total, available := exec.Pool().Len()
+	require.Equal(t, 5, total)
+	require.Equal(t, 5, available)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pt := moveCall(objects[i%2])
+			if i%5 == 0 {
+				pt = moveCall(objects...)
+			}
+			_, err := exec.Execute(ctx, pt, suiclient.DefaultGasBudget)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 21, chain.Executed)
+	// every transaction used the refs from the effects of the previous one
+	for _, object := range objects {
+		require.Equal(t, uint64(1+12), chain.Versions[*object.ObjectId])
+		ref, _, ok := cache.Get(object.ObjectId)
+		require.True(t, ok)
+		require.Equal(t, uint64(1+12), ref.Version)
+	}
+	total, available = exec.Pool().Len()
+	require.Equal(t, 5, total)
+	require.Equal(t, 5, available)
+	require.Equal(t, uint64(1000*gasFee-21*gasFee), exec.Pool().Balance())
+}
+
+func TestExecutorRejects(t *testing.T) {
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(signer.Address)
+	gasCoin := chain.Create(10 * gasFee)
+	object := chain.Create(0)
+	ctx := context.Background()
+	exec := executor.NewExecutor(&executor.Config{Signer: signer, Client: chain})
+
+	// no gas coin is synced yet
+	_, err := exec.Execute(ctx, moveCall(object), suiclient.DefaultGasBudget)
+	require.ErrorIs(t, err, gasstation.ErrPoolExhausted)
+
+	require.NoError(t, exec.Sync(ctx))
+	_, err = exec.Execute(ctx, moveCall(gasCoin), suiclient.DefaultGasBudget)
+	require.ErrorIs(t, err, executor.ErrGasCoinInput)
+
+	// a queued transaction gives up with its context
+	gasBudget := uint64(5 * gasFee)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	chain.Lock()
+	done := make(chan error)
+	go func() {
+		_, err := exec.Execute(context.Background(), moveCall(object), gasBudget)
+		done <- err
+	}()
+	// wait until the first transaction holds the only gas coin
+	for {
+		if _, available := exec.Pool().Len(); available == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	_, err = exec.Execute(ctx, moveCall(object), gasBudget)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	chain.Unlock()
+	require.NoError(t, <-done)
+}
+
+func TestExecutorImmutableObjects(t *testing.T) {
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(signer.Address)
+	chain.Create(10 * gasFee)
+	chain.Create(10 * gasFee)
+	metadata := chain.Create(0)
+	chain.Frozen[*metadata.ObjectId] = true
+	ctx := context.Background()
+
+	cache := suiclient.NewObjectRefCache()
+	cache.Put(metadata, &sui.Owner{Immutable: &sui.EmptyEnum{}})
+	exec := executor.NewExecutor(&executor.Config{Signer: signer, Client: chain, Cache: cache})
+	require.NoError(t, exec.Sync(ctx))
+
+	// both transactions reading the immutable object are in flight at once
+	chain.Lock()
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := exec.Execute(ctx, moveCall(metadata), suiclient.DefaultGasBudget)
+			done <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return chain.Submitted.Load() == 2 }, time.Second, time.Millisecond)
+	chain.Unlock()
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+}
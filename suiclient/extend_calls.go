@@ -14,11 +14,10 @@ import (
 
 func (s *ClientImpl) SignAndExecuteTransaction(
 	ctx context.Context,
-	signer *suisigner.Signer,
+	signer suisigner.TransactionSigner,
 	txBytes sui.Base64Data,
 	options *SuiTransactionBlockResponseOptions,
 ) (*SuiTransactionBlockResponse, error) {
-	// FIXME we need to support other intent
 	signature, err := signer.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign transaction block: %w", err)
@@ -36,14 +35,14 @@ func (s *ClientImpl) SignAndExecuteTransaction(
 		return nil, fmt.Errorf("failed to execute transaction: %w", err)
 	}
 	if options.ShowEffects && !resp.Effects.Data.IsSuccess() {
-		return resp, fmt.Errorf("failed to execute transaction: %v", resp.Effects.Data.V1.Status)
+		return resp, fmt.Errorf("failed to execute transaction: %w", s.decodeFailure(ctx, resp.Effects.Data.V1.Status.Failure(), decodeProgrammableTransaction(txBytes)))
 	}
 	return resp, nil
 }
 
 func (s *ClientImpl) BuildAndPublishContract(
 	ctx context.Context,
-	signer *suisigner.Signer,
+	signer suisigner.TransactionSigner,
 	contractPath string,
 	gasBudget uint64,
 	options *SuiTransactionBlockResponseOptions,
@@ -56,7 +55,7 @@ func (s *ClientImpl) BuildAndPublishContract(
 	txnBytes, err := s.Publish(
 		context.Background(),
 		&PublishRequest{
-			Sender:          signer.Address,
+			Sender:          signer.GetAddress(),
 			CompiledModules: modules.Modules,
 			Dependencies:    modules.Dependencies,
 			GasBudget:       sui.NewBigInt(gasBudget),
@@ -79,7 +78,7 @@ func (s *ClientImpl) BuildAndPublishContract(
 
 func (s *ClientImpl) PublishContract(
 	ctx context.Context,
-	signer *suisigner.Signer,
+	signer suisigner.TransactionSigner,
 	modules []*sui.Base64Data,
 	dependencies []*sui.Address,
 	gasBudget uint64,
@@ -88,7 +87,7 @@ func (s *ClientImpl) PublishContract(
 	txnBytes, err := s.Publish(
 		context.Background(),
 		&PublishRequest{
-			Sender:          signer.Address,
+			Sender:          signer.GetAddress(),
 			CompiledModules: modules,
 			Dependencies:    dependencies,
 			GasBudget:       sui.NewBigInt(gasBudget),
@@ -111,7 +110,7 @@ func (s *ClientImpl) PublishContract(
 
 func (s *ClientImpl) MintToken(
 	ctx context.Context,
-	signer *suisigner.Signer,
+	signer suisigner.TransactionSigner,
 	packageId *sui.PackageId,
 	tokenName string,
 	treasuryCap *sui.ObjectId,
@@ -121,12 +120,12 @@ func (s *ClientImpl) MintToken(
 	txnBytes, err := s.MoveCall(
 		ctx,
 		&MoveCallRequest{
-			Signer:    signer.Address,
+			Signer:    signer.GetAddress(),
 			PackageId: packageId,
 			Module:    tokenName,
 			Function:  "mint",
 			TypeArgs:  []string{},
-			Arguments: []any{treasuryCap.String(), fmt.Sprintf("%d", mintAmount), signer.Address.String()},
+			Arguments: []any{treasuryCap.String(), fmt.Sprintf("%d", mintAmount), signer.GetAddress().String()},
 			GasBudget: sui.NewBigInt(DefaultGasBudget),
 		},
 	)
@@ -293,3 +292,99 @@ func BCS_RequestWithdrawStake(
 	)
 	return bcs.Marshal(tx)
 }
+
+// ValidatorCandidate is the metadata of `0x3::sui_system::request_add_validator_candidate`.
+// ProtocolPubKey and ProofOfPossession come from the BLS12-381 key in `suisigner/bls12381`,
+// and the addresses are multiaddrs such as `/dns/validator.example.com/tcp/8080/http`.
+type ValidatorCandidate struct {
+	ProtocolPubKey    []byte
+	NetworkPubKey     []byte
+	WorkerPubKey      []byte
+	ProofOfPossession []byte
+	Name              string
+	Description       string
+	ImageUrl          string
+	ProjectUrl        string
+	NetAddress        string
+	P2pAddress        string
+	PrimaryAddress    string
+	WorkerAddress     string
+	GasPrice          uint64
+	CommissionRate    uint64 // in basis points
+}
+
+func BCS_RequestAddValidatorCandidate(
+	signer *sui.Address,
+	candidate *ValidatorCandidate,
+	gas []*sui.ObjectRef,
+	gasBudget, gasPrice uint64,
+) ([]byte, error) {
+	// build with BCS
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	arg0, err := ptb.Obj(suiptb.SuiSystemMutObj)
+	if err != nil {
+		return nil, err
+	}
+	args := []suiptb.Argument{arg0}
+	values := []any{
+		candidate.ProtocolPubKey,
+		candidate.NetworkPubKey,
+		candidate.WorkerPubKey,
+		candidate.ProofOfPossession,
+		candidate.Name,
+		candidate.Description,
+		candidate.ImageUrl,
+		candidate.ProjectUrl,
+		candidate.NetAddress,
+		candidate.P2pAddress,
+		candidate.PrimaryAddress,
+		candidate.WorkerAddress,
+		candidate.GasPrice,
+		candidate.CommissionRate,
+	}
+	for _, value := range values {
+		arg, err := ptb.Pure(value)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	ptb.Command(
+		suiptb.Command{
+			MoveCall: &suiptb.ProgrammableMoveCall{
+				Package:   sui.SuiPackageIdSuiSystem,
+				Module:    sui.SuiSystemModuleName,
+				Function:  "request_add_validator_candidate",
+				Arguments: args,
+			},
+		},
+	)
+	pt := ptb.Finish()
+	tx := suiptb.NewTransactionData(
+		signer, pt, gas, gasBudget, gasPrice,
+	)
+	return bcs.Marshal(tx)
+}
+
+// AddressHasActivity reports whether the address has sent or received any transaction.
+// It can be used as the `suisigner.AccountActivityChecker` for account discovery.
+func (s *ClientImpl) AddressHasActivity(ctx context.Context, address *sui.Address) (bool, error) {
+	limit := uint(1)
+	filters := []*TransactionFilter{
+		{FromAddress: address},
+		{ToAddress: address},
+	}
+	for _, filter := range filters {
+		page, err := s.QueryTransactionBlocks(ctx, &QueryTransactionBlocksRequest{
+			Query: &TransactionBlockResponseQuery{Filter: filter},
+			Limit: &limit,
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(page.Data) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,82 @@
+package suiclient_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+var testDigest = sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi")
+
+// fakeRPC serves canned JSON-RPC results by method, so the client can be tested offline
+type fakeRPC struct {
+	t        *testing.T
+	mu       sync.Mutex
+	handlers map[string]func(params []json.RawMessage) (any, error)
+	calls    map[string]int
+}
+
+func newFakeRPC(t *testing.T) (*fakeRPC, *suiclient.ClientImpl) {
+	f := &fakeRPC{
+		t:        t,
+		handlers: make(map[string]func(params []json.RawMessage) (any, error)),
+		calls:    make(map[string]int),
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, suiclient.NewClient(server.URL)
+}
+
+func (f *fakeRPC) handle(method string, handler func(params []json.RawMessage) (any, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[method] = handler
+}
+
+// result serves a fixed result, given as a JSON string or as a value to encode
+func (f *fakeRPC) result(method string, result any) {
+	f.handle(method, func([]json.RawMessage) (any, error) {
+		if s, ok := result.(string); ok {
+			return json.RawMessage(s), nil
+		}
+		return result, nil
+	})
+}
+
+func (f *fakeRPC) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+func (f *fakeRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Id     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		f.t.Errorf("invalid JSON-RPC request: %s", err)
+		return
+	}
+	f.mu.Lock()
+	handler, ok := f.handlers[req.Method]
+	f.calls[req.Method]++
+	f.mu.Unlock()
+
+	resp := map[string]any{"jsonrpc": "2.0", "id": req.Id}
+	if !ok {
+		resp["error"] = map[string]any{"code": -32601, "message": "method not found: " + req.Method}
+	} else if result, err := handler(req.Params); err != nil {
+		resp["error"] = map[string]any{"code": -32000, "message": err.Error()}
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
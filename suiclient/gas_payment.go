@@ -0,0 +1,177 @@
+package suiclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+const (
+	// MaxGasPaymentObjects is the max number of the gas coins of a transaction, `max_gas_payment_objects` in the protocol config
+	MaxGasPaymentObjects = 256
+	// MaxTxGas is the max gas budget of a transaction, `max_tx_gas` in the protocol config
+	MaxTxGas uint64 = 50_000_000_000
+	// GasSafeOverhead is the computation units added to the estimated budget, the same as the TypeScript SDK
+	GasSafeOverhead uint64 = 1000
+)
+
+// GasOptions sets the gas of a transaction. The zero values are filled by BuildTransactionData.
+type GasOptions struct {
+	// the reference gas price if zero
+	GasPrice uint64
+	// estimated by a dry run if zero
+	GasBudget uint64
+	// the extra percentage added to the estimated gas budget
+	BudgetMarginPercent uint64
+	// selected from the SUI coins of the sender if empty
+	GasPayment []*sui.ObjectRef
+}
+
+// BuildTransactionData resolves the object inputs added by their ids, fills the gas price, the gas budget and
+// the gas payment of the programmable transaction, and returns the TransactionData ready to sign
+func (s *ClientImpl) BuildTransactionData(
+	ctx context.Context,
+	sender *sui.Address,
+	ptb *suiptb.ProgrammableTransactionBuilder,
+	options *GasOptions,
+) (*suiptb.TransactionData, error) {
+	if options == nil {
+		options = &GasOptions{}
+	}
+	if err := s.ResolveObjects(ctx, ptb); err != nil {
+		return nil, fmt.Errorf("failed to resolve objects: %w", err)
+	}
+	pt, err := ptb.FinishChecked()
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := options.GasPrice
+	if gasPrice == 0 {
+		price, err := s.GetReferenceGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reference gas price: %w", err)
+		}
+		gasPrice = price.Uint64()
+	}
+	gasBudget := options.GasBudget
+	if gasBudget == 0 {
+		var err error
+		gasBudget, err = s.EstimateGasBudget(ctx, sender, pt, gasPrice)
+		if err != nil {
+			return nil, err
+		}
+		gasBudget += gasBudget * options.BudgetMarginPercent / 100
+		if gasBudget > MaxTxGas {
+			gasBudget = MaxTxGas
+		}
+	}
+	gasPayment := options.GasPayment
+	if len(gasPayment) == 0 {
+		var err error
+		gasPayment, err = s.SelectGasPayment(ctx, sender, gasBudget, pt.InputObjectIds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to select gas coins: %w", err)
+		}
+	}
+	txData := suiptb.NewTransactionData(sender, pt, gasPayment, gasBudget, gasPrice)
+	return &txData, nil
+}
+
+// EstimateGasBudget dry-runs the transaction and returns the gas budget it needs
+func (s *ClientImpl) EstimateGasBudget(
+	ctx context.Context,
+	sender *sui.Address,
+	pt suiptb.ProgrammableTransaction,
+	gasPrice uint64,
+) (uint64, error) {
+	// the dry run pays with a mock gas coin when no gas payment is given
+	txData := suiptb.NewTransactionData(sender, pt, []*sui.ObjectRef{}, MaxTxGas, gasPrice)
+	txBytes, err := bcs.Marshal(txData)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	resp, err := s.DryRunTransaction(ctx, txBytes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dry run transaction: %w", err)
+	}
+	effects := resp.Effects.Data.V1
+	if effects == nil {
+		return 0, fmt.Errorf("dry run returned no effects")
+	}
+	if !resp.Effects.Data.IsSuccess() {
+		return 0, fmt.Errorf("dry run failed: %s", effects.Status.Error)
+	}
+	return GasBudgetFromCost(&effects.GasUsed, gasPrice), nil
+}
+
+// GasBudgetFromCost returns the gas budget of a measured gas cost with the safe overhead of GasSafeOverhead.
+// The budget covers the computation cost even if the storage rebate is larger than the storage cost.
+func GasBudgetFromCost(gasUsed *GasCostSummary, gasPrice uint64) uint64 {
+	computation := bigIntUint64(gasUsed.ComputationCost) + GasSafeOverhead*gasPrice
+	storage := bigIntUint64(gasUsed.StorageCost)
+	rebate := bigIntUint64(gasUsed.StorageRebate)
+	if storage <= rebate {
+		return computation
+	}
+	return computation + storage - rebate
+}
+
+func bigIntUint64(v *sui.BigInt) uint64 {
+	if v == nil || v.Int == nil {
+		return 0
+	}
+	return v.Uint64()
+}
+
+// SelectGasPayment picks the SUI coins of the owner whose total balance covers the gas budget.
+// The coins in `exclude`, e.g. the ones used as the inputs of the transaction, are never picked, and neither are the
+// coins whose versions in the object cache of the client are newer than the ones of the fullnode.
+func (s *ClientImpl) SelectGasPayment(
+	ctx context.Context,
+	owner *sui.Address,
+	gasBudget uint64,
+	exclude []*sui.ObjectId,
+) ([]*sui.ObjectRef, error) {
+	excluded := make(map[sui.ObjectId]bool, len(exclude))
+	for _, id := range exclude {
+		excluded[*id] = true
+	}
+
+	var payment []*sui.ObjectRef
+	var total uint64
+	var cursor *sui.ObjectId
+	for {
+		page, err := s.GetCoins(ctx, &GetCoinsRequest{Owner: owner, Cursor: cursor})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get coins: %w", err)
+		}
+		for _, coin := range page.Data {
+			if excluded[*coin.CoinObjectId] || coin.Balance.Uint64() == 0 {
+				continue
+			}
+			if len(payment) == MaxGasPaymentObjects {
+				return nil, fmt.Errorf("%w: more than %d coins are needed to pay %d gas", ErrNeedMergeCoin, MaxGasPaymentObjects, gasBudget)
+			}
+			if s.objectCache != nil && s.objectCache.isNewer(coin.CoinObjectId, coin.Version.Uint64()) {
+				// the fullnode lags behind the effects, so the balance of the coin is unknown
+				continue
+			}
+			payment = append(payment, coin.Ref())
+			total += coin.Balance.Uint64()
+			if total >= gasBudget {
+				return payment, nil
+			}
+		}
+		if !page.HasNextPage || page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if len(payment) == 0 {
+		return nil, ErrNoCoinsFound
+	}
+	return nil, fmt.Errorf("%w: %d of %d gas", ErrInsufficientBalance, total, gasBudget)
+}
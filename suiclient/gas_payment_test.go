@@ -0,0 +1,104 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+func TestGasBudgetFromCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		cost     suiclient.GasCostSummary
+		expected uint64
+	}{
+		{
+			name: "storage cost",
+			cost: suiclient.GasCostSummary{
+				ComputationCost: sui.NewBigInt(1_000_000),
+				StorageCost:     sui.NewBigInt(2_000_000),
+				StorageRebate:   sui.NewBigInt(500_000),
+			},
+			expected: 1_000_000 + 1000*750 + 1_500_000,
+		},
+		{
+			name: "storage rebate",
+			cost: suiclient.GasCostSummary{
+				ComputationCost: sui.NewBigInt(1_000_000),
+				StorageCost:     sui.NewBigInt(500_000),
+				StorageRebate:   sui.NewBigInt(2_000_000),
+			},
+			expected: 1_000_000 + 1000*750,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, suiclient.GasBudgetFromCost(&tt.cost, 750))
+		})
+	}
+}
+
+func TestBuildTransactionData(t *testing.T) {
+	sender := sui.MustAddressFromHex("0xa11ce")
+	inputCoin := &sui.ObjectRef{ObjectId: sui.MustObjectIdFromHex("0x101"), Version: 1, Digest: testDigest}
+
+	rpc, client := newFakeRPC(t)
+	rpc.result("suix_getReferenceGasPrice", `"750"`)
+	rpc.handle("sui_dryRunTransactionBlock", func(params []json.RawMessage) (any, error) {
+		var txBytes sui.Base64Data
+		require.NoError(t, json.Unmarshal(params[0], &txBytes))
+		var tx suiptb.TransactionData
+		_, err := bcs.Unmarshal(txBytes, &tx)
+		require.NoError(t, err)
+		require.Empty(t, tx.V1.GasData.Payment)
+		require.Equal(t, uint64(750), tx.V1.GasData.Price)
+		return json.RawMessage(`{"effects":{"messageVersion":"v1","status":{"status":"success"},"gasUsed":{
+			"computationCost":"1000000","storageCost":"2000000","storageRebate":"500000","nonRefundableStorageFee":"0"}}}`), nil
+	})
+	// the coin used by the transaction comes first, and must not be picked as gas
+	rpc.result("suix_getCoins", fmt.Sprintf(`{"data":[
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x101","version":"1","digest":"%[1]s","balance":"100000000"},
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x102","version":"2","digest":"%[1]s","balance":"2000000"},
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x103","version":"3","digest":"%[1]s","balance":"2000000"},
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x104","version":"4","digest":"%[1]s","balance":"2000000"}
+	],"hasNextPage":false}`, testDigest))
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	require.NoError(t, ptb.TransferObject(sui.MustAddressFromHex("0xb0b"), inputCoin))
+	tx, err := client.BuildTransactionData(context.Background(), sender, ptb, &suiclient.GasOptions{BudgetMarginPercent: 10})
+	require.NoError(t, err)
+
+	gasData := tx.V1.GasData
+	require.Equal(t, uint64(750), gasData.Price)
+	require.Equal(t, uint64(3_250_000*110/100), gasData.Budget)
+	require.Len(t, gasData.Payment, 2)
+	require.Equal(t, sui.MustObjectIdFromHex("0x102"), gasData.Payment[0].ObjectId)
+	require.Equal(t, sui.MustObjectIdFromHex("0x103"), gasData.Payment[1].ObjectId)
+
+	t.Run("resolves objects", func(t *testing.T) {
+		rpc.result("sui_multiGetObjects", fmt.Sprintf(`[
+			{"data":{"objectId":"0x105","version":"7","digest":"%s","owner":{"AddressOwner":"0xa11ce"}}}
+		]`, testDigest))
+		ptb := suiptb.NewTransactionDataTransactionBuilder()
+		ptb.Command(suiptb.Command{TransferObjects: &suiptb.ProgrammableTransferObjects{
+			Objects: []suiptb.Argument{ptb.ObjId(sui.MustObjectIdFromHex("0x105"))},
+			Address: ptb.MustPure(sui.MustAddressFromHex("0xb0b")),
+		}})
+		tx, err := client.BuildTransactionData(context.Background(), sender, ptb, nil)
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), tx.V1.Kind.ProgrammableTransaction.Inputs[0].Object.ImmOrOwnedObject.Version)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		_, err := client.BuildTransactionData(context.Background(), sender, ptb, &suiclient.GasOptions{GasBudget: 10_000_000})
+		require.ErrorIs(t, err, suiclient.ErrInsufficientBalance)
+	})
+}
@@ -0,0 +1,85 @@
+package gasstation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// StationClient is the sender side of a gas station served over HTTP
+type StationClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewStationClient(url string) *StationClient {
+	return &StationClient{
+		url:        strings.TrimRight(url, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to add the authentication of the application
+func (c *StationClient) WithHTTPClient(httpClient *http.Client) *StationClient {
+	c.httpClient = httpClient
+	return c
+}
+
+// Sponsor sends the transaction kind of the sender to the station, and returns the sponsored transaction to sign
+func (c *StationClient) Sponsor(ctx context.Context, sender *sui.Address, kind *suiptb.TransactionKind, gasBudget uint64) (*SponsorResponse, error) {
+	kindBytes, err := bcs.Marshal(kind)
+	if err != nil {
+		return nil, err
+	}
+	var resp SponsorResponse
+	err = c.call(ctx, PathSponsor, &SponsorRequest{Sender: sender, TransactionKind: kindBytes, GasBudget: gasBudget}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SignAndExecute signs the sponsored transaction by the sender, and lets the station execute it
+func (c *StationClient) SignAndExecute(ctx context.Context, sender suisigner.TransactionSigner, sponsored *SponsorResponse) (*ExecuteResponse, error) {
+	signature, err := sender.SignTransactionBlock(sponsored.TxBytes, suisigner.DefaultIntent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction block: %w", err)
+	}
+	var resp ExecuteResponse
+	err = c.call(ctx, PathExecute, &ExecuteRequest{ReservationId: sponsored.ReservationId, SenderSignature: &signature}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *StationClient) call(ctx context.Context, path string, reqBody any, respBody any) error {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(reqBody); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		return fmt.Errorf("gas station returned %d: %s", resp.StatusCode, errResp.Error)
+	}
+	return json.NewDecoder(resp.Body).Decode(respBody)
+}
@@ -0,0 +1,320 @@
+package gasstation_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suiclient/gasstation"
+	"github.com/pattonkan/sui-go/suiclient/internal/fakechain"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/policy"
+)
+
+const gasFee = fakechain.GasFee
+
+var (
+	allowedPackage = sui.MustPackageIdFromHex("0x1234")
+	testDigest     = sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi")
+)
+
+func newStation(t *testing.T, chain *fakechain.Chain, sponsor *suisigner.Signer, quota gasstation.Quota) *gasstation.Station {
+	station := gasstation.NewStation(&gasstation.Config{
+		Sponsor: sponsor,
+		Client:  chain,
+		Policy: &policy.Policy{
+			AllowedMoveCalls: []policy.MoveCallRule{{Package: allowedPackage}},
+			MaxGasBudget:     suiclient.DefaultGasBudget,
+		},
+		Quota: quota,
+	})
+	require.NoError(t, station.Sync(context.Background()))
+	return station
+}
+
+func moveCallKind(pkg *sui.PackageId) *suiptb.TransactionKind {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.ProgrammableMoveCall(pkg, "game", "play", nil, []suiptb.Argument{ptb.MustPure(uint64(1))})
+	pt := ptb.Finish()
+	return &suiptb.TransactionKind{ProgrammableTransaction: &pt}
+}
+
+func kindBytes(t *testing.T, kind *suiptb.TransactionKind) []byte {
+	b, err := bcs.Marshal(kind)
+	require.NoError(t, err)
+	return b
+}
+
+func TestStationOverHTTP(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee)
+	station := newStation(t, chain, sponsor, gasstation.Quota{})
+	server := httptest.NewServer(station)
+	defer server.Close()
+	client := gasstation.NewStationClient(server.URL)
+
+	for i := 0; i < 3; i++ {
+		sponsored, err := client.Sponsor(context.Background(), sender.Address, moveCallKind(allowedPackage), suiclient.DefaultGasBudget)
+		require.NoError(t, err)
+		resp, err := client.SignAndExecute(context.Background(), sender, sponsored)
+		require.NoError(t, err)
+		require.Equal(t, suiclient.ExecutionStatusSuccess, resp.Status.Status)
+	}
+	// the coin was reused with the versions from the effects
+	require.Equal(t, 3, chain.Executed)
+	require.Equal(t, uint64(97*gasFee), station.Pool().Balance())
+
+	_, err := client.Sponsor(context.Background(), sender.Address, moveCallKind(sui.MustPackageIdFromHex("0xbad")), suiclient.DefaultGasBudget)
+	require.ErrorContains(t, err, "403")
+}
+
+func TestStationReservations(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee, 100*gasFee)
+	station := newStation(t, chain, sponsor, gasstation.Quota{})
+	ctx := context.Background()
+
+	first, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.NoError(t, err)
+	second, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.NoError(t, err)
+	_, err = station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.ErrorIs(t, err, gasstation.ErrPoolExhausted)
+
+	// both reservations can be executed, since they never share a gas coin
+	for _, reservation := range []*gasstation.Reservation{second, first} {
+		signature, err := sender.SignTransactionBlock(reservation.TxBytes, suisigner.DefaultIntent())
+		require.NoError(t, err)
+		_, err = station.Execute(ctx, reservation.Id, &signature)
+		require.NoError(t, err)
+	}
+	signature, err := sender.SignTransactionBlock(first.TxBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	_, err = station.Execute(ctx, first.Id, &signature)
+	require.ErrorIs(t, err, gasstation.ErrReservationNotFound)
+
+	t.Run("expired", func(t *testing.T) {
+		now := time.Now()
+		station.Now = func() time.Time { return now }
+		reservation, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+		require.NoError(t, err)
+		now = now.Add(2 * gasstation.DefaultReservationTimeout)
+		signature, err := sender.SignTransactionBlock(reservation.TxBytes, suisigner.DefaultIntent())
+		require.NoError(t, err)
+		_, err = station.Execute(ctx, reservation.Id, &signature)
+		require.ErrorIs(t, err, gasstation.ErrReservationNotFound)
+
+		require.NoError(t, station.ReleaseExpired(ctx))
+		total, available := station.Pool().Len()
+		require.Equal(t, 2, total)
+		require.Equal(t, 2, available)
+	})
+
+	t.Run("wrong sender signature", func(t *testing.T) {
+		reservation, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+		require.NoError(t, err)
+		signature, err := sponsor.SignTransactionBlock(reservation.TxBytes, suisigner.DefaultIntent())
+		require.NoError(t, err)
+		_, err = station.Execute(ctx, reservation.Id, &signature)
+		require.Error(t, err)
+	})
+}
+
+func TestStationExecutionFailure(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee)
+	chain.Failure = "CommandArgumentError { arg_idx: 0, kind: TypeMismatch } in command 0"
+	station := newStation(t, chain, sponsor, gasstation.Quota{})
+	ctx := context.Background()
+
+	reservation, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.NoError(t, err)
+	signature, err := sender.SignTransactionBlock(reservation.TxBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	resp, err := station.Execute(ctx, reservation.Id, &signature)
+	require.NotNil(t, resp)
+	var failure *suiclient.ExecutionFailure
+	require.ErrorAs(t, err, &failure)
+	require.Equal(t, suiclient.ExecutionFailureCommandArgumentError, failure.Kind)
+	require.Equal(t, 0, *failure.Argument)
+	// the gas is charged anyway
+	require.Equal(t, uint64(99*gasFee), station.Pool().Balance())
+}
+
+func TestStationRun(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee)
+	station := gasstation.NewStation(&gasstation.Config{
+		Sponsor:            sponsor,
+		Client:             chain,
+		Policy:             &policy.Policy{AllowedMoveCalls: []policy.MoveCallRule{{Package: allowedPackage}}},
+		ReservationTimeout: time.Millisecond,
+		ReleaseInterval:    time.Millisecond,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, station.Sync(ctx))
+
+	_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.NoError(t, err)
+	_, available := station.Pool().Len()
+	require.Equal(t, 0, available)
+
+	// the coin of the expired reservation is synced back into the pool without any call to ReleaseExpired
+	done := make(chan struct{})
+	go func() {
+		station.Run(ctx)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		_, available := station.Pool().Len()
+		return available == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+}
+
+func TestStationSync(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee, 200*gasFee, 300*gasFee)
+	station := newStation(t, chain, sponsor, gasstation.Quota{})
+	ctx := context.Background()
+
+	// the smallest coin is reserved
+	_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.NoError(t, err)
+
+	// the other coins leave the sponsor, e.g. merged or transferred by another process using the same key
+	for id, balance := range chain.Balances {
+		if balance != 100*gasFee {
+			delete(chain.Balances, id)
+		}
+	}
+	require.NoError(t, station.Sync(ctx))
+	total, available := station.Pool().Len()
+	require.Equal(t, 1, total)
+	require.Equal(t, 0, available)
+	require.Equal(t, uint64(100*gasFee), station.Pool().Balance())
+}
+
+func TestStationSpendingLimit(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee, 100*gasFee, 100*gasFee)
+	// the sponsor spends the gas fee of each transaction, which is dry run by the client of the station
+	station := gasstation.NewStation(&gasstation.Config{
+		Sponsor: sponsor,
+		Client:  chain,
+		Policy: &policy.Policy{
+			AllowedMoveCalls: []policy.MoveCallRule{{Package: allowedPackage}},
+			SpendingLimits:   []policy.SpendingLimit{{CoinType: sui.SuiCoinType, Amount: 2 * gasFee, Window: time.Hour}},
+		},
+	})
+	ctx := context.Background()
+	require.NoError(t, station.Sync(ctx))
+
+	for i := 0; i < 2; i++ {
+		_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+		require.NoError(t, err)
+	}
+	_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+	require.ErrorIs(t, err, policy.ErrPolicyViolation)
+	// the coin of the rejected transaction is released
+	_, available := station.Pool().Len()
+	require.Equal(t, 1, available)
+}
+
+func TestStationRejects(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 100*gasFee, 100*gasFee, 100*gasFee)
+	station := newStation(t, chain, sponsor, gasstation.Quota{MaxTransactions: 1, Window: time.Hour})
+	ctx := context.Background()
+
+	t.Run("gas coin", func(t *testing.T) {
+		ptb := suiptb.NewTransactionDataTransactionBuilder()
+		amount := uint64(100)
+		require.NoError(t, ptb.TransferSui(sender.Address, &amount))
+		pt := ptb.Finish()
+		_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, &suiptb.TransactionKind{ProgrammableTransaction: &pt}), suiclient.DefaultGasBudget)
+		require.ErrorIs(t, err, suiclient.ErrSponsorRejected)
+	})
+
+	t.Run("coin of the pool as input", func(t *testing.T) {
+		var coin *sui.ObjectRef
+		for id, version := range chain.Versions {
+			coin = &sui.ObjectRef{ObjectId: sui.MustObjectIdFromHex(id.String()), Version: version, Digest: fakechain.Digest}
+		}
+		ptb := suiptb.NewTransactionDataTransactionBuilder()
+		require.NoError(t, ptb.TransferObject(sender.Address, coin))
+		pt := ptb.Finish()
+		_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, &suiptb.TransactionKind{ProgrammableTransaction: &pt}), suiclient.DefaultGasBudget)
+		require.ErrorIs(t, err, suiclient.ErrSponsorRejected)
+	})
+
+	t.Run("gas budget", func(t *testing.T) {
+		_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget+1)
+		require.ErrorIs(t, err, policy.ErrPolicyViolation)
+	})
+
+	t.Run("quota", func(t *testing.T) {
+		_, err := station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+		require.NoError(t, err)
+		_, err = station.Sponsor(ctx, sender.Address, kindBytes(t, moveCallKind(allowedPackage)), suiclient.DefaultGasBudget)
+		require.ErrorIs(t, err, gasstation.ErrQuotaExceeded)
+	})
+}
+
+func TestGasPoolAdd(t *testing.T) {
+	owner := sui.MustAddressFromHex("0xa11ce")
+	id := sui.MustObjectIdFromHex("0x101")
+	pool := gasstation.NewGasPool(owner)
+	pool.Add(&sui.ObjectRef{ObjectId: id, Version: 1, Digest: testDigest}, 100*gasFee)
+
+	ref, err := pool.Reserve(gasFee)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), ref.Version)
+	pool.Reconcile(&suiclient.SuiTransactionBlockEffectsV1{
+		GasUsed: suiclient.GasCostSummary{ComputationCost: sui.NewBigInt(gasFee)},
+		GasObject: suiclient.OwnedObjectRef{
+			Owner:     suiclient.WrapperTaggedJson[sui.Owner]{Data: sui.Owner{AddressOwner: owner}},
+			Reference: suiclient.SuiObjectRef{ObjectId: id, Version: 2, Digest: testDigest},
+		},
+	})
+
+	// the lagging fullnode still returns the version consumed by the reconciled transaction
+	pool.Add(&sui.ObjectRef{ObjectId: id, Version: 1, Digest: testDigest}, 100*gasFee)
+	ref, err = pool.Reserve(gasFee)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), ref.Version)
+	require.Equal(t, uint64(99*gasFee), pool.Balance())
+	pool.Release(id)
+
+	// a newer version updates the balance too
+	pool.Add(&sui.ObjectRef{ObjectId: id, Version: 3, Digest: testDigest}, 50*gasFee)
+	require.Equal(t, uint64(50*gasFee), pool.Balance())
+}
+
+func TestSplitGasCoins(t *testing.T) {
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	chain := fakechain.New(sponsor.Address, 1000*gasFee)
+	station := newStation(t, chain, sponsor, gasstation.Quota{})
+
+	require.NoError(t, station.SplitGasCoins(context.Background(), 5, 50*gasFee, suiclient.DefaultGasBudget))
+	total, available := station.Pool().Len()
+	require.Equal(t, 6, total)
+	require.Equal(t, 6, available)
+	require.Equal(t, uint64(999*gasFee), station.Pool().Balance())
+}
@@ -0,0 +1,198 @@
+package gasstation
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+var ErrPoolExhausted = errors.New("no gas coin is available")
+
+type gasCoin struct {
+	ref      *sui.ObjectRef
+	balance  uint64
+	reserved bool
+}
+
+// GasPool keeps the gas coins of the sponsor, and hands each of them to a single transaction at a time
+type GasPool struct {
+	mu    sync.Mutex
+	owner *sui.Address
+	coins map[sui.ObjectId]*gasCoin
+}
+
+func NewGasPool(owner *sui.Address) *GasPool {
+	return &GasPool{
+		owner: owner,
+		coins: make(map[sui.ObjectId]*gasCoin),
+	}
+}
+
+// Add puts a coin in the pool, or updates the coin if it is in the pool and not reserved. An older or the same
+// version, e.g. from a fullnode lagging behind the reconciled effects, leaves the coin and its balance as they are.
+func (p *GasPool) Add(ref *sui.ObjectRef, balance uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if coin, ok := p.coins[*ref.ObjectId]; ok && (coin.reserved || ref.Version <= coin.ref.Version) {
+		return
+	}
+	p.coins[*ref.ObjectId] = &gasCoin{ref: ref, balance: balance}
+}
+
+// Reserve takes the coin with the smallest balance that covers the gas budget
+func (p *GasPool) Reserve(gasBudget uint64) (*sui.ObjectRef, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var picked *gasCoin
+	for _, coin := range p.coins {
+		if coin.reserved || coin.balance < gasBudget {
+			continue
+		}
+		if picked == nil || coin.balance < picked.balance {
+			picked = coin
+		}
+	}
+	if picked == nil {
+		return nil, ErrPoolExhausted
+	}
+	picked.reserved = true
+	return picked.ref, nil
+}
+
+// Release returns a reserved coin whose transaction was never executed
+func (p *GasPool) Release(id *sui.ObjectId) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if coin, ok := p.coins[*id]; ok {
+		coin.reserved = false
+	}
+}
+
+// Remove drops the coin, e.g. when its state is unknown
+func (p *GasPool) Remove(id *sui.ObjectId) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.coins, *id)
+}
+
+// Reconcile applies the effects of an executed transaction paid by the pool.
+// The gas coin gets its new version and balance and is released, and the deleted coins are dropped.
+func (p *GasPool) Reconcile(effects *suiclient.SuiTransactionBlockEffectsV1) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, deleted := range effects.Deleted {
+		delete(p.coins, *deleted.ObjectId)
+	}
+	gasRef := effects.GasObject.Reference
+	if gasRef.ObjectId == nil {
+		return
+	}
+	coin, ok := p.coins[*gasRef.ObjectId]
+	if !ok {
+		return
+	}
+	owner := effects.GasObject.Owner.Data.AddressOwner
+	if owner == nil || *owner != *p.owner {
+		delete(p.coins, *gasRef.ObjectId)
+		return
+	}
+	fee := gasFee(&effects.GasUsed)
+	switch {
+	case fee >= 0 && uint64(fee) > coin.balance:
+		coin.balance = 0
+	case fee >= 0:
+		coin.balance -= uint64(fee)
+	default:
+		coin.balance += uint64(-fee)
+	}
+	coin.ref = gasRef.Ref()
+	coin.reserved = false
+}
+
+// Len returns the number of the coins, and the number of the ones available
+func (p *GasPool) Len() (total int, available int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, coin := range p.coins {
+		if !coin.reserved {
+			available++
+		}
+	}
+	return len(p.coins), available
+}
+
+// Balance returns the total balance of the coins in the pool
+func (p *GasPool) Balance() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var total uint64
+	for _, coin := range p.coins {
+		total += coin.balance
+	}
+	return total
+}
+
+// Debit takes the amount paid out of the coin besides the gas fee, e.g. by a split of the coin
+func (p *GasPool) Debit(id *sui.ObjectId, amount uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if coin, ok := p.coins[*id]; ok {
+		if amount > coin.balance {
+			amount = coin.balance
+		}
+		coin.balance -= amount
+	}
+}
+
+// retain drops the coins which aren't reserved and aren't in ids, e.g. the coins which left the owner
+func (p *GasPool) retain(ids map[sui.ObjectId]bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, coin := range p.coins {
+		if !coin.reserved && !ids[id] {
+			delete(p.coins, id)
+		}
+	}
+}
+
+// Contains reports whether the coin is in the pool
+func (p *GasPool) Contains(id *sui.ObjectId) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.coins[*id]
+	return ok
+}
+
+// largest reserves the coin with the largest balance
+func (p *GasPool) largest() (*sui.ObjectRef, uint64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var picked *gasCoin
+	for _, coin := range p.coins {
+		if !coin.reserved && (picked == nil || coin.balance > picked.balance) {
+			picked = coin
+		}
+	}
+	if picked == nil {
+		return nil, 0, ErrPoolExhausted
+	}
+	picked.reserved = true
+	return picked.ref, picked.balance, nil
+}
+
+// gasFee is the net gas fee, which is negative if the storage rebate is larger than the cost
+func gasFee(gasUsed *suiclient.GasCostSummary) int64 {
+	var fee int64
+	if gasUsed.ComputationCost != nil {
+		fee += gasUsed.ComputationCost.Int64()
+	}
+	if gasUsed.StorageCost != nil {
+		fee += gasUsed.StorageCost.Int64()
+	}
+	if gasUsed.StorageRebate != nil {
+		fee -= gasUsed.StorageRebate.Int64()
+	}
+	return fee
+}
@@ -0,0 +1,133 @@
+package gasstation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/policy"
+)
+
+const (
+	PathSponsor = "/v1/sponsor"
+	PathExecute = "/v1/execute"
+)
+
+// the max size of a request body, which is above the max transaction size of Sui
+const maxRequestSize = 1 << 20
+
+type SponsorRequest struct {
+	Sender *sui.Address `json:"sender"`
+	// the BCS encoded TransactionKind
+	TransactionKind sui.Base64Data `json:"transactionKind"`
+	GasBudget       uint64         `json:"gasBudget"`
+}
+
+type SponsorResponse struct {
+	ReservationId    string               `json:"reservationId"`
+	TxBytes          sui.Base64Data       `json:"txBytes"`
+	SponsorSignature *suisigner.Signature `json:"sponsorSignature"`
+	ExpiresAt        time.Time            `json:"expiresAt"`
+}
+
+type ExecuteRequest struct {
+	ReservationId   string               `json:"reservationId"`
+	SenderSignature *suisigner.Signature `json:"senderSignature"`
+}
+
+type ExecuteResponse struct {
+	Digest sui.TransactionDigest     `json:"digest"`
+	Status suiclient.ExecutionStatus `json:"status"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP serves the station to the senders. The senders are only authenticated by their signatures on
+// execution, so the station should sit behind the authentication of the application.
+func (s *Station) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	switch r.URL.Path {
+	case PathSponsor:
+		s.handleSponsor(w, r)
+	case PathExecute:
+		s.handleExecute(w, r)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Station) handleSponsor(w http.ResponseWriter, r *http.Request) {
+	var req SponsorRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	if req.Sender == nil || req.GasBudget == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("sender and gas budget are required"))
+		return
+	}
+	reservation, err := s.Sponsor(r.Context(), req.Sender, req.TransactionKind, req.GasBudget)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, SponsorResponse{
+		ReservationId:    reservation.Id,
+		TxBytes:          reservation.TxBytes,
+		SponsorSignature: reservation.SponsorSignature,
+		ExpiresAt:        reservation.ExpiresAt,
+	})
+}
+
+func (s *Station) handleExecute(w http.ResponseWriter, r *http.Request) {
+	var req ExecuteRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+	resp, err := s.Execute(r.Context(), req.ReservationId, req.SenderSignature)
+	if resp == nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	// a failed execution is still executed, so its status is returned as is
+	writeJSON(w, http.StatusOK, ExecuteResponse{
+		Digest: resp.Digest,
+		Status: resp.Effects.Data.V1.Status,
+	})
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrReservationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrPoolExhausted):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, suiclient.ErrSponsorRejected), errors.Is(err, policy.ErrPolicyViolation):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,410 @@
+// Package gasstation sponsors the transactions of other senders from a pool of gas coins.
+// Clients send their transaction kinds, the station attaches a gas coin and signs as the sponsor,
+// and executes the transaction once the sender signature arrives.
+package gasstation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/policy"
+)
+
+const (
+	DefaultReservationTimeout = time.Minute
+	DefaultReleaseInterval    = 10 * time.Second
+)
+
+var (
+	ErrQuotaExceeded       = errors.New("sender quota exceeded")
+	ErrReservationNotFound = errors.New("reservation not found or expired")
+)
+
+// Client is the part of suiclient.ClientImpl the station uses
+type Client interface {
+	GetCoins(ctx context.Context, req *suiclient.GetCoinsRequest) (*suiclient.CoinPage, error)
+	GetReferenceGasPrice(ctx context.Context) (*sui.BigInt, error)
+	// dry-runs the sponsored transactions for the spending limits and RequireDryRun of the policy
+	DryRunTransaction(ctx context.Context, txDataBytes sui.Base64Data) (*suiclient.DryRunTransactionBlockResponse, error)
+	ExecuteTransactionBlock(ctx context.Context, req *suiclient.ExecuteTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error)
+}
+
+var _ Client = (*suiclient.ClientImpl)(nil)
+
+// Quota limits the sponsoring of each sender within a sliding window, or for the lifetime of the station
+// if Window is zero. Zero limits are unlimited.
+type Quota struct {
+	MaxTransactions int
+	MaxGasBudget    uint64
+	Window          time.Duration
+}
+
+type Config struct {
+	Sponsor suisigner.TransactionSigner
+	Client  Client
+	// the rules of the sponsored transactions, e.g. the allowed move calls and the max gas budget
+	Policy *policy.Policy
+	Quota  Quota
+	// how long a sponsored transaction waits for the sender signature, DefaultReservationTimeout if zero
+	ReservationTimeout time.Duration
+	// how often Run releases the expired reservations, DefaultReleaseInterval if zero
+	ReleaseInterval time.Duration
+	// optional, the reference gas price is used if zero
+	GasPrice uint64
+}
+
+// Reservation is a sponsored transaction waiting for the signature of the sender
+type Reservation struct {
+	Id               string
+	Sender           *sui.Address
+	TxBytes          sui.Base64Data
+	SponsorSignature *suisigner.Signature
+	ExpiresAt        time.Time
+
+	gasCoin   *sui.ObjectRef
+	gasBudget uint64
+}
+
+type usage struct {
+	at        time.Time
+	gasBudget uint64
+}
+
+type Station struct {
+	sponsor            suisigner.TransactionSigner
+	guard              *policy.Guard
+	client             Client
+	quota              Quota
+	reservationTimeout time.Duration
+	releaseInterval    time.Duration
+	gasPrice           uint64
+	pool               *GasPool
+
+	// Now returns the current time, which can be replaced in tests
+	Now func() time.Time
+
+	mu           sync.Mutex
+	reservations map[string]*Reservation
+	usages       map[sui.Address][]usage
+	// whether some coins were dropped from the pool, and must be synced from the chain
+	needsSync bool
+}
+
+func NewStation(config *Config) *Station {
+	p := config.Policy
+	if p == nil {
+		p = &policy.Policy{}
+	}
+	timeout := config.ReservationTimeout
+	if timeout == 0 {
+		timeout = DefaultReservationTimeout
+	}
+	interval := config.ReleaseInterval
+	if interval == 0 {
+		interval = DefaultReleaseInterval
+	}
+	return &Station{
+		sponsor:            config.Sponsor,
+		guard:              policy.NewGuard(config.Sponsor, p, config.Client),
+		client:             config.Client,
+		quota:              config.Quota,
+		reservationTimeout: timeout,
+		releaseInterval:    interval,
+		gasPrice:           config.GasPrice,
+		pool:               NewGasPool(config.Sponsor.GetAddress()),
+		Now:                time.Now,
+		reservations:       make(map[string]*Reservation),
+		usages:             make(map[sui.Address][]usage),
+	}
+}
+
+func (s *Station) Pool() *GasPool {
+	return s.pool
+}
+
+// Sync loads the SUI coins of the sponsor into the pool, and drops the coins the sponsor no longer owns.
+// The reserved coins are kept as they are.
+func (s *Station) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	s.needsSync = false
+	s.mu.Unlock()
+
+	owned := make(map[sui.ObjectId]bool)
+	var cursor *sui.ObjectId
+	for {
+		page, err := s.client.GetCoins(ctx, &suiclient.GetCoinsRequest{Owner: s.sponsor.GetAddress(), Cursor: cursor})
+		if err != nil {
+			// synced again by the next ReleaseExpired
+			s.mu.Lock()
+			s.needsSync = true
+			s.mu.Unlock()
+			return fmt.Errorf("failed to get gas coins: %w", err)
+		}
+		for _, coin := range page.Data {
+			owned[*coin.CoinObjectId] = true
+			s.pool.Add(coin.Ref(), coin.Balance.Uint64())
+		}
+		if !page.HasNextPage || page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	s.pool.retain(owned)
+	return nil
+}
+
+// Run releases the expired reservations every ReleaseInterval until ctx is done, e.g. `go station.Run(ctx)`
+// along with the server. A failed sync of the pool is retried on the next tick.
+func (s *Station) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.releaseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.ReleaseExpired(ctx)
+		}
+	}
+}
+
+// SplitGasCoins splits the largest coin of the pool into `count` coins of `amount`, and adds them to the pool
+func (s *Station) SplitGasCoins(ctx context.Context, count int, amount uint64, gasBudget uint64) error {
+	ref, balance, err := s.pool.largest()
+	if err != nil {
+		return err
+	}
+	if balance < uint64(count)*amount+gasBudget {
+		s.pool.Release(ref.ObjectId)
+		return fmt.Errorf("gas coin %s has %d, which can't be split into %d coins of %d", ref.ObjectId, balance, count, amount)
+	}
+	gasPrice, err := s.referenceGasPrice(ctx)
+	if err != nil {
+		s.pool.Release(ref.ObjectId)
+		return err
+	}
+
+	sponsor := s.sponsor.GetAddress()
+	recipients := make([]*sui.Address, count)
+	amounts := make([]uint64, count)
+	for i := range recipients {
+		recipients[i] = sponsor
+		amounts[i] = amount
+	}
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	if err := ptb.PaySui(recipients, amounts); err != nil {
+		s.pool.Release(ref.ObjectId)
+		return err
+	}
+	tx := suiptb.NewTransactionData(sponsor, ptb.Finish(), []*sui.ObjectRef{ref}, gasBudget, gasPrice)
+	txBytes, err := bcs.Marshal(tx)
+	if err != nil {
+		s.pool.Release(ref.ObjectId)
+		return err
+	}
+	signature, err := s.sponsor.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	if err != nil {
+		s.pool.Release(ref.ObjectId)
+		return fmt.Errorf("failed to sign transaction block: %w", err)
+	}
+	// debited before the coin is released by the execution, which underestimates the balance if the split fails
+	s.pool.Debit(ref.ObjectId, uint64(count)*amount)
+	resp, err := s.execute(ctx, txBytes, []*suisigner.Signature{&signature}, ref.ObjectId)
+	if err != nil {
+		return err
+	}
+	for _, created := range resp.Effects.Data.V1.Created {
+		if owner := created.Owner.Data.AddressOwner; owner != nil && *owner == *sponsor {
+			s.pool.Add(created.Reference.Ref(), amount)
+		}
+	}
+	return nil
+}
+
+// Sponsor attaches a gas coin to the BCS encoded transaction kind of the sender, and signs it as the sponsor.
+// The transaction must be executed by Execute before the reservation expires.
+func (s *Station) Sponsor(ctx context.Context, sender *sui.Address, kindBytes []byte, gasBudget uint64) (*Reservation, error) {
+	var kind suiptb.TransactionKind
+	if _, err := bcs.Unmarshal(kindBytes, &kind); err != nil {
+		return nil, fmt.Errorf("%w: %w", suiclient.ErrSponsorRejected, err)
+	}
+	if err := suiclient.ValidateSponsoredKind(sender, &kind); err != nil {
+		return nil, err
+	}
+	// the sponsor signs the whole transaction, so its coins must not be taken as inputs
+	for _, id := range kind.ProgrammableTransaction.InputObjectIds() {
+		if s.pool.Contains(id) {
+			return nil, fmt.Errorf("%w: gas coin %s of the sponsor is used as an input", suiclient.ErrSponsorRejected, id)
+		}
+	}
+	now := s.Now()
+	if err := s.checkQuota(now, sender, gasBudget); err != nil {
+		return nil, err
+	}
+	gasPrice, err := s.referenceGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasCoin, err := s.pool.Reserve(gasBudget)
+	if err != nil {
+		return nil, err
+	}
+	tx := suiptb.NewTransactionDataAllowSponsor(*sender, *kind.ProgrammableTransaction, []*sui.ObjectRef{gasCoin}, gasBudget, gasPrice, s.sponsor.GetAddress())
+	txBytes, err := bcs.Marshal(tx)
+	if err != nil {
+		s.pool.Release(gasCoin.ObjectId)
+		return nil, err
+	}
+	signature, err := s.guard.SignTransactionBlockWithContext(ctx, txBytes, suisigner.DefaultIntent())
+	if err != nil {
+		s.pool.Release(gasCoin.ObjectId)
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		s.pool.Release(gasCoin.ObjectId)
+		return nil, err
+	}
+	reservation := &Reservation{
+		Id:               hex.EncodeToString(id),
+		Sender:           sender,
+		TxBytes:          txBytes,
+		SponsorSignature: &signature,
+		ExpiresAt:        now.Add(s.reservationTimeout),
+		gasCoin:          gasCoin,
+		gasBudget:        gasBudget,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkQuotaLocked(now, sender, gasBudget); err != nil {
+		s.pool.Release(gasCoin.ObjectId)
+		return nil, err
+	}
+	if s.quota.MaxTransactions != 0 || s.quota.MaxGasBudget != 0 {
+		s.usages[*sender] = append(s.usages[*sender], usage{at: now, gasBudget: gasBudget})
+	}
+	s.reservations[reservation.Id] = reservation
+	return reservation, nil
+}
+
+// Execute submits the reserved transaction with the signatures of the sender and the sponsor,
+// and reconciles the gas pool with the effects
+func (s *Station) Execute(ctx context.Context, reservationId string, senderSignature *suisigner.Signature) (*suiclient.SuiTransactionBlockResponse, error) {
+	s.mu.Lock()
+	reservation, ok := s.reservations[reservationId]
+	if !ok || !s.Now().Before(reservation.ExpiresAt) {
+		s.mu.Unlock()
+		return nil, ErrReservationNotFound
+	}
+	signatures, err := suiclient.SponsoredTransactionSignatures(reservation.TxBytes, senderSignature, reservation.SponsorSignature)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	// the reservation is consumed before the submission, so it can't be executed twice
+	delete(s.reservations, reservationId)
+	s.mu.Unlock()
+
+	return s.execute(ctx, reservation.TxBytes, signatures, reservation.gasCoin.ObjectId)
+}
+
+// ReleaseExpired drops the expired reservations. Their coins are synced from the chain before reuse,
+// because the sender may have executed the transaction without the station. It is called by Run.
+func (s *Station) ReleaseExpired(ctx context.Context) error {
+	now := s.Now()
+	s.mu.Lock()
+	for id, reservation := range s.reservations {
+		if !now.Before(reservation.ExpiresAt) {
+			delete(s.reservations, id)
+			s.pool.Remove(reservation.gasCoin.ObjectId)
+			s.needsSync = true
+		}
+	}
+	needsSync := s.needsSync
+	s.mu.Unlock()
+	if !needsSync {
+		return nil
+	}
+	return s.Sync(ctx)
+}
+
+// execute submits the transaction paid by the gas coin. The coin is dropped from the pool if the result is unknown.
+func (s *Station) execute(ctx context.Context, txBytes []byte, signatures []*suisigner.Signature, gasCoin *sui.ObjectId) (*suiclient.SuiTransactionBlockResponse, error) {
+	resp, err := s.client.ExecuteTransactionBlock(ctx, &suiclient.ExecuteTransactionBlockRequest{
+		TxDataBytes: txBytes,
+		Signatures:  signatures,
+		Options:     &suiclient.SuiTransactionBlockResponseOptions{ShowEffects: true},
+		RequestType: suiclient.TxnRequestTypeWaitForLocalExecution,
+	})
+	if err != nil || resp.Effects.Data.V1 == nil {
+		s.pool.Remove(gasCoin)
+		s.mu.Lock()
+		s.needsSync = true
+		s.mu.Unlock()
+		if err == nil {
+			err = errors.New("no effects returned")
+		}
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	// the gas is charged even if the execution fails
+	s.pool.Reconcile(resp.Effects.Data.V1)
+	if !resp.Effects.Data.IsSuccess() {
+		return resp, fmt.Errorf("failed to execute transaction: %w", resp.Effects.Data.V1.Status.Failure())
+	}
+	return resp, nil
+}
+
+func (s *Station) referenceGasPrice(ctx context.Context) (uint64, error) {
+	if s.gasPrice != 0 {
+		return s.gasPrice, nil
+	}
+	price, err := s.client.GetReferenceGasPrice(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reference gas price: %w", err)
+	}
+	return price.Uint64(), nil
+}
+
+func (s *Station) checkQuota(now time.Time, sender *sui.Address, gasBudget uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkQuotaLocked(now, sender, gasBudget)
+}
+
+// checkQuotaLocked must be called with the mutex held
+func (s *Station) checkQuotaLocked(now time.Time, sender *sui.Address, gasBudget uint64) error {
+	var kept []usage
+	for _, u := range s.usages[*sender] {
+		if s.quota.Window == 0 || now.Sub(u.at) < s.quota.Window {
+			kept = append(kept, u)
+		}
+	}
+	s.usages[*sender] = kept
+
+	if s.quota.MaxTransactions == 0 && s.quota.MaxGasBudget == 0 {
+		return nil
+	}
+	if s.quota.MaxTransactions != 0 && len(kept) >= s.quota.MaxTransactions {
+		return fmt.Errorf("%w: %s sent %d transactions", ErrQuotaExceeded, sender, len(kept))
+	}
+	total := gasBudget
+	for _, u := range kept {
+		total += u.gasBudget
+	}
+	if s.quota.MaxGasBudget != 0 && total > s.quota.MaxGasBudget {
+		return fmt.Errorf("%w: %s would use %d gas budget", ErrQuotaExceeded, sender, total)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Package fakechain is a fake fullnode for the tests of the executor and the gas station.
+package fakechain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// GasFee is charged to the gas coin of every transaction, and to the gas owner by DryRunTransaction
+const GasFee = 1_000_000
+
+var Digest = sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi")
+
+// Chain executes the transactions by bumping the versions of their owned objects, and rejects the ones
+// using an outdated version, or an object which is used by another transaction in flight, like a validator would.
+// The objects with a balance are the SUI coins of the owner. Holding the lock stops the execution.
+type Chain struct {
+	sync.Mutex
+	Owner    *sui.Address
+	Versions map[sui.ObjectId]uint64
+	Balances map[sui.ObjectId]uint64
+	// the immutable objects, which are only read and never get new versions
+	Frozen map[sui.ObjectId]bool
+	// the error of the failed executions, the transactions succeed if empty
+	Failure string
+	// GetCoins returns no coin, like a fullnode which hasn't indexed the transactions yet
+	Lagging  atomic.Bool
+	Executed int
+	// the number of the transactions submitted so far
+	Submitted atomic.Int32
+
+	inFlight map[sui.ObjectId]bool
+	nextId   int
+}
+
+// New creates a chain with a coin of the owner for each balance
+func New(owner *sui.Address, balances ...uint64) *Chain {
+	chain := &Chain{
+		Owner:    owner,
+		Versions: make(map[sui.ObjectId]uint64),
+		Balances: make(map[sui.ObjectId]uint64),
+		Frozen:   make(map[sui.ObjectId]bool),
+		inFlight: make(map[sui.ObjectId]bool),
+	}
+	for _, balance := range balances {
+		chain.Create(balance)
+	}
+	return chain
+}
+
+// Create must be called with the lock held, or before the chain is shared.
+// The object is a coin unless the balance is zero.
+func (f *Chain) Create(balance uint64) *sui.ObjectRef {
+	f.nextId++
+	id := sui.MustObjectIdFromHex(fmt.Sprintf("0x%x", 0x100+f.nextId))
+	f.Versions[*id] = 1
+	f.Balances[*id] = balance
+	return &sui.ObjectRef{ObjectId: id, Version: 1, Digest: Digest}
+}
+
+func (f *Chain) GetCoins(ctx context.Context, req *suiclient.GetCoinsRequest) (*suiclient.CoinPage, error) {
+	f.Lock()
+	defer f.Unlock()
+	page := &suiclient.CoinPage{}
+	if f.Lagging.Load() {
+		return page, nil
+	}
+	for id, balance := range f.Balances {
+		if balance == 0 {
+			continue
+		}
+		page.Data = append(page.Data, &suiclient.Coin{
+			CoinType:     sui.SuiCoinType,
+			CoinObjectId: sui.MustObjectIdFromHex(id.String()),
+			Version:      sui.NewBigInt(f.Versions[id]),
+			Digest:       Digest,
+			Balance:      sui.NewBigInt(balance),
+		})
+	}
+	return page, nil
+}
+
+func (f *Chain) GetReferenceGasPrice(ctx context.Context) (*sui.BigInt, error) {
+	return sui.NewBigInt(suiclient.DefaultGasPrice), nil
+}
+
+// DryRunTransaction charges the gas fee to the gas owner
+func (f *Chain) DryRunTransaction(ctx context.Context, txDataBytes sui.Base64Data) (*suiclient.DryRunTransactionBlockResponse, error) {
+	var tx suiptb.TransactionData
+	if _, err := bcs.Unmarshal(txDataBytes, &tx); err != nil {
+		return nil, err
+	}
+	return &suiclient.DryRunTransactionBlockResponse{
+		Effects: suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+			Data: suiclient.SuiTransactionBlockEffects{V1: &suiclient.SuiTransactionBlockEffectsV1{
+				Status: suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess},
+			}},
+		},
+		BalanceChanges: []suiclient.BalanceChange{{
+			Owner:    suiclient.ObjectOwner{ObjectOwnerInternal: &suiclient.ObjectOwnerInternal{AddressOwner: tx.V1.GasData.Owner}},
+			CoinType: sui.SuiCoinType,
+			Amount:   fmt.Sprintf("-%d", GasFee),
+		}},
+	}, nil
+}
+
+func (f *Chain) ExecuteTransactionBlock(ctx context.Context, req *suiclient.ExecuteTransactionBlockRequest) (*suiclient.SuiTransactionBlockResponse, error) {
+	var tx suiptb.TransactionData
+	if _, err := bcs.Unmarshal(req.TxDataBytes, &tx); err != nil {
+		return nil, err
+	}
+	if len(req.Signatures) == 0 {
+		return nil, fmt.Errorf("transaction has no signature")
+	}
+	if _, err := suiclient.SponsoredTransactionSignatures(req.TxDataBytes, req.Signatures[0], req.Signatures[len(req.Signatures)-1]); err != nil {
+		return nil, err
+	}
+	f.Submitted.Add(1)
+	gas := tx.V1.GasData.Payment[0]
+	refs := []*sui.ObjectRef{gas}
+	f.Lock()
+	for _, input := range tx.V1.Kind.ProgrammableTransaction.Inputs {
+		if input.Object != nil && input.Object.ImmOrOwnedObject != nil && !f.Frozen[*input.Object.ImmOrOwnedObject.ObjectId] {
+			refs = append(refs, input.Object.ImmOrOwnedObject)
+		}
+	}
+	for _, ref := range refs {
+		if f.inFlight[*ref.ObjectId] {
+			f.Unlock()
+			return nil, fmt.Errorf("object %s is equivocated", ref.ObjectId)
+		}
+		if f.Versions[*ref.ObjectId] != ref.Version {
+			f.Unlock()
+			return nil, fmt.Errorf("object %s is not available for consumption", ref.ObjectId)
+		}
+	}
+	for _, ref := range refs {
+		f.inFlight[*ref.ObjectId] = true
+	}
+	f.Unlock()
+	// the window in which another transaction could take the same objects
+	time.Sleep(time.Millisecond)
+	f.Lock()
+	defer f.Unlock()
+
+	owner := suiclient.WrapperTaggedJson[sui.Owner]{Data: sui.Owner{AddressOwner: f.Owner}}
+	effects := &suiclient.SuiTransactionBlockEffectsV1{
+		Status: suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess},
+		GasUsed: suiclient.GasCostSummary{
+			ComputationCost: sui.NewBigInt(GasFee),
+			StorageCost:     sui.NewBigInt(0),
+			StorageRebate:   sui.NewBigInt(0),
+		},
+	}
+	for _, ref := range refs {
+		delete(f.inFlight, *ref.ObjectId)
+		f.Versions[*ref.ObjectId]++
+		effects.Mutated = append(effects.Mutated, suiclient.OwnedObjectRef{
+			Owner:     owner,
+			Reference: suiclient.SuiObjectRef{ObjectId: ref.ObjectId, Version: f.Versions[*ref.ObjectId], Digest: Digest},
+		})
+	}
+	effects.GasObject = effects.Mutated[0]
+	f.Balances[*gas.ObjectId] -= GasFee
+	if f.Failure != "" {
+		effects.Status = suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusFailure, Error: f.Failure}
+	}
+	// the split of the gas coins pays the owner itself
+	if tx.V1.Sender == *f.Owner && f.Failure == "" {
+		for _, command := range tx.V1.Kind.ProgrammableTransaction.Commands {
+			if command.SplitCoins == nil {
+				continue
+			}
+			for _, amount := range command.SplitCoins.Amounts {
+				var value uint64
+				_, err := bcs.Unmarshal(*tx.V1.Kind.ProgrammableTransaction.Inputs[*amount.Input].Pure, &value)
+				if err != nil {
+					return nil, err
+				}
+				created := f.Create(value)
+				f.Balances[*gas.ObjectId] -= value
+				effects.Created = append(effects.Created, suiclient.OwnedObjectRef{
+					Owner:     owner,
+					Reference: suiclient.SuiObjectRef{ObjectId: created.ObjectId, Version: 1, Digest: Digest},
+				})
+			}
+		}
+	}
+	f.Executed++
+
+	resp := &suiclient.SuiTransactionBlockResponse{Digest: *Digest}
+	resp.Effects = &suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+		Data: suiclient.SuiTransactionBlockEffects{V1: effects},
+	}
+	return resp, nil
+}
@@ -0,0 +1,166 @@
+package suiclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/fardream/go-bcs/bcs"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+// MoveAbortError is a Move abort parsed from an execution error like
+// `MoveAbort(MoveLocation { module: ModuleId { address: ..., name: Identifier("coin") }, function: 3,
+// instruction: 9, function_name: Some("split") }, 2) in command 0`
+type MoveAbortError struct {
+	Address  *sui.Address
+	Module   sui.Identifier
+	Function sui.Identifier
+	// the index of the function in the module, and of the instruction in the function
+	FunctionIndex uint64
+	Instruction   uint64
+	Code          uint64
+	// the name of the code registered by movebcs.RegisterAbortCode(), empty if it isn't registered
+	Name string
+	// the resolved clever error of a Move 2024 `#[error]` abort, see DecodeCleverError()
+	Clever *movebcs.CleverError
+	// the index of the failed command, nil if the error doesn't tell
+	Command *int
+	// the package called by the failed MoveCall command, which is an upgrade of Address if the package was upgraded,
+	// nil if the transaction isn't known
+	PackageId *sui.PackageId
+	// the error returned by the fullnode
+	Raw string
+}
+
+func (e *MoveAbortError) Error() string {
+	location := fmt.Sprintf("%s::%s", e.Address.ShortString(), e.Module)
+	if e.Function != "" {
+		location += "::" + string(e.Function)
+	}
+	reason := fmt.Sprintf("code %d", e.Code)
+	if e.Clever != nil {
+		reason = e.Clever.String()
+	} else if e.Name != "" {
+		reason += fmt.Sprintf(" (%s)", e.Name)
+	}
+	if e.Command != nil {
+		return fmt.Sprintf("move abort in %s with %s in command %d", location, reason, *e.Command)
+	}
+	return fmt.Sprintf("move abort in %s with %s", location, reason)
+}
+
+var moveAbortRegex = regexp.MustCompile(`MoveAbort\(MoveLocation \{ module: ModuleId \{ address: (?:0x)?([0-9a-fA-F]+), name: Identifier\("([^"]*)"\) \}, ` +
+	`function: (\d+), instruction: (\d+), function_name: (?:Some\("([^"]*)"\)|None) \}, (\d+)\)(?: in command (\d+))?`)
+
+// ParseMoveAbort parses the Move abort in the error of an execution status or a DevInspect, and returns
+// false if the error isn't a Move abort
+func ParseMoveAbort(errorString string) (*MoveAbortError, bool) {
+	m := moveAbortRegex.FindStringSubmatch(errorString)
+	if m == nil {
+		return nil, false
+	}
+	address, err := sui.AddressFromHex(m[1])
+	if err != nil {
+		return nil, false
+	}
+	abort := &MoveAbortError{
+		Address:  address,
+		Module:   sui.Identifier(m[2]),
+		Function: sui.Identifier(m[5]),
+		Raw:      errorString,
+	}
+	if abort.FunctionIndex, err = strconv.ParseUint(m[3], 10, 64); err != nil {
+		return nil, false
+	}
+	if abort.Instruction, err = strconv.ParseUint(m[4], 10, 64); err != nil {
+		return nil, false
+	}
+	if abort.Code, err = strconv.ParseUint(m[6], 10, 64); err != nil {
+		return nil, false
+	}
+	if m[7] != "" {
+		command, err := strconv.Atoi(m[7])
+		if err != nil {
+			return nil, false
+		}
+		abort.Command = &command
+	}
+	abort.Name, _ = movebcs.AbortCodeName(abort.Address, abort.Module, abort.Code)
+	return abort, true
+}
+
+// DecodeCleverError resolves the code of a Move 2024 `#[error]` abort by the bytecode of the module which
+// aborted, and sets it to abort.Clever. An abort code which isn't a clever error is left as it is.
+// The abort is located at the original id of the package, whose bytecode is older than the one which aborted
+// if the package was upgraded, so the module is taken from abort.PackageId when that package has it.
+// It falls back to the module at abort.Address, e.g. for an abort in a dependency or of an unknown transaction.
+func (s *ClientImpl) DecodeCleverError(ctx context.Context, abort *MoveAbortError) error {
+	if _, ok := movebcs.ParseCleverErrorCode(abort.Code); !ok {
+		return nil
+	}
+	var bytecode []byte
+	if abort.PackageId != nil && *abort.PackageId != *abort.Address {
+		bytecode, _ = s.getModuleBytecode(ctx, abort.PackageId, abort.Module)
+	}
+	if bytecode == nil {
+		var err error
+		if bytecode, err = s.getModuleBytecode(ctx, abort.Address, abort.Module); err != nil {
+			return err
+		}
+	}
+	module, err := movebcs.ParseCompiledModule(bytecode)
+	if err != nil {
+		return fmt.Errorf("can't parse module %s: %w", abort.Module, err)
+	}
+	abort.Clever, err = movebcs.ResolveCleverError(module, abort.Code)
+	return err
+}
+
+// getModuleBytecode fetches the bytecode of a module of the package
+func (s *ClientImpl) getModuleBytecode(ctx context.Context, packageId *sui.PackageId, moduleName sui.Identifier) ([]byte, error) {
+	resp, err := s.GetObject(ctx, &GetObjectRequest{
+		ObjectId: packageId,
+		Options:  &SuiObjectDataOptions{ShowBcs: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get package %s: %w", packageId, err)
+	}
+	if resp.Data == nil || resp.Data.Bcs == nil || resp.Data.Bcs.Data.Package == nil {
+		return nil, fmt.Errorf("%s isn't a package", packageId)
+	}
+	bytecode, ok := resp.Data.Bcs.Data.Package.ModuleMap[string(moduleName)]
+	if !ok {
+		return nil, fmt.Errorf("package %s has no module %s", packageId, moduleName)
+	}
+	return bytecode, nil
+}
+
+// decodeFailure resolves the clever error of a Move abort when it can, and returns the failure.
+// The transaction tells the package of the failed MoveCall, and may be nil.
+func (s *ClientImpl) decodeFailure(ctx context.Context, failure *ExecutionFailure, pt *suiptb.ProgrammableTransaction) *ExecutionFailure {
+	if failure != nil && failure.MoveAbort != nil {
+		abort := failure.MoveAbort
+		if pt != nil && abort.Command != nil && *abort.Command < len(pt.Commands) {
+			if call := pt.Commands[*abort.Command].MoveCall; call != nil {
+				abort.PackageId = call.Package
+			}
+		}
+		// the raw abort code is still reported if the module can't be fetched
+		_ = s.DecodeCleverError(ctx, abort)
+	}
+	return failure
+}
+
+// decodeProgrammableTransaction returns the programmable transaction of the TransactionData, nil if it isn't one
+func decodeProgrammableTransaction(txBytes []byte) *suiptb.ProgrammableTransaction {
+	var tx suiptb.TransactionData
+	if _, err := bcs.Unmarshal(txBytes, &tx); err != nil || tx.V1 == nil {
+		return nil
+	}
+	return tx.V1.Kind.ProgrammableTransaction
+}
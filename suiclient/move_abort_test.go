@@ -0,0 +1,101 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// the pool module with the identifiers [EPaused, pool] and the constants [b"Pool is paused", 7u64]
+const pausedModule = "oRzrCwYAAAACBwANBg0cB0VQYXVzZWQEcG9vbAoCDw5Qb29sIGlzIHBhdXNlZAMIBwAAAAAAAAA="
+
+func TestDecodeCleverError(t *testing.T) {
+	pool := sui.MustObjectIdFromHex("0x55")
+	rpc, client := newFakeRPC(t)
+	rpc.result("sui_getNormalizedMoveFunction", balanceFunction)
+	rpc.result("sui_getNormalizedMoveStruct", `{"abilities":{"abilities":["Drop"]},"typeParameters":[],"fields":[]}`)
+	rpc.result("sui_multiGetObjects", []map[string]any{{"data": map[string]any{
+		"objectId": pool.String(), "version": "3", "digest": testDigest.String(),
+		"owner": map[string]any{"Shared": map[string]any{"initial_shared_version": 2}},
+	}}})
+	// EPaused at line 42
+	abortError := `MoveAbort(MoveLocation { module: ModuleId { address: 0000000000000000000000000000000000000000000000000000000000001234, ` +
+		`name: Identifier("pool") }, function: 3, instruction: 9, function_name: Some("balance") }, 9223372217243402240) in command 0`
+	rpc.result("sui_devInspectTransactionBlock", fmt.Sprintf(`{"effects":{"messageVersion":"v1","status":{"status":"failure"}},"events":[],"error":%q}`, abortError))
+	rpc.handle("sui_getObject", func(params []json.RawMessage) (any, error) {
+		var id sui.ObjectId
+		require.NoError(t, json.Unmarshal(params[0], &id))
+		require.Equal(t, sui.MustObjectIdFromHex("0x1234"), &id)
+		return map[string]any{"data": map[string]any{
+			"objectId": id.String(), "version": "1", "digest": testDigest.String(),
+			"bcs": map[string]any{"dataType": "package", "id": id.String(), "version": 1, "moduleMap": map[string]any{"pool": pausedModule}},
+		}}, nil
+	})
+
+	_, err := client.View(context.Background(), "0x1234::pool::balance", []sui.TypeTag{*sui.MustNewTypeTag("0x2::sui::SUI")}, pool)
+	var abort *suiclient.MoveAbortError
+	require.True(t, errors.As(err, &abort))
+	require.NotNil(t, abort.Clever)
+	require.Equal(t, uint16(42), abort.Clever.Line)
+	require.Equal(t, "EPaused", abort.Clever.Name)
+	require.Equal(t, "move abort in 0x1234::pool::balance with EPaused: Pool is paused in command 0", abort.Error())
+
+	// a plain abort code isn't fetched
+	abort = &suiclient.MoveAbortError{Address: sui.MustAddressFromHex("0x2"), Module: "coin", Code: 2}
+	require.NoError(t, client.DecodeCleverError(context.Background(), abort))
+	require.Nil(t, abort.Clever)
+	require.Equal(t, 1, rpc.count("sui_getObject"))
+
+	abort = &suiclient.MoveAbortError{Address: sui.MustAddressFromHex("0x1234"), Module: "vault", Code: 9223372217243402240}
+	require.ErrorContains(t, client.DecodeCleverError(context.Background(), abort), "has no module vault")
+}
+
+func TestDecodeCleverErrorUpgradedPackage(t *testing.T) {
+	pool := sui.MustObjectIdFromHex("0x55")
+	original := sui.MustPackageIdFromHex("0x1234")
+	upgraded := sui.MustPackageIdFromHex("0x5678")
+	rpc, client := newFakeRPC(t)
+	rpc.result("sui_getNormalizedMoveFunction", balanceFunction)
+	rpc.result("sui_getNormalizedMoveStruct", `{"abilities":{"abilities":["Drop"]},"typeParameters":[],"fields":[]}`)
+	rpc.result("sui_multiGetObjects", []map[string]any{{"data": map[string]any{
+		"objectId": pool.String(), "version": "3", "digest": testDigest.String(),
+		"owner": map[string]any{"Shared": map[string]any{"initial_shared_version": 2}},
+	}}})
+	// the abort of the upgraded package is located at the original id
+	abortError := `MoveAbort(MoveLocation { module: ModuleId { address: 0000000000000000000000000000000000000000000000000000000000001234, ` +
+		`name: Identifier("pool") }, function: 3, instruction: 9, function_name: Some("balance") }, 9223372217243402240) in command 0`
+	rpc.result("sui_devInspectTransactionBlock", fmt.Sprintf(`{"effects":{"messageVersion":"v1","status":{"status":"failure"}},"events":[],"error":%q}`, abortError))
+	rpc.handle("sui_getObject", func(params []json.RawMessage) (any, error) {
+		var id sui.ObjectId
+		require.NoError(t, json.Unmarshal(params[0], &id))
+		// the pool module was added by the upgrade
+		moduleMap := map[string]any{}
+		if id == *upgraded {
+			moduleMap["pool"] = pausedModule
+		}
+		return map[string]any{"data": map[string]any{
+			"objectId": id.String(), "version": "1", "digest": testDigest.String(),
+			"bcs": map[string]any{"dataType": "package", "id": id.String(), "version": 1, "moduleMap": moduleMap},
+		}}, nil
+	})
+
+	_, err := client.View(context.Background(), "0x5678::pool::balance", []sui.TypeTag{*sui.MustNewTypeTag("0x2::sui::SUI")}, pool)
+	var abort *suiclient.MoveAbortError
+	require.True(t, errors.As(err, &abort))
+	require.Equal(t, upgraded, abort.PackageId)
+	require.NotNil(t, abort.Clever)
+	require.Equal(t, "EPaused", abort.Clever.Name)
+
+	// the called package has no module of the abort, e.g. of an abort in a dependency, which is taken from Address
+	dependency := upgraded
+	abort = &suiclient.MoveAbortError{Address: dependency, Module: "pool", Code: 9223372217243402240, PackageId: original}
+	require.NoError(t, client.DecodeCleverError(context.Background(), abort))
+	require.Equal(t, "EPaused", abort.Clever.Name)
+}
@@ -0,0 +1,193 @@
+package suiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+var ErrMoveCallMismatch = errors.New("move call doesn't match the function signature")
+
+// AddMoveCall adds a MoveCall to the PTB after checking the type arguments and the arguments against
+// the normalized Move function. Each argument is one of
+//   - a suiptb.Argument, e.g. the result of a previous command, which is passed as is
+//   - a Go value for a pure parameter, which is encoded by suiptb.EncodePure() and must match the Move type exactly
+//   - a *sui.ObjectId for an object parameter, which is added by ptb.ObjId() and resolved by ResolveObjects()
+//   - a *sui.ObjectRef or a suiptb.ObjectArg for an object parameter
+//
+// The trailing TxContext parameter is passed by the runtime, so it takes no argument.
+func (s *ClientImpl) AddMoveCall(
+	ctx context.Context,
+	ptb *suiptb.ProgrammableTransactionBuilder,
+	packageId *sui.PackageId,
+	module sui.Identifier,
+	function sui.Identifier,
+	typeArgs []sui.TypeTag,
+	args ...any,
+) (suiptb.Argument, error) {
+	fn, err := s.GetNormalizedMoveFunction(ctx, packageId, module, function)
+	if err != nil {
+		return suiptb.Argument{}, fmt.Errorf("failed to get Move function %s::%s::%s: %w", packageId, module, function, err)
+	}
+	return s.addMoveCall(ctx, ptb, fn, packageId, module, function, typeArgs, args)
+}
+
+func (s *ClientImpl) addMoveCall(
+	ctx context.Context,
+	ptb *suiptb.ProgrammableTransactionBuilder,
+	fn *sui.MoveNormalizedFunction,
+	packageId *sui.PackageId,
+	module sui.Identifier,
+	function sui.Identifier,
+	typeArgs []sui.TypeTag,
+	args []any,
+) (suiptb.Argument, error) {
+	target := fmt.Sprintf("%s::%s::%s", packageId, module, function)
+	if len(typeArgs) != len(fn.TypeParameters) {
+		return suiptb.Argument{}, fmt.Errorf("%w: %s takes %d type arguments, got %d", ErrMoveCallMismatch, target, len(fn.TypeParameters), len(typeArgs))
+	}
+	abilities := &abilityChecker{client: s, structs: make(map[string]*sui.MoveNormalizedStruct)}
+	for i, constraints := range fn.TypeParameters {
+		has, err := abilities.of(ctx, &typeArgs[i])
+		if err != nil {
+			return suiptb.Argument{}, err
+		}
+		for _, ability := range constraints.Abilities {
+			if !has.Has(ability) {
+				return suiptb.Argument{}, fmt.Errorf("%w: type argument %d of %s must have %s, but %s doesn't", ErrMoveCallMismatch, i, target, sui.MoveAbility(ability), typeArgs[i].String())
+			}
+		}
+	}
+
+	params := fn.Parameters
+	if len(params) > 0 && params[len(params)-1].IsTxContext() {
+		params = params[:len(params)-1]
+	}
+	if len(args) != len(params) {
+		return suiptb.Argument{}, fmt.Errorf("%w: %s takes %d arguments, got %d", ErrMoveCallMismatch, target, len(params), len(args))
+	}
+
+	arguments := make([]suiptb.Argument, len(args))
+	for i, arg := range args {
+		var err error
+		arguments[i], err = moveCallArgument(ptb, &params[i], typeArgs, arg)
+		if err != nil {
+			return suiptb.Argument{}, fmt.Errorf("argument %d of %s: %w", i, target, err)
+		}
+	}
+	return ptb.Command(suiptb.Command{
+		MoveCall: &suiptb.ProgrammableMoveCall{
+			Package:       packageId,
+			Module:        module,
+			Function:      function,
+			TypeArguments: typeArgs,
+			Arguments:     arguments,
+		},
+	}), nil
+}
+
+func moveCallArgument(ptb *suiptb.ProgrammableTransactionBuilder, param *sui.MoveNormalizedType, typeArgs []sui.TypeTag, arg any) (suiptb.Argument, error) {
+	if arg, ok := arg.(suiptb.Argument); ok {
+		return arg, nil
+	}
+	tag, err := param.Dereference().TypeTag(typeArgs)
+	if err != nil {
+		return suiptb.Argument{}, err
+	}
+	if suiptb.IsPureType(tag) {
+		// a pure input can be borrowed too, e.g. by `&String` or `&vector<u8>`
+		b, err := suiptb.EncodePure(tag, arg)
+		if err != nil {
+			return suiptb.Argument{}, err
+		}
+		return ptb.Input(suiptb.CallArg{Pure: &b})
+	}
+	switch arg := arg.(type) {
+	case *sui.ObjectId:
+		return ptb.ObjId(arg), nil
+	case *sui.ObjectRef:
+		return ptb.Obj(suiptb.ObjectArg{ImmOrOwnedObject: arg})
+	case suiptb.ObjectArg:
+		return ptb.Obj(arg)
+	default:
+		return suiptb.Argument{}, fmt.Errorf("%w: expected an object of %s, got %T", ErrMoveCallMismatch, tag, arg)
+	}
+}
+
+// abilityChecker finds the abilities of the types, with the normalized structs fetched once
+type abilityChecker struct {
+	client  *ClientImpl
+	structs map[string]*sui.MoveNormalizedStruct
+}
+
+var primitiveAbilities = sui.MoveAbilitySet{Abilities: []sui.MoveAbility{sui.MoveAbilityCopy, sui.MoveAbilityDrop, sui.MoveAbilityStore}}
+
+func (c *abilityChecker) of(ctx context.Context, tag *sui.TypeTag) (sui.MoveAbilitySet, error) {
+	switch {
+	case tag.Signer != nil:
+		return sui.MoveAbilitySet{Abilities: []sui.MoveAbility{sui.MoveAbilityDrop}}, nil
+	case tag.Vector != nil:
+		elem, err := c.of(ctx, tag.Vector)
+		if err != nil {
+			return sui.MoveAbilitySet{}, err
+		}
+		return intersectAbilities(primitiveAbilities, elem), nil
+	case tag.Struct != nil:
+		return c.ofStruct(ctx, tag.Struct)
+	default:
+		return primitiveAbilities, nil
+	}
+}
+
+// ofStruct follows the Move rule: a generic struct has a declared ability only if each non-phantom type argument
+// has the same ability, or `store` for `key`
+func (c *abilityChecker) ofStruct(ctx context.Context, tag *sui.StructTag) (sui.MoveAbilitySet, error) {
+	key := fmt.Sprintf("%s::%s::%s", tag.Address, tag.Module, tag.Name)
+	normalized, ok := c.structs[key]
+	if !ok {
+		var err error
+		normalized, err = c.client.GetNormalizedMoveStruct(ctx, tag.Address, tag.Module, tag.Name)
+		if err != nil {
+			return sui.MoveAbilitySet{}, fmt.Errorf("failed to get Move struct %s: %w", key, err)
+		}
+		c.structs[key] = normalized
+	}
+	if len(normalized.TypeParameters) != len(tag.TypeParams) {
+		return sui.MoveAbilitySet{}, fmt.Errorf("%w: %s takes %d type arguments, got %d", ErrMoveCallMismatch, key, len(normalized.TypeParameters), len(tag.TypeParams))
+	}
+	abilities := normalized.Abilities
+	for i, param := range normalized.TypeParameters {
+		if param.IsPhantom {
+			continue
+		}
+		argAbilities, err := c.of(ctx, &tag.TypeParams[i])
+		if err != nil {
+			return sui.MoveAbilitySet{}, err
+		}
+		var kept []sui.MoveAbility
+		for _, ability := range abilities.Abilities {
+			required := ability
+			if ability == sui.MoveAbilityKey {
+				required = sui.MoveAbilityStore
+			}
+			if argAbilities.Has(required) {
+				kept = append(kept, ability)
+			}
+		}
+		abilities = sui.MoveAbilitySet{Abilities: kept}
+	}
+	return abilities, nil
+}
+
+func intersectAbilities(a sui.MoveAbilitySet, b sui.MoveAbilitySet) sui.MoveAbilitySet {
+	var abilities []sui.MoveAbility
+	for _, ability := range a.Abilities {
+		if b.Has(ability) {
+			abilities = append(abilities, ability)
+		}
+	}
+	return sui.MoveAbilitySet{Abilities: abilities}
+}
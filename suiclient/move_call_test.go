@@ -0,0 +1,111 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// mint<T: store>(pool: &mut Pool<T>, amount: u64, memo: Option<String>, tags: vector<vector<u8>>, ctx: &mut TxContext)
+const mintFunction = `{
+	"visibility":"Public","isEntry":false,"return":[],
+	"typeParameters":[{"abilities":["Store"]}],
+	"parameters":[
+		{"MutableReference":{"Struct":{"address":"0x1234","module":"pool","name":"Pool","typeArguments":[{"TypeParameter":0}]}}},
+		"U64",
+		{"Struct":{"address":"0x1","module":"option","name":"Option","typeArguments":[
+			{"Struct":{"address":"0x1","module":"string","name":"String","typeArguments":[]}}]}},
+		{"Vector":{"Vector":"U8"}},
+		{"MutableReference":{"Struct":{"address":"0x2","module":"tx_context","name":"TxContext","typeArguments":[]}}}
+	]}`
+
+func TestAddMoveCall(t *testing.T) {
+	pkg := sui.MustPackageIdFromHex("0x1234")
+	pool := sui.MustObjectIdFromHex("0x55")
+
+	rpc, client := newFakeRPC(t)
+	rpc.result("sui_getNormalizedMoveFunction", mintFunction)
+	rpc.handle("sui_getNormalizedMoveStruct", func(params []json.RawMessage) (any, error) {
+		var name string
+		require.NoError(t, json.Unmarshal(params[2], &name))
+		switch name {
+		case "Coin":
+			return json.RawMessage(`{"abilities":{"abilities":["Store","Key"]},"typeParameters":[{"constraints":{"abilities":[]},"isPhantom":true}],"fields":[]}`), nil
+		default:
+			// a hot potato without abilities
+			return json.RawMessage(`{"abilities":{"abilities":[]},"typeParameters":[],"fields":[]}`), nil
+		}
+	})
+	coinType := *sui.MustNewTypeTag("0x2::coin::Coin<0x2::sui::SUI>")
+	memo := "gm"
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	result, err := client.AddMoveCall(context.Background(), ptb, pkg, "pool", "mint", []sui.TypeTag{coinType},
+		pool, uint64(100), &memo, [][]byte{[]byte("a")})
+	require.NoError(t, err)
+	require.Equal(t, uint16(0), *result.Result)
+
+	require.Len(t, ptb.UnresolvedObjects(), 1)
+	require.NoError(t, ptb.ResolveObject(suiptb.ObjectArg{ImmOrOwnedObject: &sui.ObjectRef{ObjectId: pool, Version: 1, Digest: testDigest}}))
+	pt := ptb.Finish()
+	expectedAmount, err := bcs.Marshal(uint64(100))
+	require.NoError(t, err)
+	require.Equal(t, expectedAmount, *pt.Inputs[1].Pure)
+	require.Equal(t, []byte{1, 2, 'g', 'm'}, *pt.Inputs[2].Pure)
+	require.Equal(t, []byte{1, 1, 'a'}, *pt.Inputs[3].Pure)
+
+	mismatches := []struct {
+		name     string
+		typeArgs []sui.TypeTag
+		args     []any
+	}{
+		{"int instead of u64", []sui.TypeTag{coinType}, []any{pool, 100, &memo, [][]byte{}}},
+		{"string instead of vector<u8>", []sui.TypeTag{coinType}, []any{pool, uint64(100), &memo, []string{"a"}}},
+		{"missing argument", []sui.TypeTag{coinType}, []any{pool, uint64(100), &memo}},
+		{"missing type argument", nil, []any{pool, uint64(100), &memo, [][]byte{}}},
+		{"ability", []sui.TypeTag{*sui.MustNewTypeTag("0x1234::pool::Receipt")}, []any{pool, uint64(100), &memo, [][]byte{}}},
+		{"pure for object", []sui.TypeTag{coinType}, []any{uint64(1), uint64(100), &memo, [][]byte{}}},
+	}
+	for _, tt := range mismatches {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.AddMoveCall(context.Background(), suiptb.NewTransactionDataTransactionBuilder(), pkg, "pool", "mint", tt.typeArgs, tt.args...)
+			require.Error(t, err)
+			require.True(t, errorIsAny(err, suiclient.ErrMoveCallMismatch, suiptb.ErrPureTypeMismatch), err.Error())
+		})
+	}
+}
+
+func TestAddMoveCallPureReference(t *testing.T) {
+	rpc, client := newFakeRPC(t)
+	// greet(name: &String, data: &mut vector<u8>)
+	rpc.result("sui_getNormalizedMoveFunction", `{
+		"visibility":"Public","isEntry":false,"return":[],"typeParameters":[],
+		"parameters":[
+			{"Reference":{"Struct":{"address":"0x1","module":"string","name":"String","typeArguments":[]}}},
+			{"MutableReference":{"Vector":"U8"}}
+		]}`)
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	_, err := client.AddMoveCall(context.Background(), ptb, sui.MustPackageIdFromHex("0x1234"), "hello", "greet", nil, "gm", []byte{1})
+	require.NoError(t, err)
+	pt := ptb.Finish()
+	require.Equal(t, []byte{2, 'g', 'm'}, *pt.Inputs[0].Pure)
+	require.Equal(t, []byte{1, 1}, *pt.Inputs[1].Pure)
+}
+
+func errorIsAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
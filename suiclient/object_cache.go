@@ -0,0 +1,138 @@
+package suiclient
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient/conn"
+)
+
+// ObjectRefCache keeps the latest refs and owners of objects, from the effects of the executed transactions
+// and from the objects fetched while building them, so the next transactions are built without refetching
+// the objects. An entry which turns out to be stale, e.g. because another client used the object, is
+// invalidated when a transaction using it is rejected.
+type ObjectRefCache struct {
+	mu      sync.RWMutex
+	objects map[sui.ObjectId]*cachedObject
+}
+
+// cachedObject is the latest known version of an object, whose ref is nil if the object is deleted or wrapped
+type cachedObject struct {
+	version uint64
+	ref     *sui.ObjectRef
+	owner   *sui.Owner
+}
+
+func NewObjectRefCache() *ObjectRefCache {
+	return &ObjectRefCache{objects: make(map[sui.ObjectId]*cachedObject)}
+}
+
+// Get returns the latest ref and owner of the object, or false if the object isn't cached, or is deleted or wrapped
+func (c *ObjectRefCache) Get(id *sui.ObjectId) (*sui.ObjectRef, *sui.Owner, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	object, ok := c.objects[*id]
+	if !ok || object.ref == nil {
+		return nil, nil, false
+	}
+	return object.ref, object.owner, true
+}
+
+// Put stores the ref and the owner of the object, unless a newer version is cached
+func (c *ObjectRefCache) Put(ref *sui.ObjectRef, owner *sui.Owner) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(ref.ObjectId, &cachedObject{version: ref.Version, ref: ref, owner: owner})
+}
+
+// ApplyEffects stores the new refs of the created, mutated and unwrapped objects and of the gas coin,
+// and marks the deleted and wrapped objects as gone
+func (c *ObjectRefCache) ApplyEffects(effects *SuiTransactionBlockEffectsV1) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changes := [][]OwnedObjectRef{effects.Created, effects.Mutated, effects.Unwrapped, {effects.GasObject}}
+	for _, changed := range changes {
+		for _, change := range changed {
+			if change.Reference.ObjectId == nil {
+				continue
+			}
+			owner := change.Owner.Data
+			c.put(change.Reference.ObjectId, &cachedObject{
+				version: change.Reference.Version,
+				ref:     change.Reference.Ref(),
+				owner:   &owner,
+			})
+		}
+	}
+	for _, refs := range [][]SuiObjectRef{effects.Deleted, effects.Wrapped, effects.UnwrappedThenDeleted} {
+		for _, ref := range refs {
+			c.put(ref.ObjectId, &cachedObject{version: ref.Version})
+		}
+	}
+}
+
+// Invalidate drops the objects, e.g. the ones whose versions turned out to be stale
+func (c *ObjectRefCache) Invalidate(ids ...*sui.ObjectId) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range ids {
+		delete(c.objects, *id)
+	}
+}
+
+// isNewer reports whether the cached version of the object is newer than the version, e.g. the one of a coin
+// from a lagging fullnode
+func (c *ObjectRefCache) isNewer(id *sui.ObjectId, version uint64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	object, ok := c.objects[*id]
+	return ok && object.version > version
+}
+
+// put must be called with the mutex held
+func (c *ObjectRefCache) put(id *sui.ObjectId, object *cachedObject) {
+	if cached, ok := c.objects[*id]; ok && cached.version > object.version {
+		return
+	}
+	c.objects[*id] = object
+}
+
+// updateObjectCache applies the effects of an executed transaction to the cache. If the transaction was rejected,
+// the stale objects are invalidated, and if its result is unknown, e.g. the response has no effects because
+// ShowEffects isn't set, all of its objects are.
+func (s *ClientImpl) updateObjectCache(txBytes []byte, resp *SuiTransactionBlockResponse, err error) {
+	if s.objectCache == nil {
+		return
+	}
+	var rpcErr conn.RPCError
+	switch {
+	case err == nil && resp.Effects != nil && resp.Effects.Data.V1 != nil:
+		s.objectCache.ApplyEffects(resp.Effects.Data.V1)
+	case err != nil && errors.As(err, &rpcErr):
+		s.objectCache.Invalidate(StaleObjectIds(err)...)
+	default:
+		var tx suiptb.TransactionData
+		if _, err := bcs.Unmarshal(txBytes, &tx); err != nil || tx.V1 == nil {
+			return
+		}
+		for _, gasCoin := range tx.V1.GasData.Payment {
+			s.objectCache.Invalidate(gasCoin.ObjectId)
+		}
+		if pt := tx.V1.Kind.ProgrammableTransaction; pt != nil {
+			s.objectCache.Invalidate(pt.InputObjectIds()...)
+		}
+	}
+}
+
+// cacheObject stores an object fetched with its owner, and returns its ref and owner
+func (s *ClientImpl) cacheObject(data *SuiObjectData) (*sui.ObjectRef, *sui.Owner) {
+	ref := &sui.ObjectRef{ObjectId: data.ObjectId, Version: data.Version.Uint64(), Digest: data.Digest}
+	owner := data.Owner.owner()
+	if s.objectCache != nil && owner != nil {
+		s.objectCache.Put(ref, owner)
+	}
+	return ref, owner
+}
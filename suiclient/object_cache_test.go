@@ -0,0 +1,172 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+func ownedObjectRef(id string, version uint64, owner *sui.Address) suiclient.OwnedObjectRef {
+	return suiclient.OwnedObjectRef{
+		Owner:     suiclient.WrapperTaggedJson[sui.Owner]{Data: sui.Owner{AddressOwner: owner}},
+		Reference: suiclient.SuiObjectRef{ObjectId: sui.MustObjectIdFromHex(id), Version: version, Digest: testDigest},
+	}
+}
+
+func TestObjectRefCache(t *testing.T) {
+	owner := sui.MustAddressFromHex("0xa11ce")
+	created := sui.MustObjectIdFromHex("0x11")
+	mutated := sui.MustObjectIdFromHex("0x22")
+	deleted := sui.MustObjectIdFromHex("0x33")
+	wrapped := sui.MustObjectIdFromHex("0x44")
+	gasCoin := sui.MustObjectIdFromHex("0x99")
+
+	cache := suiclient.NewObjectRefCache()
+	cache.Put(&sui.ObjectRef{ObjectId: mutated, Version: 3, Digest: testDigest}, &sui.Owner{AddressOwner: owner})
+	cache.Put(&sui.ObjectRef{ObjectId: deleted, Version: 3, Digest: testDigest}, &sui.Owner{AddressOwner: owner})
+	cache.ApplyEffects(&suiclient.SuiTransactionBlockEffectsV1{
+		Created:   []suiclient.OwnedObjectRef{ownedObjectRef("0x11", 5, owner)},
+		Mutated:   []suiclient.OwnedObjectRef{ownedObjectRef("0x22", 5, owner)},
+		Deleted:   []suiclient.SuiObjectRef{{ObjectId: deleted, Version: 5, Digest: testDigest}},
+		Wrapped:   []suiclient.SuiObjectRef{{ObjectId: wrapped, Version: 5, Digest: testDigest}},
+		GasObject: ownedObjectRef("0x99", 5, owner),
+	})
+
+	for _, id := range []*sui.ObjectId{created, mutated, gasCoin} {
+		ref, refOwner, ok := cache.Get(id)
+		require.True(t, ok)
+		require.Equal(t, &sui.ObjectRef{ObjectId: id, Version: 5, Digest: testDigest}, ref)
+		require.Equal(t, owner, refOwner.AddressOwner)
+	}
+	for _, id := range []*sui.ObjectId{deleted, wrapped} {
+		_, _, ok := cache.Get(id)
+		require.False(t, ok)
+	}
+
+	// an older version, e.g. from a lagging fullnode, doesn't replace the newer one, nor revive a deleted object
+	cache.Put(&sui.ObjectRef{ObjectId: mutated, Version: 4, Digest: testDigest}, &sui.Owner{AddressOwner: owner})
+	cache.Put(&sui.ObjectRef{ObjectId: deleted, Version: 4, Digest: testDigest}, &sui.Owner{AddressOwner: owner})
+	ref, _, _ := cache.Get(mutated)
+	require.Equal(t, uint64(5), ref.Version)
+	_, _, ok := cache.Get(deleted)
+	require.False(t, ok)
+
+	// a wrapped object comes back when it is unwrapped
+	cache.ApplyEffects(&suiclient.SuiTransactionBlockEffectsV1{
+		Unwrapped: []suiclient.OwnedObjectRef{ownedObjectRef("0x44", 6, owner)},
+		GasObject: ownedObjectRef("0x99", 6, owner),
+	})
+	ref, _, ok = cache.Get(wrapped)
+	require.True(t, ok)
+	require.Equal(t, uint64(6), ref.Version)
+
+	cache.Invalidate(mutated, gasCoin)
+	_, _, ok = cache.Get(mutated)
+	require.False(t, ok)
+	_, _, ok = cache.Get(gasCoin)
+	require.False(t, ok)
+}
+
+func TestClientObjectRefCache(t *testing.T) {
+	ctx := context.Background()
+	sender := sui.MustAddressFromHex("0xa11ce")
+	owned := sui.MustObjectIdFromHex("0x11")
+	pool := sui.MustObjectIdFromHex("0x33")
+
+	rpc, client := newFakeRPC(t)
+	cache := suiclient.NewObjectRefCache()
+	client.WithObjectRefCache(cache)
+	rpc.result("sui_executeTransactionBlock", fmt.Sprintf(`{"digest":"%[1]s","effects":{
+		"messageVersion":"v1","status":{"status":"success"},
+		"mutated":[
+			{"owner":{"AddressOwner":"0xa11ce"},"reference":{"objectId":"0x11","version":8,"digest":"%[1]s"}},
+			{"owner":{"Shared":{"initial_shared_version":3}},"reference":{"objectId":"0x33","version":10,"digest":"%[1]s"}},
+			{"owner":{"AddressOwner":"0xa11ce"},"reference":{"objectId":"0x102","version":6,"digest":"%[1]s"}}
+		],
+		"deleted":[{"objectId":"0x103","version":6,"digest":"%[1]s"}],
+		"gasObject":{"owner":{"AddressOwner":"0xa11ce"},"reference":{"objectId":"0x102","version":6,"digest":"%[1]s"}}
+	}}`, testDigest))
+	rpc.result("sui_getNormalizedMoveFunction", `{
+		"visibility":"Public","isEntry":true,"typeParameters":[],"return":[],
+		"parameters":[
+			{"MutableReference":{"Struct":{"address":"0x1234","module":"game","name":"Hero","typeArguments":[]}}},
+			{"MutableReference":{"Struct":{"address":"0x1234","module":"game","name":"Pool","typeArguments":[]}}}
+		]}`)
+	rpc.result("sui_multiGetObjects", fmt.Sprintf(`[
+		{"data":{"objectId":"0x11","version":"9","digest":"%[1]s","owner":{"AddressOwner":"0xa11ce"}}}
+	]`, testDigest))
+	// the fullnode lags behind the effects
+	rpc.result("suix_getCoins", fmt.Sprintf(`{"data":[
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x102","version":"5","digest":"%[1]s","balance":"2000000"},
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x103","version":"5","digest":"%[1]s","balance":"2000000"},
+		{"coinType":"0x2::sui::SUI","coinObjectId":"0x104","version":"4","digest":"%[1]s","balance":"2000000"}
+	],"hasNextPage":false}`, testDigest))
+
+	_, err := client.ExecuteTransactionBlock(ctx, &suiclient.ExecuteTransactionBlockRequest{})
+	require.NoError(t, err)
+
+	resolve := func() suiptb.ProgrammableTransaction {
+		ptb := suiptb.NewTransactionDataTransactionBuilder()
+		ptb.ProgrammableMoveCall(sui.MustPackageIdFromHex("0x1234"), "game", "play", nil,
+			[]suiptb.Argument{ptb.ObjId(owned), ptb.ObjId(pool)})
+		require.NoError(t, client.ResolveObjects(ctx, ptb))
+		return ptb.Finish()
+	}
+
+	// the objects come from the effects
+	pt := resolve()
+	require.Equal(t, 0, rpc.count("sui_multiGetObjects"))
+	require.Equal(t, &sui.ObjectRef{ObjectId: owned, Version: 8, Digest: testDigest}, pt.Inputs[0].Object.ImmOrOwnedObject)
+	require.Equal(t, &suiptb.SharedObjectArg{Id: pool, InitialSharedVersion: 3, Mutable: true}, pt.Inputs[1].Object.SharedObject)
+
+	// the coins moved by the effects, whose balances the fullnode doesn't know yet, are skipped
+	payment, err := client.SelectGasPayment(ctx, sender, 2_000_000, nil)
+	require.NoError(t, err)
+	require.Equal(t, []*sui.ObjectRef{{ObjectId: sui.MustObjectIdFromHex("0x104"), Version: 4, Digest: testDigest}}, payment)
+	_, err = client.SelectGasPayment(ctx, sender, 3_000_000, nil)
+	require.ErrorIs(t, err, suiclient.ErrInsufficientBalance)
+
+	// a rejection for a stale version invalidates the object, which is fetched again
+	rpc.handle("sui_executeTransactionBlock", func([]json.RawMessage) (any, error) {
+		return nil, errors.New("Object ID 0x0000000000000000000000000000000000000000000000000000000000000011 Version 0x8 " +
+			"Digest 4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi is not available for consumption, current version: 0x9")
+	})
+	_, err = client.ExecuteTransactionBlock(ctx, &suiclient.ExecuteTransactionBlockRequest{})
+	require.Error(t, err)
+	pt = resolve()
+	require.Equal(t, 1, rpc.count("sui_multiGetObjects"))
+	require.Equal(t, &sui.ObjectRef{ObjectId: owned, Version: 9, Digest: testDigest}, pt.Inputs[0].Object.ImmOrOwnedObject)
+
+	// the fetched object is cached too
+	resolve()
+	require.Equal(t, 1, rpc.count("sui_multiGetObjects"))
+
+	// a response without effects, e.g. without ShowEffects, leaves the new versions unknown
+	rpc.result("sui_executeTransactionBlock", fmt.Sprintf(`{"digest":"%s"}`, testDigest))
+	tx := suiptb.NewTransactionData(sender, pt, []*sui.ObjectRef{{ObjectId: sui.MustObjectIdFromHex("0x104"), Version: 4, Digest: testDigest}},
+		suiclient.DefaultGasBudget, suiclient.DefaultGasPrice)
+	txBytes, err := bcs.Marshal(&tx)
+	require.NoError(t, err)
+	_, err = client.ExecuteTransactionBlock(ctx, &suiclient.ExecuteTransactionBlockRequest{TxDataBytes: txBytes})
+	require.NoError(t, err)
+	for _, id := range []*sui.ObjectId{owned, pool} {
+		_, _, ok := cache.Get(id)
+		require.False(t, ok)
+	}
+	rpc.result("sui_multiGetObjects", fmt.Sprintf(`[
+		{"data":{"objectId":"0x11","version":"10","digest":"%[1]s","owner":{"AddressOwner":"0xa11ce"}}},
+		{"data":{"objectId":"0x33","version":"11","digest":"%[1]s","owner":{"Shared":{"initial_shared_version":3}}}}
+	]`, testDigest))
+	pt = resolve()
+	require.Equal(t, 2, rpc.count("sui_multiGetObjects"))
+	require.Equal(t, &sui.ObjectRef{ObjectId: owned, Version: 10, Digest: testDigest}, pt.Inputs[0].Object.ImmOrOwnedObject)
+}
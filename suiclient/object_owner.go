@@ -57,3 +57,26 @@ func (o *ObjectOwner) UnmarshalJSON(data []byte) error {
 	}
 	return errors.New("value not json")
 }
+
+// owner converts the owner of an object data into the one of the effects, nil if it is unknown
+func (o *ObjectOwner) owner() *sui.Owner {
+	switch {
+	case o == nil:
+		return nil
+	case o.string != nil && *o.string == "Immutable":
+		return &sui.Owner{Immutable: &sui.EmptyEnum{}}
+	case o.ObjectOwnerInternal == nil:
+		return nil
+	case o.AddressOwner != nil:
+		return &sui.Owner{AddressOwner: o.AddressOwner}
+	case o.ObjectOwner != nil:
+		return &sui.Owner{ObjectOwner: o.ObjectOwner}
+	case o.Shared != nil && o.Shared.InitialSharedVersion != nil:
+		owner := &sui.Owner{}
+		owner.Shared = &struct {
+			InitialSharedVersion sui.SequenceNumber `json:"initial_shared_version"`
+		}{InitialSharedVersion: *o.Shared.InitialSharedVersion}
+		return owner
+	}
+	return nil
+}
@@ -17,6 +17,14 @@ type SuiObjectRef struct {
 	Version sui.SequenceNumber `json:"version"`
 }
 
+func (r SuiObjectRef) Ref() *sui.ObjectRef {
+	return &sui.ObjectRef{
+		ObjectId: r.ObjectId,
+		Version:  r.Version,
+		Digest:   r.Digest,
+	}
+}
+
 type SuiGasData struct {
 	Payment []SuiObjectRef `json:"payment"`
 	/** Gas Object's owner */
@@ -0,0 +1,179 @@
+package suiclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+// ResolveObjects resolves the inputs added by their ids only, e.g. by `ptb.ObjId()`, with a single MultiGetObjects call
+// for the objects which aren't in the object cache of the client.
+// The kind of each input comes from the owner of the object. A shared object is mutable unless every Move function
+// taking it takes `&T`, and an object taken as `Receiving<T>` becomes a Receiving input.
+func (s *ClientImpl) ResolveObjects(ctx context.Context, ptb *suiptb.ProgrammableTransactionBuilder) error {
+	unresolved := ptb.UnresolvedObjects()
+	if len(unresolved) == 0 {
+		return nil
+	}
+	refs, owners, err := s.lookupObjects(ctx, unresolved)
+	if err != nil {
+		return err
+	}
+
+	usages := &inputUsages{client: s, functions: make(map[string]*sui.MoveNormalizedFunction)}
+	for i, obj := range unresolved {
+		ref := refs[i]
+
+		idx, _ := ptb.Inputs.Find(suiptb.BuilderArg{Object: obj.Id})
+		var objArg suiptb.ObjectArg
+		switch owner := owners[i]; {
+		case owner != nil && owner.Shared != nil:
+			mutable := obj.Mutable
+			if mutable == nil {
+				usage, err := usages.of(ctx, ptb.Commands, uint16(idx))
+				if err != nil {
+					return err
+				}
+				mutable = &usage.mutable
+			}
+			objArg.SharedObject = &suiptb.SharedObjectArg{
+				Id:                   obj.Id,
+				InitialSharedVersion: owner.Shared.InitialSharedVersion,
+				Mutable:              *mutable,
+			}
+		default:
+			receiving := obj.Receiving
+			if !receiving {
+				usage, err := usages.of(ctx, ptb.Commands, uint16(idx))
+				if err != nil {
+					return err
+				}
+				receiving = usage.receiving
+			}
+			if receiving {
+				objArg.Receiving = ref
+			} else {
+				objArg.ImmOrOwnedObject = ref
+			}
+		}
+		if err := ptb.ResolveObject(objArg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupObjects returns the refs and owners of the objects, from the object cache of the client if they are cached,
+// and fetched with a single MultiGetObjects call otherwise
+func (s *ClientImpl) lookupObjects(ctx context.Context, unresolved []*suiptb.UnresolvedObject) ([]*sui.ObjectRef, []*sui.Owner, error) {
+	refs := make([]*sui.ObjectRef, len(unresolved))
+	owners := make([]*sui.Owner, len(unresolved))
+	var ids []*sui.ObjectId
+	var missed []int
+	for i, obj := range unresolved {
+		if s.objectCache != nil {
+			if ref, owner, ok := s.objectCache.Get(obj.Id); ok && owner != nil {
+				refs[i], owners[i] = ref, owner
+				continue
+			}
+		}
+		ids = append(ids, obj.Id)
+		missed = append(missed, i)
+	}
+	if len(ids) == 0 {
+		return refs, owners, nil
+	}
+
+	objs, err := s.MultiGetObjects(ctx, &MultiGetObjectsRequest{
+		ObjectIds: ids,
+		Options:   &SuiObjectDataOptions{ShowOwner: true},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get objects: %w", err)
+	}
+	if len(objs) != len(ids) {
+		return nil, nil, fmt.Errorf("got %d objects for %d ids", len(objs), len(ids))
+	}
+	for j, i := range missed {
+		data := objs[j].Data
+		if data == nil || data.Owner == nil {
+			return nil, nil, fmt.Errorf("object %s not found", ids[j])
+		}
+		if shared := data.Owner.ObjectOwnerInternal; shared != nil && shared.Shared != nil && shared.Shared.InitialSharedVersion == nil {
+			return nil, nil, fmt.Errorf("shared object %s has no initial shared version", ids[j])
+		}
+		refs[i], owners[i] = s.cacheObject(data)
+	}
+	return refs, owners, nil
+}
+
+type inputUsage struct {
+	mutable   bool
+	receiving bool
+}
+
+// inputUsages finds how the commands take an input, with the normalized Move functions fetched once
+type inputUsages struct {
+	client    *ClientImpl
+	functions map[string]*sui.MoveNormalizedFunction
+}
+
+func (u *inputUsages) of(ctx context.Context, commands []suiptb.Command, input uint16) (*inputUsage, error) {
+	usage := &inputUsage{}
+	for _, command := range commands {
+		if command.MoveCall == nil {
+			for _, arg := range command.Arguments() {
+				if arg.Input != nil && *arg.Input == input {
+					// the builtin commands take the objects by value, or by `&mut` for the coins to split and merge into
+					usage.mutable = true
+				}
+			}
+			continue
+		}
+		moveCall := command.MoveCall
+		for i, arg := range moveCall.Arguments {
+			if arg.Input == nil || *arg.Input != input {
+				continue
+			}
+			function, err := u.function(ctx, moveCall)
+			if err != nil {
+				return nil, err
+			}
+			if i >= len(function.Parameters) {
+				return nil, fmt.Errorf("%s::%s::%s takes %d arguments", moveCall.Package, moveCall.Module, moveCall.Function, len(function.Parameters))
+			}
+			param := function.Parameters[i]
+			switch {
+			case param.Reference != nil:
+			case param.MutableReference != nil:
+				usage.mutable = true
+			case isReceivingType(&param):
+				usage.receiving = true
+			default:
+				usage.mutable = true
+			}
+		}
+	}
+	return usage, nil
+}
+
+func (u *inputUsages) function(ctx context.Context, moveCall *suiptb.ProgrammableMoveCall) (*sui.MoveNormalizedFunction, error) {
+	key := fmt.Sprintf("%s::%s::%s", moveCall.Package, moveCall.Module, moveCall.Function)
+	if function, ok := u.functions[key]; ok {
+		return function, nil
+	}
+	function, err := u.client.GetNormalizedMoveFunction(ctx, moveCall.Package, moveCall.Module, moveCall.Function)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Move function %s: %w", key, err)
+	}
+	u.functions[key] = function
+	return function, nil
+}
+
+func isReceivingType(t *sui.MoveNormalizedType) bool {
+	return t.Struct != nil &&
+		t.Struct.Address != nil && *t.Struct.Address == *sui.SuiPackageIdSuiFramework &&
+		t.Struct.Module == "transfer" && t.Struct.Name == "Receiving"
+}
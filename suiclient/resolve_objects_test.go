@@ -0,0 +1,62 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+func TestResolveObjects(t *testing.T) {
+	pkg := sui.MustPackageIdFromHex("0x1234")
+	owned := sui.MustObjectIdFromHex("0x11")
+	readOnly := sui.MustObjectIdFromHex("0x22")
+	pool := sui.MustObjectIdFromHex("0x33")
+	received := sui.MustObjectIdFromHex("0x44")
+
+	rpc, client := newFakeRPC(t)
+	rpc.handle("sui_multiGetObjects", func(params []json.RawMessage) (any, error) {
+		var ids []*sui.ObjectId
+		require.NoError(t, json.Unmarshal(params[0], &ids))
+		require.Equal(t, []*sui.ObjectId{owned, readOnly, pool, received}, ids)
+		return json.RawMessage(fmt.Sprintf(`[
+			{"data":{"objectId":"0x11","version":"7","digest":"%[1]s","owner":{"AddressOwner":"0xa11ce"}}},
+			{"data":{"objectId":"0x22","version":"8","digest":"%[1]s","owner":{"Shared":{"initial_shared_version":2}}}},
+			{"data":{"objectId":"0x33","version":"9","digest":"%[1]s","owner":{"Shared":{"initial_shared_version":3}}}},
+			{"data":{"objectId":"0x44","version":"4","digest":"%[1]s","owner":{"AddressOwner":"0x11"}}}
+		]`, testDigest)), nil
+	})
+	rpc.result("sui_getNormalizedMoveFunction", `{
+		"visibility":"Public","isEntry":true,"typeParameters":[],"return":[],
+		"parameters":[
+			{"MutableReference":{"Struct":{"address":"0x1234","module":"game","name":"Hero","typeArguments":[]}}},
+			{"Reference":{"Struct":{"address":"0x1234","module":"game","name":"Config","typeArguments":[]}}},
+			{"MutableReference":{"Struct":{"address":"0x1234","module":"game","name":"Pool","typeArguments":[]}}},
+			{"Struct":{"address":"0x2","module":"transfer","name":"Receiving","typeArguments":[
+				{"Struct":{"address":"0x1234","module":"game","name":"Item","typeArguments":[]}}]}},
+			{"MutableReference":{"Struct":{"address":"0x2","module":"tx_context","name":"TxContext","typeArguments":[]}}}
+		]}`)
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.ProgrammableMoveCall(pkg, "game", "play", nil, []suiptb.Argument{
+		ptb.ObjId(owned), ptb.ObjId(readOnly), ptb.ObjId(pool), ptb.ObjId(received),
+	})
+	// the second call to the same function doesn't fetch it again
+	ptb.ProgrammableMoveCall(pkg, "game", "play", nil, []suiptb.Argument{
+		ptb.ObjId(owned), ptb.ObjId(readOnly), ptb.ObjId(pool), ptb.ObjId(received),
+	})
+	require.NoError(t, client.ResolveObjects(context.Background(), ptb))
+	require.Equal(t, 1, rpc.count("sui_multiGetObjects"))
+	require.Equal(t, 1, rpc.count("sui_getNormalizedMoveFunction"))
+
+	pt := ptb.Finish()
+	require.Equal(t, &sui.ObjectRef{ObjectId: owned, Version: 7, Digest: testDigest}, pt.Inputs[0].Object.ImmOrOwnedObject)
+	require.Equal(t, &suiptb.SharedObjectArg{Id: readOnly, InitialSharedVersion: 2, Mutable: false}, pt.Inputs[1].Object.SharedObject)
+	require.Equal(t, &suiptb.SharedObjectArg{Id: pool, InitialSharedVersion: 3, Mutable: true}, pt.Inputs[2].Object.SharedObject)
+	require.Equal(t, &sui.ObjectRef{ObjectId: received, Version: 4, Digest: testDigest}, pt.Inputs[3].Object.Receiving)
+}
@@ -0,0 +1,171 @@
+package suiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient/conn"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+const (
+	DefaultMaxRetries    = 3
+	DefaultSubmitTimeout = 30 * time.Second
+)
+
+// TransactionBuilder builds the transaction to submit. On a retry, refs has the latest refs of the objects whose
+// versions were stale, which must replace the ones used before, e.g. in the inputs or in the gas payment.
+type TransactionBuilder func(ctx context.Context, refs map[sui.ObjectId]*sui.ObjectRef) (*suiptb.TransactionData, error)
+
+type RetryOptions struct {
+	// the max number of the resubmissions, DefaultMaxRetries if zero
+	MaxRetries int
+	// the timeout of each submission, DefaultSubmitTimeout if zero
+	SubmitTimeout time.Duration
+	// the options of the returned response
+	Options *SuiTransactionBlockResponseOptions
+}
+
+var staleObjectRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`Object ID (0x[0-9a-fA-F]+) Version 0x[0-9a-fA-F]+ Digest \S+ is not available for consumption`),
+	regexp.MustCompile(`ObjectVersionUnavailableForConsumption \{ provided_obj_ref: \((0x[0-9a-fA-F]+),`),
+	regexp.MustCompile(`ObjectNotFound \{ object_id: (0x[0-9a-fA-F]+), version: Some`),
+}
+
+// StaleObjectIds returns the objects whose versions were stale in the error of a rejected transaction,
+// nil if the transaction wasn't rejected for stale versions
+func StaleObjectIds(err error) []*sui.ObjectId {
+	var ids []*sui.ObjectId
+	seen := make(map[sui.ObjectId]bool)
+	for _, regex := range staleObjectRegexes {
+		for _, m := range regex.FindAllStringSubmatch(err.Error(), -1) {
+			id, err := sui.ObjectIdFromHex(m[1])
+			if err != nil || seen[*id] {
+				continue
+			}
+			seen[*id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SignAndExecuteWithRetry builds, signs and executes a transaction, and rebuilds it with the latest object refs
+// when it is rejected for stale object versions. A submission whose result is unknown, e.g. which timed out, is
+// looked up by its digest and resubmitted as it is, so the transaction can't be executed twice. opts is optional.
+func (s *ClientImpl) SignAndExecuteWithRetry(
+	ctx context.Context,
+	signer suisigner.TransactionSigner,
+	build TransactionBuilder,
+	opts *RetryOptions,
+) (*SuiTransactionBlockResponse, error) {
+	if opts == nil {
+		opts = &RetryOptions{}
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	submitTimeout := opts.SubmitTimeout
+	if submitTimeout == 0 {
+		submitTimeout = DefaultSubmitTimeout
+	}
+
+	refs := make(map[sui.ObjectId]*sui.ObjectRef)
+	var txBytes []byte
+	var signature suisigner.Signature
+	// whether a submission of txBytes has an unknown result
+	var unknown bool
+	for retries := 0; ; retries++ {
+		if txBytes == nil {
+			tx, err := build(ctx, refs)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build transaction: %w", err)
+			}
+			if txBytes, err = bcs.Marshal(tx); err != nil {
+				return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+			}
+			if signature, err = signer.SignTransactionBlock(txBytes, suisigner.DefaultIntent()); err != nil {
+				return nil, fmt.Errorf("failed to sign transaction block: %w", err)
+			}
+			unknown = false
+		}
+
+		submitCtx, cancel := context.WithTimeout(ctx, submitTimeout)
+		resp, err := s.ExecuteTransactionBlock(submitCtx, &ExecuteTransactionBlockRequest{
+			TxDataBytes: txBytes,
+			Signatures:  []*suisigner.Signature{&signature},
+			Options:     opts.Options,
+			RequestType: TxnRequestTypeWaitForEffectsCert,
+		})
+		cancel()
+		if err == nil {
+			return s.executionResult(ctx, txBytes, resp)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to execute transaction %s: %w", suiptb.TransactionDigest(txBytes), err)
+		}
+		if retries >= maxRetries {
+			return nil, fmt.Errorf("failed to execute transaction after %d retries: %w", retries, err)
+		}
+
+		var rpcErr conn.RPCError
+		if !errors.As(err, &rpcErr) {
+			// the result is unknown, and the transaction is resubmitted as it is unless it landed
+			landed, err := s.GetTransactionBlock(ctx, &GetTransactionBlockRequest{
+				Digest:  suiptb.TransactionDigest(txBytes),
+				Options: opts.Options,
+			})
+			if err == nil {
+				s.updateObjectCache(txBytes, landed, nil)
+				return s.executionResult(ctx, txBytes, landed)
+			}
+			unknown = true
+			continue
+		}
+		ids := StaleObjectIds(err)
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("failed to execute transaction: %w", err)
+		}
+		if unknown {
+			// the versions may be consumed by the submission with the unknown result, which must not be rebuilt
+			waitCtx, cancel := context.WithTimeout(ctx, submitTimeout)
+			landed, waitErr := s.WaitForTransaction(waitCtx, suiptb.TransactionDigest(txBytes), &WaitOptions{Options: opts.Options})
+			cancel()
+			if waitErr == nil {
+				s.updateObjectCache(txBytes, landed, nil)
+				return s.executionResult(ctx, txBytes, landed)
+			}
+			return nil, fmt.Errorf("failed to execute transaction %s, which may have landed: %w", suiptb.TransactionDigest(txBytes), err)
+		}
+		// the rejected transaction can't be executed anymore, since its object versions are consumed
+		objects, err := s.MultiGetObjects(ctx, &MultiGetObjectsRequest{
+			ObjectIds: ids,
+			Options:   &SuiObjectDataOptions{ShowOwner: true},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh stale objects: %w", err)
+		}
+		for _, object := range objects {
+			if object.Data != nil {
+				ref, _ := s.cacheObject(object.Data)
+				refs[*ref.ObjectId] = ref
+			}
+		}
+		txBytes = nil
+	}
+}
+
+// executionResult returns the response, with the failure of the execution as the error if the effects are shown
+func (s *ClientImpl) executionResult(ctx context.Context, txBytes []byte, resp *SuiTransactionBlockResponse) (*SuiTransactionBlockResponse, error) {
+	if resp.Effects != nil && resp.Effects.Data.V1 != nil && !resp.Effects.Data.IsSuccess() {
+		return resp, fmt.Errorf("failed to execute transaction: %w", s.decodeFailure(ctx, resp.Effects.Data.V1.Status.Failure(), decodeProgrammableTransaction(txBytes)))
+	}
+	return resp, nil
+}
@@ -0,0 +1,213 @@
+package suiclient_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+const staleError = "Transaction validator signing failed due to issues with transaction inputs, please review the errors and try again: " +
+	"Object ID 0x0000000000000000000000000000000000000000000000000000000000000055 Version 0x3 Digest 4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi " +
+	"is not available for consumption, current version: 0x4"
+
+// retryTest builds a transaction using the object 0x55, and records the submitted transactions
+type retryTest struct {
+	mu        sync.Mutex
+	builds    int
+	submitted [][]byte
+}
+
+func (r *retryTest) build(ctx context.Context, refs map[sui.ObjectId]*sui.ObjectRef) (*suiptb.TransactionData, error) {
+	r.mu.Lock()
+	r.builds++
+	r.mu.Unlock()
+	object := &sui.ObjectRef{ObjectId: sui.MustObjectIdFromHex("0x55"), Version: 3, Digest: testDigest}
+	if ref, ok := refs[*object.ObjectId]; ok {
+		object = ref
+	}
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.ProgrammableMoveCall(sui.MustPackageIdFromHex("0x1234"), "game", "play", nil,
+		[]suiptb.Argument{ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: object})})
+	gas := &sui.ObjectRef{ObjectId: sui.MustObjectIdFromHex("0x99"), Version: 1, Digest: testDigest}
+	tx := suiptb.NewTransactionData(sui.MustAddressFromHex("0x1"), ptb.Finish(), []*sui.ObjectRef{gas},
+		suiclient.DefaultGasBudget, suiclient.DefaultGasPrice)
+	return &tx, nil
+}
+
+// submit records the submitted transaction, and returns it with the number of the submissions so far
+func (r *retryTest) submit(params []json.RawMessage) ([]byte, int) {
+	var txBytes sui.Base64Data
+	if err := json.Unmarshal(params[0], &txBytes); err != nil {
+		panic(err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submitted = append(r.submitted, txBytes)
+	return txBytes, len(r.submitted)
+}
+
+func (r *retryTest) first() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.submitted[0]
+}
+
+func (r *retryTest) objectVersion(t *testing.T, i int) uint64 {
+	var tx suiptb.TransactionData
+	_, err := bcs.Unmarshal(r.submitted[i], &tx)
+	require.NoError(t, err)
+	return tx.V1.Kind.ProgrammableTransaction.Inputs[0].Object.ImmOrOwnedObject.Version
+}
+
+func successResponse(txBytes []byte) map[string]any {
+	return map[string]any{
+		"digest":  suiptb.TransactionDigest(txBytes).String(),
+		"effects": map[string]any{"messageVersion": "v1", "status": map[string]any{"status": "success"}},
+	}
+}
+
+func TestSignAndExecuteWithRetry(t *testing.T) {
+	signer := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	ctx := context.Background()
+	object := map[string]any{"data": map[string]any{"objectId": "0x55", "version": "4", "digest": testDigest.String()}}
+
+	t.Run("stale object", func(t *testing.T) {
+		rpc, client := newFakeRPC(t)
+		r := &retryTest{}
+		rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+			txBytes, n := r.submit(params)
+			if n == 1 {
+				return nil, errors.New(staleError)
+			}
+			return successResponse(txBytes), nil
+		})
+		rpc.result("sui_multiGetObjects", []any{object})
+
+		resp, err := client.SignAndExecuteWithRetry(ctx, signer, r.build, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, r.builds)
+		require.Equal(t, uint64(3), r.objectVersion(t, 0))
+		require.Equal(t, uint64(4), r.objectVersion(t, 1))
+		require.Equal(t, *suiptb.TransactionDigest(r.submitted[1]), resp.Digest)
+	})
+
+	t.Run("bounded retries", func(t *testing.T) {
+		rpc, client := newFakeRPC(t)
+		r := &retryTest{}
+		rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+			r.submit(params)
+			return nil, errors.New(staleError)
+		})
+		rpc.result("sui_multiGetObjects", []any{object})
+
+		_, err := client.SignAndExecuteWithRetry(ctx, signer, r.build, &suiclient.RetryOptions{MaxRetries: 2})
+		require.ErrorContains(t, err, "after 2 retries")
+		require.Len(t, r.submitted, 3)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		rpc, client := newFakeRPC(t)
+		r := &retryTest{}
+		rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+			r.submit(params)
+			return nil, errors.New("Transaction is denied: Address 0x1 is denied for coin 0x2::sui::SUI")
+		})
+
+		_, err := client.SignAndExecuteWithRetry(ctx, signer, r.build, nil)
+		require.ErrorContains(t, err, "denied")
+		require.Len(t, r.submitted, 1)
+	})
+
+	t.Run("timed out and resubmitted", func(t *testing.T) {
+		rpc, client := newFakeRPC(t)
+		r := &retryTest{}
+		rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+			txBytes, n := r.submit(params)
+			if n == 1 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return successResponse(txBytes), nil
+		})
+		rpc.handle("sui_getTransactionBlock", func(params []json.RawMessage) (any, error) {
+			var digest sui.TransactionDigest
+			require.NoError(t, json.Unmarshal(params[0], &digest))
+			require.Equal(t, suiptb.TransactionDigest(r.first()), &digest)
+			return nil, fmt.Errorf("Could not find the referenced transaction [TransactionDigest(%s)].", digest)
+		})
+
+		_, err := client.SignAndExecuteWithRetry(ctx, signer, r.build, &suiclient.RetryOptions{SubmitTimeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+		// the same transaction is resubmitted, not rebuilt
+		require.Equal(t, 1, r.builds)
+		require.Len(t, r.submitted, 2)
+		require.Equal(t, r.submitted[0], r.submitted[1])
+	})
+
+	t.Run("timed out and landed", func(t *testing.T) {
+		rpc, client := newFakeRPC(t)
+		r := &retryTest{}
+		rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+			txBytes, _ := r.submit(params)
+			time.Sleep(50 * time.Millisecond)
+			return successResponse(txBytes), nil
+		})
+		rpc.handle("sui_getTransactionBlock", func(params []json.RawMessage) (any, error) {
+			return successResponse(r.first()), nil
+		})
+
+		resp, err := client.SignAndExecuteWithRetry(ctx, signer, r.build, &suiclient.RetryOptions{SubmitTimeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+		require.Len(t, r.submitted, 1)
+		require.Equal(t, *suiptb.TransactionDigest(r.submitted[0]), resp.Digest)
+	})
+
+	t.Run("timed out and consumed its own objects", func(t *testing.T) {
+		rpc, client := newFakeRPC(t)
+		r := &retryTest{}
+		var lookups atomic.Int32
+		rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+			_, n := r.submit(params)
+			if n == 1 {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return nil, errors.New(staleError)
+		})
+		rpc.handle("sui_getTransactionBlock", func(params []json.RawMessage) (any, error) {
+			if lookups.Add(1) == 1 {
+				return nil, errors.New("Could not find the referenced transaction")
+			}
+			return successResponse(r.first()), nil
+		})
+
+		_, err := client.SignAndExecuteWithRetry(ctx, signer, r.build, &suiclient.RetryOptions{SubmitTimeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+		// the transaction which landed is never rebuilt
+		require.Equal(t, 1, r.builds)
+		require.Equal(t, 0, rpc.count("sui_multiGetObjects"))
+	})
+}
+
+func TestStaleObjectIds(t *testing.T) {
+	ids := suiclient.StaleObjectIds(errors.New(staleError))
+	require.Equal(t, []*sui.ObjectId{sui.MustObjectIdFromHex("0x55")}, ids)
+
+	ids = suiclient.StaleObjectIds(errors.New("ObjectVersionUnavailableForConsumption { provided_obj_ref: (0x56, SequenceNumber(3), " +
+		"o#4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi), current_version: SequenceNumber(4) }"))
+	require.Equal(t, []*sui.ObjectId{sui.MustObjectIdFromHex("0x56")}, ids)
+
+	require.Empty(t, suiclient.StaleObjectIds(errors.New("InsufficientGas")))
+}
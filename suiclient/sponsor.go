@@ -0,0 +1,226 @@
+package suiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// ErrSponsorRejected is returned when the sponsor refuses to pay for the transaction kind
+var ErrSponsorRejected = errors.New("sponsor rejected the transaction")
+
+// SponsorOptions sets the gas of a sponsored transaction
+type SponsorOptions struct {
+	// optional, estimated by a dry run if zero
+	GasBudget uint64
+	// optional, the reference gas price is used if zero
+	GasPrice uint64
+	// optional, picked from the SUI coins of the sponsor if empty
+	GasPayment []*sui.ObjectRef
+	// optional, checks the transaction kind of the sender before co-signing, `ValidateSponsoredKind` if nil
+	ValidateKind func(sender *sui.Address, kind *suiptb.TransactionKind) error
+}
+
+// SponsoredTransaction is a transaction whose gas is paid by the sponsor.
+// It is executed once the sender signs `TxBytes` too.
+type SponsoredTransaction struct {
+	TxData           *suiptb.TransactionData
+	TxBytes          sui.Base64Data
+	SponsorSignature *suisigner.Signature
+}
+
+// ValidateSponsoredKind rejects the transaction kinds a sponsor shouldn't pay for:
+// the ones which are not programmable transactions, and the ones using the gas coin, which belongs to the sponsor.
+func ValidateSponsoredKind(sender *sui.Address, kind *suiptb.TransactionKind) error {
+	if kind.ProgrammableTransaction == nil {
+		return fmt.Errorf("%w: only programmable transactions can be sponsored", ErrSponsorRejected)
+	}
+	if kind.ProgrammableTransaction.UsesGasCoin() {
+		return fmt.Errorf("%w: the gas coin of the sponsor can't be used by the sender", ErrSponsorRejected)
+	}
+	return nil
+}
+
+// SponsorTransaction attaches the gas of the sponsor to the transaction kind of the sender, and signs it by the sponsor.
+// options is optional.
+func (s *ClientImpl) SponsorTransaction(
+	ctx context.Context,
+	sponsor suisigner.TransactionSigner,
+	sender *sui.Address,
+	kind *suiptb.TransactionKind,
+	options *SponsorOptions,
+) (*SponsoredTransaction, error) {
+	if options == nil {
+		options = &SponsorOptions{}
+	}
+	validate := options.ValidateKind
+	if validate == nil {
+		validate = ValidateSponsoredKind
+	}
+	if err := validate(sender, kind); err != nil {
+		return nil, err
+	}
+	if kind.ProgrammableTransaction == nil {
+		return nil, fmt.Errorf("%w: only programmable transactions can be sponsored", ErrSponsorRejected)
+	}
+
+	gasPrice := options.GasPrice
+	if gasPrice == 0 {
+		price, err := s.GetReferenceGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reference gas price: %w", err)
+		}
+		gasPrice = price.Uint64()
+	}
+	gasBudget := options.GasBudget
+	if gasBudget == 0 {
+		var err error
+		gasBudget, err = s.EstimateGasBudget(ctx, sender, *kind.ProgrammableTransaction, gasPrice)
+		if err != nil {
+			return nil, err
+		}
+	}
+	gasPayment := options.GasPayment
+	if len(gasPayment) == 0 {
+		var err error
+		gasPayment, err = s.SelectGasPayment(ctx, sponsor.GetAddress(), gasBudget, kind.ProgrammableTransaction.InputObjectIds())
+		if err != nil {
+			return nil, fmt.Errorf("failed to select gas coins of the sponsor: %w", err)
+		}
+	}
+
+	txData := suiptb.NewTransactionDataAllowSponsor(
+		*sender,
+		*kind.ProgrammableTransaction,
+		gasPayment,
+		gasBudget,
+		gasPrice,
+		sponsor.GetAddress(),
+	)
+	txBytes, err := bcs.Marshal(txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode sponsored transaction: %w", err)
+	}
+	signature, err := sponsor.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign sponsored transaction: %w", err)
+	}
+	return &SponsoredTransaction{
+		TxData:           &txData,
+		TxBytes:          txBytes,
+		SponsorSignature: &signature,
+	}, nil
+}
+
+// SponsorTransactionKindBytes is SponsorTransaction taking the BCS encoded transaction kind sent by the sender
+func (s *ClientImpl) SponsorTransactionKindBytes(
+	ctx context.Context,
+	sponsor suisigner.TransactionSigner,
+	sender *sui.Address,
+	kindBytes []byte,
+	options *SponsorOptions,
+) (*SponsoredTransaction, error) {
+	var kind suiptb.TransactionKind
+	if _, err := bcs.Unmarshal(kindBytes, &kind); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSponsorRejected, err)
+	}
+	return s.SponsorTransaction(ctx, sponsor, sender, &kind, options)
+}
+
+// SignAndExecuteSponsoredTransaction signs the sponsored transaction by the sender, and executes it
+func (s *ClientImpl) SignAndExecuteSponsoredTransaction(
+	ctx context.Context,
+	sender suisigner.TransactionSigner,
+	tx *SponsoredTransaction,
+	options *SuiTransactionBlockResponseOptions,
+) (*SuiTransactionBlockResponse, error) {
+	signature, err := sender.SignTransactionBlock(tx.TxBytes, suisigner.DefaultIntent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction block: %w", err)
+	}
+	return s.ExecuteSponsoredTransaction(ctx, tx.TxBytes, &signature, tx.SponsorSignature, options)
+}
+
+// ExecuteSponsoredTransaction checks the signatures of the sender and the sponsor, and executes the transaction
+// with the signatures in the order of the sender and then the sponsor
+func (s *ClientImpl) ExecuteSponsoredTransaction(
+	ctx context.Context,
+	txBytes sui.Base64Data,
+	senderSignature *suisigner.Signature,
+	sponsorSignature *suisigner.Signature,
+	options *SuiTransactionBlockResponseOptions,
+) (*SuiTransactionBlockResponse, error) {
+	signatures, err := SponsoredTransactionSignatures(txBytes, senderSignature, sponsorSignature)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.ExecuteTransactionBlock(
+		ctx,
+		&ExecuteTransactionBlockRequest{
+			TxDataBytes: txBytes,
+			Signatures:  signatures,
+			Options:     options,
+			RequestType: TxnRequestTypeWaitForLocalExecution,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction: %w", err)
+	}
+	if options != nil && options.ShowEffects && !resp.Effects.Data.IsSuccess() {
+		return resp, fmt.Errorf("failed to execute transaction: %w", s.decodeFailure(ctx, resp.Effects.Data.V1.Status.Failure(), decodeProgrammableTransaction(txBytes)))
+	}
+	return resp, nil
+}
+
+// SponsoredTransactionSignatures verifies the signatures against the sender and the gas owner of the transaction,
+// and returns them in the order the node expects
+func SponsoredTransactionSignatures(
+	txBytes []byte,
+	senderSignature *suisigner.Signature,
+	sponsorSignature *suisigner.Signature,
+) ([]*suisigner.Signature, error) {
+	var txData suiptb.TransactionData
+	if _, err := bcs.Unmarshal(txBytes, &txData); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	sender := txData.V1.Sender
+	sponsor := txData.V1.GasData.Owner
+	if sponsor == nil {
+		return nil, errors.New("transaction has no gas owner")
+	}
+
+	if err := checkTransactionSigner(senderSignature, txBytes, &sender, "sender"); err != nil {
+		return nil, err
+	}
+	if *sponsor == sender {
+		return []*suisigner.Signature{senderSignature}, nil
+	}
+	if err := checkTransactionSigner(sponsorSignature, txBytes, sponsor, "sponsor"); err != nil {
+		return nil, err
+	}
+	return []*suisigner.Signature{senderSignature, sponsorSignature}, nil
+}
+
+// checkTransactionSigner verifies the signature is of the expected address.
+// The schemes which can't be verified locally, like zkLogin, are left to the node.
+func checkTransactionSigner(signature *suisigner.Signature, txBytes []byte, expected *sui.Address, role string) error {
+	if signature == nil {
+		return fmt.Errorf("missing %s signature", role)
+	}
+	signer, err := suisigner.VerifyTransactionSignature(signature, txBytes)
+	if errors.Is(err, suisigner.ErrUnsupportedSignature) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s signature: %w", role, err)
+	}
+	if *signer != *expected {
+		return fmt.Errorf("%s signature is of %s, not %s", role, signer, expected)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package suiclient_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suiclient/conn"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+func TestSponsorTransaction(t *testing.T) {
+	client := suiclient.NewClient(conn.TestnetEndpointUrl)
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{1}, 32), suisigner.KeySchemeFlagEd25519)
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+	gasCoin := &sui.ObjectRef{
+		ObjectId: sui.MustObjectIdFromHex("0x5678"),
+		Version:  1,
+		Digest:   sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"),
+	}
+	options := &suiclient.SponsorOptions{
+		GasBudget:  suiclient.DefaultGasBudget,
+		GasPrice:   suiclient.DefaultGasPrice,
+		GasPayment: []*sui.ObjectRef{gasCoin},
+	}
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.MustPure(uint64(1))
+	pt := ptb.Finish()
+	kindBytes, err := bcs.Marshal(suiptb.TransactionKind{ProgrammableTransaction: &pt})
+	require.NoError(t, err)
+
+	// gas is set without any RPC, so this runs offline
+	tx, err := client.SponsorTransactionKindBytes(context.Background(), sponsor, sender.Address, kindBytes, options)
+	require.NoError(t, err)
+	require.Equal(t, *sender.Address, tx.TxData.V1.Sender)
+	require.Equal(t, sponsor.Address, tx.TxData.V1.GasData.Owner)
+	require.Equal(t, []*sui.ObjectRef{gasCoin}, tx.TxData.V1.GasData.Payment)
+
+	senderSignature, err := sender.SignTransactionBlock(tx.TxBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	signatures, err := suiclient.SponsoredTransactionSignatures(tx.TxBytes, &senderSignature, tx.SponsorSignature)
+	require.NoError(t, err)
+	require.Equal(t, []*suisigner.Signature{&senderSignature, tx.SponsorSignature}, signatures)
+
+	// the signatures in the wrong order are rejected
+	_, err = suiclient.SponsoredTransactionSignatures(tx.TxBytes, tx.SponsorSignature, &senderSignature)
+	require.Error(t, err)
+
+	t.Run("gas coin of the sponsor", func(t *testing.T) {
+		ptb := suiptb.NewTransactionDataTransactionBuilder()
+		amount := uint64(100)
+		require.NoError(t, ptb.TransferSui(sender.Address, &amount))
+		pt := ptb.Finish()
+		_, err := client.SponsorTransaction(context.Background(), sponsor, sender.Address, &suiptb.TransactionKind{ProgrammableTransaction: &pt}, options)
+		require.ErrorIs(t, err, suiclient.ErrSponsorRejected)
+	})
+}
+
+func TestSignAndExecuteSponsoredTransaction(t *testing.T) {
+	sender := suisigner.NewSigner(bytes.Repeat([]byte{3}, 32), suisigner.KeySchemeFlagEd25519)
+	sponsor := suisigner.NewSigner(bytes.Repeat([]byte{2}, 32), suisigner.KeySchemeFlagEd25519)
+
+	rpc, client := newFakeRPC(t)
+	rpc.result("suix_getReferenceGasPrice", `"750"`)
+	rpc.result("sui_dryRunTransactionBlock", `{"effects":{"messageVersion":"v1","status":{"status":"success"},"gasUsed":{
+		"computationCost":"1000000","storageCost":"2000000","storageRebate":"500000","nonRefundableStorageFee":"0"}}}`)
+	rpc.handle("suix_getCoins", func(params []json.RawMessage) (any, error) {
+		var owner sui.Address
+		require.NoError(t, json.Unmarshal(params[0], &owner))
+		require.Equal(t, *sponsor.Address, owner)
+		return json.RawMessage(fmt.Sprintf(`{"data":[
+			{"coinType":"0x2::sui::SUI","coinObjectId":"0x5678","version":"1","digest":"%s","balance":"100000000"}
+		],"hasNextPage":false}`, testDigest)), nil
+	})
+	rpc.handle("sui_executeTransactionBlock", func(params []json.RawMessage) (any, error) {
+		var signatures []*suisigner.Signature
+		require.NoError(t, json.Unmarshal(params[1], &signatures))
+		require.Len(t, signatures, 2)
+		return json.RawMessage(fmt.Sprintf(`{"digest":"%s","effects":{"messageVersion":"v1","status":{"status":"success"}}}`, testDigest)), nil
+	})
+
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	ptb.MustPure(uint64(1))
+	pt := ptb.Finish()
+	// the gas price, the gas budget and the gas payment are all filled by the client
+	tx, err := client.SponsorTransaction(context.Background(), sponsor, sender.Address, &suiptb.TransactionKind{ProgrammableTransaction: &pt}, nil)
+	require.NoError(t, err)
+	gasData := tx.TxData.V1.GasData
+	require.Equal(t, uint64(750), gasData.Price)
+	require.Equal(t, suiclient.GasBudgetFromCost(&suiclient.GasCostSummary{
+		ComputationCost: sui.NewBigInt(1000000),
+		StorageCost:     sui.NewBigInt(2000000),
+		StorageRebate:   sui.NewBigInt(500000),
+	}, 750), gasData.Budget)
+	require.Equal(t, sui.MustObjectIdFromHex("0x5678"), gasData.Payment[0].ObjectId)
+
+	resp, err := client.SignAndExecuteSponsoredTransaction(
+		context.Background(),
+		sender,
+		tx,
+		&suiclient.SuiTransactionBlockResponseOptions{ShowEffects: true},
+	)
+	require.NoError(t, err)
+	require.True(t, resp.Effects.Data.IsSuccess())
+}
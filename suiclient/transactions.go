@@ -329,8 +329,6 @@ func (r *SuiTransactionBlockResponse) GetCreatedObjectInfo(module string, objNam
 	return nil, "", fmt.Errorf("not found")
 }
 
-type ReturnValueType interface{}
-type MutableReferenceOutputType interface{}
 type ExecutionResultType struct {
 	MutableReferenceOutputs []MutableReferenceOutputType `json:"mutableReferenceOutputs,omitempty"`
 	ReturnValues            []ReturnValueType            `json:"returnValues,omitempty"`
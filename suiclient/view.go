@@ -0,0 +1,124 @@
+package suiclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+// ViewCall is a call of a Move view function, the Target is `package::module::function`
+type ViewCall struct {
+	Target   string
+	TypeArgs []sui.TypeTag
+	// the arguments of AddMoveCall()
+	Args []any
+}
+
+// View calls a Move function by DevInspect from the zero address, and returns its return values decoded
+// by the normalized return types of the function. A return value is decoded into its Go type when it has
+// one, see movebcs.GoType(), and into a *movebcs.MoveValue otherwise. A Move abort is returned as a
+// *MoveAbortError, which can be taken by errors.As().
+func (s *ClientImpl) View(ctx context.Context, target string, typeArgs []sui.TypeTag, args ...any) ([]any, error) {
+	values, err := s.ViewBatch(ctx, nil, []ViewCall{{Target: target, TypeArgs: typeArgs, Args: args}})
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// ViewBatch calls the view functions in a single PTB by DevInspect, and returns the return values of each
+// call, see View(). A nil sender is the zero address. The index of the call which aborts is the Command of
+// the *MoveAbortError.
+func (s *ClientImpl) ViewBatch(ctx context.Context, sender *sui.Address, calls []ViewCall) ([][]any, error) {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	functions := make([]*sui.MoveNormalizedFunction, len(calls))
+	for i, call := range calls {
+		packageId, module, function, err := parseViewTarget(call.Target)
+		if err != nil {
+			return nil, err
+		}
+		functions[i], err = s.GetNormalizedMoveFunction(ctx, packageId, module, function)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Move function %s: %w", call.Target, err)
+		}
+		_, err = s.addMoveCall(ctx, ptb, functions[i], packageId, module, function, call.TypeArgs, call.Args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.ResolveObjects(ctx, ptb); err != nil {
+		return nil, err
+	}
+
+	pt, err := ptb.FinishChecked()
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.devInspect(ctx, sender, pt)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("dev inspect returned %d results for %d calls", len(results), len(calls))
+	}
+	decoder := movebcs.NewDecoder(s)
+	values := make([][]any, len(calls))
+	for i, call := range calls {
+		values[i], err = decodeReturnValues(ctx, decoder, functions[i], call.TypeArgs, results[i].ReturnValues)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode the return values of %s: %w", call.Target, err)
+		}
+	}
+	return values, nil
+}
+
+func decodeReturnValues(
+	ctx context.Context,
+	decoder *movebcs.Decoder,
+	fn *sui.MoveNormalizedFunction,
+	typeArgs []sui.TypeTag,
+	returnValues []ReturnValueType,
+) ([]any, error) {
+	if len(returnValues) != len(fn.Return) {
+		return nil, fmt.Errorf("got %d return values, expected %d", len(returnValues), len(fn.Return))
+	}
+	values := make([]any, len(fn.Return))
+	for i, ret := range fn.Return {
+		tag, err := ret.Dereference().TypeTag(typeArgs)
+		if err != nil {
+			return nil, err
+		}
+		v, err := movebcs.NewValue(tag)
+		if err != nil {
+			// no Go type, so the value is decoded by its layout
+			values[i], err = decoder.Decode(ctx, tag, returnValues[i].Bcs)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		returnValue := ReturnValueType{Bcs: returnValues[i].Bcs, Type: tag}
+		if err := returnValue.Decode(v); err != nil {
+			return nil, err
+		}
+		values[i] = reflect.ValueOf(v).Elem().Interface()
+	}
+	return values, nil
+}
+
+func parseViewTarget(target string) (*sui.PackageId, sui.Identifier, sui.Identifier, error) {
+	parts := strings.Split(target, "::")
+	if len(parts) != 3 {
+		return nil, "", "", fmt.Errorf("invalid target %q, expected `package::module::function`", target)
+	}
+	packageId, err := sui.PackageIdFromHex(parts[0])
+	if err != nil {
+		return nil, "", "", fmt.Errorf("invalid package of target %q: %w", target, err)
+	}
+	return packageId, sui.Identifier(parts[1]), sui.Identifier(parts[2]), nil
+}
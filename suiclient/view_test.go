@@ -0,0 +1,133 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+// info(pool: &Pool<T>): PoolInfo, which has no Go type
+const infoFunction = `{
+	"visibility":"Public","isEntry":false,
+	"typeParameters":[{"abilities":[]}],
+	"parameters":[{"Reference":{"Struct":{"address":"0x1234","module":"pool","name":"Pool","typeArguments":[{"TypeParameter":0}]}}}],
+	"return":[{"Struct":{"address":"0x1234","module":"pool","name":"PoolInfo","typeArguments":[]}}]
+}`
+
+const abortError = `MoveAbort(MoveLocation { module: ModuleId { address: 0000000000000000000000000000000000000000000000000000000000001234, ` +
+	`name: Identifier("pool") }, function: 3, instruction: 9, function_name: Some("balance") }, 2) in command 1`
+
+func TestView(t *testing.T) {
+	coin := movebcs.MoveCoin{Id: sui.MustObjectIdFromHex("0x99"), Balance: 7}
+	coinBytes, err := bcs.Marshal(coin)
+	require.NoError(t, err)
+	coinInts, err := json.Marshal(bytesToInts(coinBytes))
+	require.NoError(t, err)
+
+	pool := sui.MustObjectIdFromHex("0x55")
+	rpc, client := newFakeRPC(t)
+	rpc.handle("sui_getNormalizedMoveFunction", func(params []json.RawMessage) (any, error) {
+		var function string
+		require.NoError(t, json.Unmarshal(params[2], &function))
+		if function == "info" {
+			return json.RawMessage(infoFunction), nil
+		}
+		return json.RawMessage(balanceFunction), nil
+	})
+	rpc.handle("sui_getNormalizedMoveStruct", func(params []json.RawMessage) (any, error) {
+		var name string
+		require.NoError(t, json.Unmarshal(params[2], &name))
+		if name == "PoolInfo" {
+			return json.RawMessage(`{"abilities":{"abilities":["Copy","Drop"]},"typeParameters":[],
+				"fields":[{"name":"fee","type":"U16"},{"name":"paused","type":"Bool"}]}`), nil
+		}
+		return json.RawMessage(`{"abilities":{"abilities":["Drop"]},"typeParameters":[],"fields":[]}`), nil
+	})
+	rpc.result("sui_multiGetObjects", []map[string]any{{"data": map[string]any{
+		"objectId": pool.String(), "version": "3", "digest": testDigest.String(),
+		"owner": map[string]any{"Shared": map[string]any{"initial_shared_version": 2}},
+	}}})
+	rpc.handle("sui_devInspectTransactionBlock", func(params []json.RawMessage) (any, error) {
+		var kindBytes sui.Base64Data
+		require.NoError(t, json.Unmarshal(params[1], &kindBytes))
+		var kind suiptb.TransactionKind
+		_, err := bcs.Unmarshal(kindBytes, &kind)
+		require.NoError(t, err)
+		results := []string{}
+		for _, cmd := range kind.ProgrammableTransaction.Commands {
+			if cmd.MoveCall.Function == "info" {
+				results = append(results, `{"returnValues": [[[30, 0, 1], "0x1234::pool::PoolInfo"]]}`)
+			} else {
+				results = append(results, fmt.Sprintf(`{"returnValues": [[[42, 0, 0, 0, 0, 0, 0, 0], "u64"], [%s, "0x2::coin::Coin<0x2::sui::SUI>"]]}`, coinInts))
+			}
+		}
+		return map[string]any{
+			"effects": map[string]any{"messageVersion": "v1", "status": map[string]any{"status": "success"}},
+			"events":  []any{},
+			"results": json.RawMessage(fmt.Sprintf("[%s]", strings.Join(results, ","))),
+		}, nil
+	})
+
+	typeArgs := []sui.TypeTag{*sui.MustNewTypeTag("0x2::sui::SUI")}
+	values, err := client.View(context.Background(), "0x1234::pool::balance", typeArgs, pool)
+	require.NoError(t, err)
+	require.Equal(t, []any{uint64(42), coin}, values)
+
+	batch, err := client.ViewBatch(context.Background(), nil, []suiclient.ViewCall{
+		{Target: "0x1234::pool::info", TypeArgs: typeArgs, Args: []any{pool}},
+		{Target: "0x1234::pool::balance", TypeArgs: typeArgs, Args: []any{pool}},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	require.Equal(t, []any{uint64(42), coin}, batch[1])
+	info, ok := batch[0][0].(*movebcs.MoveValue)
+	require.True(t, ok)
+	infoJSON, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"fee":30,"paused":true}`, string(infoJSON))
+	// the pool is fetched once for the two calls
+	require.Equal(t, 2, rpc.count("sui_multiGetObjects"))
+
+	_, err = client.View(context.Background(), "0x1234::pool", typeArgs, pool)
+	require.ErrorContains(t, err, "invalid target")
+
+	rpc.result("sui_devInspectTransactionBlock", fmt.Sprintf(`{"effects":{"messageVersion":"v1","status":{"status":"failure"}},"events":[],"error":%q}`, abortError))
+	_, err = client.View(context.Background(), "0x1234::pool::balance", typeArgs, pool)
+	require.True(t, errors.Is(err, suiclient.ErrDevInspectFailed))
+	var abort *suiclient.MoveAbortError
+	require.True(t, errors.As(err, &abort))
+	require.Equal(t, uint64(2), abort.Code)
+}
+
+func TestParseMoveAbort(t *testing.T) {
+	abort, ok := suiclient.ParseMoveAbort(abortError)
+	require.True(t, ok)
+	require.Equal(t, sui.MustAddressFromHex("0x1234"), abort.Address)
+	require.Equal(t, sui.Identifier("pool"), abort.Module)
+	require.Equal(t, sui.Identifier("balance"), abort.Function)
+	require.Equal(t, uint64(3), abort.FunctionIndex)
+	require.Equal(t, uint64(9), abort.Instruction)
+	require.Equal(t, uint64(2), abort.Code)
+	require.Equal(t, 1, *abort.Command)
+	require.Equal(t, "move abort in 0x1234::pool::balance with code 2 in command 1", abort.Error())
+
+	abort, ok = suiclient.ParseMoveAbort(`MoveAbort(MoveLocation { module: ModuleId { address: 0x2, name: Identifier("coin") }, ` +
+		`function: 0, instruction: 1, function_name: None }, 5)`)
+	require.True(t, ok)
+	require.Nil(t, abort.Command)
+	require.Equal(t, "move abort in 0x2::coin with code 5", abort.Error())
+
+	_, ok = suiclient.ParseMoveAbort("InsufficientGas")
+	require.False(t, ok)
+}
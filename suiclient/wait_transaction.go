@@ -0,0 +1,98 @@
+package suiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+)
+
+var (
+	// ErrTransactionNotFound is returned when the transaction isn't indexed by the fullnode before the context is done
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrTransactionNotCheckpointed is returned when the transaction isn't in a checkpoint before the context is done
+	ErrTransactionNotCheckpointed = errors.New("transaction not checkpointed")
+)
+
+const (
+	DefaultWaitPollInterval    = 100 * time.Millisecond
+	DefaultWaitMaxPollInterval = 2 * time.Second
+)
+
+// WaitOptions sets how WaitForTransaction polls the fullnode
+type WaitOptions struct {
+	// the options of the returned response
+	Options *SuiTransactionBlockResponseOptions
+	// waits until the transaction has a Checkpoint, not only until it is indexed
+	WaitForCheckpoint bool
+	// the first interval between the polls, DefaultWaitPollInterval if zero. It doubles after each poll.
+	PollInterval time.Duration
+	// DefaultWaitMaxPollInterval if zero
+	MaxPollInterval time.Duration
+}
+
+// WaitForTransaction polls GetTransactionBlock with backoff until the transaction is indexed by the fullnode,
+// e.g. after ExecuteTransactionBlock with WaitForEffectsCert, and returns its response. The deadline is the
+// one of ctx. If ctx is done first, ErrTransactionNotFound is returned, or ErrTransactionNotCheckpointed
+// with the response, while any other error of the fullnode is returned at once. opts is optional.
+func (s *ClientImpl) WaitForTransaction(
+	ctx context.Context,
+	digest *sui.TransactionDigest,
+	opts *WaitOptions,
+) (*SuiTransactionBlockResponse, error) {
+	if opts == nil {
+		opts = &WaitOptions{}
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultWaitPollInterval
+	}
+	maxInterval := opts.MaxPollInterval
+	if maxInterval <= 0 {
+		maxInterval = DefaultWaitMaxPollInterval
+	}
+
+	// the response of a transaction which isn't checkpointed yet
+	var pending *SuiTransactionBlockResponse
+	for {
+		resp, err := s.GetTransactionBlock(ctx, &GetTransactionBlockRequest{Digest: digest, Options: opts.Options})
+		switch {
+		case err == nil && (!opts.WaitForCheckpoint || resp.Checkpoint != nil):
+			return resp, nil
+		case err == nil:
+			pending = resp
+		case ctx.Err() != nil:
+			return waitTimeout(ctx, digest, pending)
+		case !IsTransactionNotFound(err):
+			return nil, fmt.Errorf("failed to get transaction %s: %w", digest, err)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return waitTimeout(ctx, digest, pending)
+		case <-timer.C:
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
+
+func waitTimeout(ctx context.Context, digest *sui.TransactionDigest, pending *SuiTransactionBlockResponse) (*SuiTransactionBlockResponse, error) {
+	if pending != nil {
+		return pending, fmt.Errorf("%w %s: %w", ErrTransactionNotCheckpointed, digest, ctx.Err())
+	}
+	return nil, fmt.Errorf("%w %s: %w", ErrTransactionNotFound, digest, ctx.Err())
+}
+
+// IsTransactionNotFound returns true if the error tells the transaction isn't indexed by the fullnode yet
+func IsTransactionNotFound(err error) bool {
+	return errors.Is(err, ErrTransactionNotFound) ||
+		strings.Contains(err.Error(), "Could not find the referenced transaction")
+}
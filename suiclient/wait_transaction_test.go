@@ -0,0 +1,70 @@
+package suiclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/suiclient"
+)
+
+func TestWaitForTransaction(t *testing.T) {
+	notFound := fmt.Errorf("Could not find the referenced transaction [TransactionDigest(%s)].", testDigest)
+	opts := &suiclient.WaitOptions{PollInterval: time.Millisecond, MaxPollInterval: 4 * time.Millisecond}
+
+	// indexed on the third poll, and checkpointed on the fifth
+	rpc, client := newFakeRPC(t)
+	var polls atomic.Int32
+	rpc.handle("sui_getTransactionBlock", func(params []json.RawMessage) (any, error) {
+		n := polls.Add(1)
+		switch {
+		case n < 3:
+			return nil, notFound
+		case n < 5:
+			return map[string]any{"digest": testDigest.String()}, nil
+		default:
+			return map[string]any{"digest": testDigest.String(), "checkpoint": "12"}, nil
+		}
+	})
+	resp, err := client.WaitForTransaction(context.Background(), testDigest, opts)
+	require.NoError(t, err)
+	require.Nil(t, resp.Checkpoint)
+	require.Equal(t, int32(3), polls.Load())
+
+	polls.Store(0)
+	resp, err = client.WaitForTransaction(context.Background(), testDigest,
+		&suiclient.WaitOptions{WaitForCheckpoint: true, PollInterval: time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), resp.Checkpoint.Uint64())
+	require.Equal(t, int32(5), polls.Load())
+
+	// not found before the deadline
+	rpc.handle("sui_getTransactionBlock", func([]json.RawMessage) (any, error) { return nil, notFound })
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForTransaction(ctx, testDigest, opts)
+	require.True(t, errors.Is(err, suiclient.ErrTransactionNotFound))
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// not checkpointed before the deadline
+	rpc.result("sui_getTransactionBlock", map[string]any{"digest": testDigest.String()})
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	resp, err = client.WaitForTransaction(ctx, testDigest, &suiclient.WaitOptions{WaitForCheckpoint: true, PollInterval: time.Millisecond})
+	require.True(t, errors.Is(err, suiclient.ErrTransactionNotCheckpointed))
+	require.Equal(t, testDigest, &resp.Digest)
+
+	// a hard failure isn't retried, on a new server since the canceled polls above may still be served
+	rpc, client = newFakeRPC(t)
+	rpc.handle("sui_getTransactionBlock", func([]json.RawMessage) (any, error) { return nil, errors.New("internal error") })
+	_, err = client.WaitForTransaction(context.Background(), testDigest, opts)
+	require.ErrorContains(t, err, "internal error")
+	require.False(t, suiclient.IsTransactionNotFound(err))
+	require.Equal(t, 1, rpc.count("sui_getTransactionBlock"))
+}
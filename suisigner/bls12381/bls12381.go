@@ -0,0 +1,247 @@
+// Package bls12381 implements the BLS12-381 keys that Sui validators use as the protocol keys.
+// It follows the min_sig scheme of fastcrypto: the public keys are in G2 and the signatures are in G1.
+package bls12381
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	bls "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	PrivateKeySize = fr.Bytes
+	PublicKeySize  = bls.SizeOfG2AffineCompressed
+	SignatureSize  = bls.SizeOfG1AffineCompressed
+)
+
+// DST is the domain separation tag of hashing the messages to G1
+var DST = []byte("BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_")
+
+type PublicKey struct {
+	point bls.G2Affine
+}
+
+type Signature struct {
+	point bls.G1Affine
+}
+
+type KeyPair struct {
+	secret    *big.Int
+	PublicKey *PublicKey
+}
+
+// GenerateKeyPair generates a key pair from 32 random bytes of key material, the same as `sui keytool generate bls12381`
+func GenerateKeyPair() (*KeyPair, error) {
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, ikm); err != nil {
+		return nil, err
+	}
+	return NewKeyPairFromSeed(ikm)
+}
+
+// NewKeyPairFromSeed derives a key pair from at least 32 bytes of key material with KeyGen
+// in the IETF BLS signature draft, which is also the master key derivation in EIP-2333
+func NewKeyPairFromSeed(ikm []byte) (*KeyPair, error) {
+	if len(ikm) < 32 {
+		return nil, errors.New("key material must be at least 32 bytes")
+	}
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	secret := new(big.Int)
+	for secret.Sign() == 0 {
+		hash := sha256.Sum256(salt)
+		salt = hash[:]
+		// L = ceil((3 * ceil(log2(r))) / 16) = 48
+		okm := make([]byte, 48)
+		reader := hkdf.New(sha256.New, append(append([]byte{}, ikm...), 0), salt, []byte{0, 48})
+		if _, err := io.ReadFull(reader, okm); err != nil {
+			return nil, err
+		}
+		secret.SetBytes(okm)
+		secret.Mod(secret, fr.Modulus())
+	}
+	return newKeyPair(secret), nil
+}
+
+// NewKeyPairFromPrivateKey creates a key pair from the 32 bytes big-endian private key
+func NewKeyPairFromPrivateKey(privateKey []byte) (*KeyPair, error) {
+	if len(privateKey) != PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length: %d", len(privateKey))
+	}
+	secret := new(big.Int).SetBytes(privateKey)
+	if secret.Sign() == 0 || secret.Cmp(fr.Modulus()) >= 0 {
+		return nil, errors.New("invalid bls12381 private key")
+	}
+	return newKeyPair(secret), nil
+}
+
+// NewKeyPairFromBase64 parses the private key in base64, which is the format of the key files
+// written by `sui keytool generate bls12381`. The key may be prefixed with the BLS12381 flag.
+func NewKeyPairFromBase64(str string) (*KeyPair, error) {
+	b, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == PrivateKeySize+1 && b[0] == suisigner.KeySchemeFlagBLS12381.Byte() {
+		b = b[1:]
+	}
+	return NewKeyPairFromPrivateKey(b)
+}
+
+func newKeyPair(secret *big.Int) *KeyPair {
+	_, _, _, g2 := bls.Generators()
+	var pubkey PublicKey
+	pubkey.point.ScalarMultiplication(&g2, secret)
+	return &KeyPair{
+		secret:    secret,
+		PublicKey: &pubkey,
+	}
+}
+
+// PrivateKey returns the 32 bytes big-endian private key
+func (k *KeyPair) PrivateKey() []byte {
+	return k.secret.FillBytes(make([]byte, PrivateKeySize))
+}
+
+// EncodeBase64 encodes the private key in base64, the format of the Sui key files
+func (k *KeyPair) EncodeBase64() string {
+	return base64.StdEncoding.EncodeToString(k.PrivateKey())
+}
+
+// Sign signs the message as it is, hashing it to G1 with DST
+func (k *KeyPair) Sign(message []byte) (*Signature, error) {
+	point, err := bls.HashToG1(message, DST)
+	if err != nil {
+		return nil, err
+	}
+	var signature Signature
+	signature.point.ScalarMultiplication(&point, k.secret)
+	return &signature, nil
+}
+
+// NewPublicKeyFromBytes parses a 96 bytes compressed public key. The point must be in the subgroup and not the identity.
+func NewPublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length: %d", len(b))
+	}
+	var pubkey PublicKey
+	if _, err := pubkey.point.SetBytes(b); err != nil {
+		return nil, fmt.Errorf("invalid bls12381 public key: %w", err)
+	}
+	if pubkey.point.IsInfinity() {
+		return nil, errors.New("invalid bls12381 public key: identity")
+	}
+	return &pubkey, nil
+}
+
+func (p *PublicKey) Bytes() []byte {
+	b := p.point.Bytes()
+	return b[:]
+}
+
+// Address returns the Sui address of the public key, which is not the address of the validator account
+func (p *PublicKey) Address() *sui.Address {
+	return suisigner.AddressFromPublicKey(suisigner.KeySchemeFlagBLS12381, p.Bytes())
+}
+
+// Verify verifies the signature over the message
+func (p *PublicKey) Verify(message []byte, signature *Signature) error {
+	return AggregateVerify([]*PublicKey{p}, [][]byte{message}, signature)
+}
+
+// NewSignatureFromBytes parses a 48 bytes compressed signature. The point must be in the subgroup.
+func NewSignatureFromBytes(b []byte) (*Signature, error) {
+	if len(b) != SignatureSize {
+		return nil, fmt.Errorf("invalid signature length: %d", len(b))
+	}
+	var signature Signature
+	if _, err := signature.point.SetBytes(b); err != nil {
+		return nil, fmt.Errorf("invalid bls12381 signature: %w", err)
+	}
+	return &signature, nil
+}
+
+func (s *Signature) Bytes() []byte {
+	b := s.point.Bytes()
+	return b[:]
+}
+
+// AggregateSignatures adds up the signatures
+func AggregateSignatures(signatures []*Signature) (*Signature, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures to aggregate")
+	}
+	var sum bls.G1Jac
+	for _, signature := range signatures {
+		sum.AddMixed(&signature.point)
+	}
+	var aggregated Signature
+	aggregated.point.FromJacobian(&sum)
+	return &aggregated, nil
+}
+
+// AggregatePublicKeys adds up the public keys
+func AggregatePublicKeys(pubkeys []*PublicKey) (*PublicKey, error) {
+	if len(pubkeys) == 0 {
+		return nil, errors.New("no public keys to aggregate")
+	}
+	var sum bls.G2Jac
+	for _, pubkey := range pubkeys {
+		sum.AddMixed(&pubkey.point)
+	}
+	var aggregated PublicKey
+	aggregated.point.FromJacobian(&sum)
+	return &aggregated, nil
+}
+
+// VerifyAggregate verifies an aggregated signature of the same message by all the public keys,
+// such as the certificates signed by a quorum of validators. The public keys must have been checked
+// with their proofs of possession to rule out rogue key attacks.
+func VerifyAggregate(pubkeys []*PublicKey, message []byte, signature *Signature) error {
+	pubkey, err := AggregatePublicKeys(pubkeys)
+	if err != nil {
+		return err
+	}
+	return pubkey.Verify(message, signature)
+}
+
+// AggregateVerify verifies an aggregated signature of distinct messages, where `messages[i]` is signed by `pubkeys[i]`
+func AggregateVerify(pubkeys []*PublicKey, messages [][]byte, signature *Signature) error {
+	if len(pubkeys) == 0 || len(pubkeys) != len(messages) {
+		return errors.New("mismatched public keys and messages")
+	}
+	if signature == nil || signature.point.IsInfinity() {
+		return suisigner.ErrInvalidSignature
+	}
+	_, _, _, g2 := bls.Generators()
+	var negG2 bls.G2Affine
+	negG2.Neg(&g2)
+
+	g1Points := []bls.G1Affine{signature.point}
+	g2Points := []bls.G2Affine{negG2}
+	for i, message := range messages {
+		point, err := bls.HashToG1(message, DST)
+		if err != nil {
+			return err
+		}
+		g1Points = append(g1Points, point)
+		g2Points = append(g2Points, pubkeys[i].point)
+	}
+	ok, err := bls.PairingCheck(g1Points, g2Points)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return suisigner.ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package bls12381_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/bls12381"
+)
+
+func TestNewKeyPairFromSeed(t *testing.T) {
+	// test case 0 of EIP-2333, whose master key derivation is KeyGen
+	seed, err := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	require.NoError(t, err)
+	keypair, err := bls12381.NewKeyPairFromSeed(seed)
+	require.NoError(t, err)
+	expected, _ := new(big.Int).SetString("6083874454709270928345386274498605044986640685124978867557563392430687146096", 10)
+	require.Equal(t, expected.FillBytes(make([]byte, 32)), keypair.PrivateKey())
+
+	_, err = bls12381.NewKeyPairFromSeed(seed[:31])
+	require.Error(t, err)
+}
+
+func TestKeyPairEncoding(t *testing.T) {
+	keypair, err := bls12381.GenerateKeyPair()
+	require.NoError(t, err)
+
+	decoded, err := bls12381.NewKeyPairFromBase64(keypair.EncodeBase64())
+	require.NoError(t, err)
+	require.Equal(t, keypair.PrivateKey(), decoded.PrivateKey())
+	require.Equal(t, keypair.PublicKey.Bytes(), decoded.PublicKey.Bytes())
+
+	pubkey, err := bls12381.NewPublicKeyFromBytes(keypair.PublicKey.Bytes())
+	require.NoError(t, err)
+	require.Len(t, pubkey.Bytes(), bls12381.PublicKeySize)
+	require.Equal(t, keypair.PublicKey.Bytes(), pubkey.Bytes())
+
+	_, err = bls12381.NewKeyPairFromPrivateKey(make([]byte, 32))
+	require.Error(t, err)
+	_, err = bls12381.NewPublicKeyFromBytes(make([]byte, bls12381.PublicKeySize))
+	require.Error(t, err)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	keypair, err := bls12381.GenerateKeyPair()
+	require.NoError(t, err)
+	msg := []byte("checkpoint summary")
+
+	signature, err := keypair.Sign(msg)
+	require.NoError(t, err)
+	parsed, err := bls12381.NewSignatureFromBytes(signature.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, keypair.PublicKey.Verify(msg, parsed))
+	require.ErrorIs(t, keypair.PublicKey.Verify([]byte("another summary"), parsed), suisigner.ErrInvalidSignature)
+
+	other, err := bls12381.GenerateKeyPair()
+	require.NoError(t, err)
+	require.ErrorIs(t, other.PublicKey.Verify(msg, parsed), suisigner.ErrInvalidSignature)
+}
+
+func TestAggregateSignatures(t *testing.T) {
+	msg := []byte("checkpoint summary")
+	var pubkeys []*bls12381.PublicKey
+	var signatures []*bls12381.Signature
+	var msgs [][]byte
+	var distinctSignatures []*bls12381.Signature
+	for i := 0; i < 4; i++ {
+		keypair, err := bls12381.GenerateKeyPair()
+		require.NoError(t, err)
+		pubkeys = append(pubkeys, keypair.PublicKey)
+
+		signature, err := keypair.Sign(msg)
+		require.NoError(t, err)
+		signatures = append(signatures, signature)
+
+		distinctMsg := append([]byte{byte(i)}, msg...)
+		msgs = append(msgs, distinctMsg)
+		signature, err = keypair.Sign(distinctMsg)
+		require.NoError(t, err)
+		distinctSignatures = append(distinctSignatures, signature)
+	}
+
+	aggregated, err := bls12381.AggregateSignatures(signatures)
+	require.NoError(t, err)
+	require.NoError(t, bls12381.VerifyAggregate(pubkeys, msg, aggregated))
+	// a missing signer
+	require.ErrorIs(t, bls12381.VerifyAggregate(pubkeys[:3], msg, aggregated), suisigner.ErrInvalidSignature)
+
+	aggregated, err = bls12381.AggregateSignatures(distinctSignatures)
+	require.NoError(t, err)
+	require.NoError(t, bls12381.AggregateVerify(pubkeys, msgs, aggregated))
+	msgs[0], msgs[1] = msgs[1], msgs[0]
+	require.ErrorIs(t, bls12381.AggregateVerify(pubkeys, msgs, aggregated), suisigner.ErrInvalidSignature)
+}
+
+func TestProofOfPossession(t *testing.T) {
+	keypair, err := bls12381.GenerateKeyPair()
+	require.NoError(t, err)
+	address := sui.MustAddressFromHex("0x1a02d61c6434b4d0ff252a880c04050b5f27c8b574026c98dd72268865c0ede5")
+
+	pop, err := keypair.ProofOfPossession(address)
+	require.NoError(t, err)
+	require.Len(t, pop.Bytes(), bls12381.SignatureSize)
+	require.NoError(t, bls12381.VerifyProofOfPossession(keypair.PublicKey, address, pop))
+
+	otherAddress := sui.MustAddressFromHex("0x2")
+	require.ErrorIs(t, bls12381.VerifyProofOfPossession(keypair.PublicKey, otherAddress, pop), suisigner.ErrInvalidSignature)
+}
+
+func TestProofOfPossessionVector(t *testing.T) {
+	// VALID_ADDRESS, VALID_PUBKEY and PROOF_OF_POSSESSION of sui-system/tests/validator_tests.move in Sui,
+	// which are generated by `generate_proof_of_possession()`
+	address := sui.MustAddressFromHex("0xaf76afe6f866d8426d2be85d6ef0b11f871a251d043b2f11e15563bf418f5a5a")
+	pubkeyBytes, err := hex.DecodeString("99f25ef61f8032b914636460982c5cc6f134ef1ddae76657f2cbfec1ebfc8d097374080df6fcf0dcb8bc4b0d8e0af5d80ebbff2b4c599f54f42d6312dfc314276078c1cc347ebbbec5198be258513f386b930d02c2749a803e2330955ebd1a10")
+	require.NoError(t, err)
+	popBytes, err := hex.DecodeString("b01cc86f421beca7ab4cfca87c0799c4d038c199dd399fbec1924d4d4367866dba9e84d514710b91feb65316e4ceef43")
+	require.NoError(t, err)
+
+	pubkey, err := bls12381.NewPublicKeyFromBytes(pubkeyBytes)
+	require.NoError(t, err)
+	pop, err := bls12381.NewSignatureFromBytes(popBytes)
+	require.NoError(t, err)
+	require.NoError(t, bls12381.VerifyProofOfPossession(pubkey, address, pop))
+	require.ErrorIs(t, bls12381.VerifyProofOfPossession(pubkey, sui.MustAddressFromHex("0x2"), pop), suisigner.ErrInvalidSignature)
+}
@@ -0,0 +1,46 @@
+package bls12381
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// ProofOfPossessionIntent is the intent of the proof of possession of a protocol key
+func ProofOfPossessionIntent() suisigner.Intent {
+	return suisigner.NewIntent(suisigner.IntentScope{ProofOfPossession: &sui.EmptyEnum{}})
+}
+
+// proofOfPossessionMessage follows `generate_proof_of_possession()` in Sui. The signed bytes are
+// `bcs(IntentMessage(pubkey || address))` followed by the epoch 0 in u64 little-endian.
+func proofOfPossessionMessage(pubkey *PublicKey, address *sui.Address) ([]byte, error) {
+	msg := append(pubkey.Bytes(), address[:]...)
+	bcsMsg, err := bcs.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode proof of possession message: %w", err)
+	}
+	data := suisigner.MessageWithIntent(ProofOfPossessionIntent(), bcsMsg)
+	return binary.LittleEndian.AppendUint64(data, 0), nil
+}
+
+// ProofOfPossession signs the protocol public key and the validator account address, which is
+// required by `request_add_validator_candidate`
+func (k *KeyPair) ProofOfPossession(address *sui.Address) (*Signature, error) {
+	msg, err := proofOfPossessionMessage(k.PublicKey, address)
+	if err != nil {
+		return nil, err
+	}
+	return k.Sign(msg)
+}
+
+// VerifyProofOfPossession verifies the proof of possession of the protocol key for the validator account address
+func VerifyProofOfPossession(pubkey *PublicKey, address *sui.Address, pop *Signature) error {
+	msg, err := proofOfPossessionMessage(pubkey, address)
+	if err != nil {
+		return err
+	}
+	return pubkey.Verify(msg, pop)
+}
@@ -0,0 +1,131 @@
+package suisigner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pattonkan/sui-go/sui"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ClientConfigFileName = "client.yaml"
+	KeystoreFileName     = "sui.keystore"
+)
+
+// ClientConfig is the `client.yaml` of Sui CLI, which records the keystore location,
+// the configured environments and the active address
+type ClientConfig struct {
+	Keystore struct {
+		File string `yaml:"File"`
+	} `yaml:"keystore"`
+	Envs          []*SuiEnv `yaml:"envs"`
+	ActiveEnv     *string   `yaml:"active_env"`
+	ActiveAddress *string   `yaml:"active_address"`
+
+	// directory of the config file, which relative keystore paths are resolved against
+	dir string
+}
+
+type SuiEnv struct {
+	Alias     string  `yaml:"alias"`
+	Rpc       string  `yaml:"rpc"`
+	Ws        *string `yaml:"ws"`
+	BasicAuth *struct {
+		Username string `yaml:"username"`
+		Password string `yaml:"password"`
+	} `yaml:"basic_auth"`
+}
+
+// DefaultSuiConfigDir returns the config directory used by Sui CLI,
+// which is `$SUI_CONFIG_DIR` if set, otherwise `~/.sui/sui_config`
+func DefaultSuiConfigDir() (string, error) {
+	if dir := os.Getenv("SUI_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".sui", "sui_config"), nil
+}
+
+// LoadDefaultClientConfig loads `client.yaml` in the default Sui config directory
+func LoadDefaultClientConfig() (*ClientConfig, error) {
+	dir, err := DefaultSuiConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return LoadClientConfig(filepath.Join(dir, ClientConfigFileName))
+}
+
+func LoadClientConfig(path string) (*ClientConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client config: %w", err)
+	}
+	var config ClientConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse client config: %w", err)
+	}
+	config.dir = filepath.Dir(path)
+	return &config, nil
+}
+
+// KeystorePath returns the path of the keystore file
+func (c *ClientConfig) KeystorePath() string {
+	path := c.Keystore.File
+	if path == "" {
+		path = KeystoreFileName
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.dir, path)
+	}
+	return path
+}
+
+// GetActiveEnv returns the active environment
+func (c *ClientConfig) GetActiveEnv() (*SuiEnv, error) {
+	if c.ActiveEnv == nil {
+		return nil, errors.New("no active env")
+	}
+	for _, env := range c.Envs {
+		if env.Alias == *c.ActiveEnv {
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("active env %s not found", *c.ActiveEnv)
+}
+
+// GetActiveAddress returns the active address
+func (c *ClientConfig) GetActiveAddress() (*sui.Address, error) {
+	if c.ActiveAddress == nil {
+		return nil, errors.New("no active address")
+	}
+	return sui.AddressFromHex(*c.ActiveAddress)
+}
+
+// ReadKeystore reads all the keys in the keystore of the config
+func (c *ClientConfig) ReadKeystore() ([]*Signer, error) {
+	return ReadKeystore(c.KeystorePath())
+}
+
+// GetActiveSigner returns the signer of the active address from the keystore
+func (c *ClientConfig) GetActiveSigner() (*Signer, error) {
+	address, err := c.GetActiveAddress()
+	if err != nil {
+		return nil, err
+	}
+	signers, err := c.ReadKeystore()
+	if err != nil {
+		return nil, err
+	}
+	for _, signer := range signers {
+		if *signer.Address == *address {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("active address %s not found in keystore", address)
+}
@@ -0,0 +1,340 @@
+package suisigner
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pattonkan/sui-go/sui"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const EncryptedKeystoreVersion = 1
+
+const (
+	KdfScrypt   = "scrypt"
+	KdfArgon2id = "argon2id"
+)
+
+const (
+	encryptionKeyLength = 32
+	kdfSaltLength       = 32
+)
+
+var (
+	ErrWrongPassword   = errors.New("wrong password or corrupted keystore")
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountExists   = errors.New("account already exists")
+)
+
+// KdfParams are the parameters of the key derivation function which turns the password
+// into the AES-256-GCM encryption key. Only the fields of the chosen KDF are used.
+type KdfParams struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt"`
+
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"` // in KiB
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// DefaultScryptParams returns the recommended scrypt parameters for interactive logins with a new salt
+func DefaultScryptParams() KdfParams {
+	return KdfParams{Name: KdfScrypt, N: 1 << 15, R: 8, P: 1}
+}
+
+// DefaultArgon2idParams returns the recommended argon2id parameters (RFC 9106) with a new salt
+func DefaultArgon2idParams() KdfParams {
+	return KdfParams{Name: KdfArgon2id, Time: 3, Memory: 64 * 1024, Threads: 4}
+}
+
+func (k *KdfParams) deriveKey(password string) ([]byte, error) {
+	switch k.Name {
+	case KdfScrypt:
+		return scrypt.Key([]byte(password), k.Salt, k.N, k.R, k.P, encryptionKeyLength)
+	case KdfArgon2id:
+		if k.Time == 0 || k.Memory == 0 || k.Threads == 0 {
+			return nil, errors.New("invalid argon2id parameters")
+		}
+		return argon2.IDKey([]byte(password), k.Salt, k.Time, k.Memory, k.Threads, encryptionKeyLength), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf: %s", k.Name)
+	}
+}
+
+// EncryptedAccount is a labelled key in the EncryptedKeystore.
+// The ciphertext is `flag || privkey` sealed with AES-256-GCM, bound to the label and address.
+type EncryptedAccount struct {
+	Label      string        `json:"label"`
+	Address    *sui.Address  `json:"address"`
+	Scheme     KeySchemeFlag `json:"scheme"`
+	Nonce      []byte        `json:"nonce"`
+	Ciphertext []byte        `json:"ciphertext"`
+}
+
+func (a *EncryptedAccount) additionalData() []byte {
+	return append([]byte(a.Label), a.Address[:]...)
+}
+
+// EncryptedKeystore is a password protected keystore holding multiple labelled accounts,
+// stored as versioned JSON. All accounts in a keystore are encrypted under the same password.
+// Private keys are only decrypted on demand, see `WithSigner()`.
+type EncryptedKeystore struct {
+	Version  int                 `json:"version"`
+	Kdf      KdfParams           `json:"kdf"`
+	Accounts []*EncryptedAccount `json:"accounts"`
+}
+
+// NewEncryptedKeystore creates an empty keystore with the given KDF, a new salt is generated if none is set
+func NewEncryptedKeystore(kdf KdfParams) (*EncryptedKeystore, error) {
+	if len(kdf.Salt) == 0 {
+		kdf.Salt = make([]byte, kdfSaltLength)
+		if _, err := rand.Read(kdf.Salt); err != nil {
+			return nil, err
+		}
+	}
+	return &EncryptedKeystore{
+		Version: EncryptedKeystoreVersion,
+		Kdf:     kdf,
+	}, nil
+}
+
+func LoadEncryptedKeystore(path string) (*EncryptedKeystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var ks EncryptedKeystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %w", err)
+	}
+	if ks.Version != EncryptedKeystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version: %d", ks.Version)
+	}
+	return &ks, nil
+}
+
+// Save writes the keystore to path atomically with permission 0600
+func (ks *EncryptedKeystore) Save(path string) error {
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (ks *EncryptedKeystore) Labels() []string {
+	labels := make([]string, len(ks.Accounts))
+	for i, account := range ks.Accounts {
+		labels[i] = account.Label
+	}
+	return labels
+}
+
+func (ks *EncryptedKeystore) Account(label string) (*EncryptedAccount, error) {
+	for _, account := range ks.Accounts {
+		if account.Label == label {
+			return account, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, label)
+}
+
+// AddAccount encrypts the key of signer under label. The password must be the same as the one
+// of the accounts already in the keystore.
+func (ks *EncryptedKeystore) AddAccount(label string, signer *Signer, password string) error {
+	if _, err := ks.Account(label); err == nil {
+		return fmt.Errorf("%w: %s", ErrAccountExists, label)
+	}
+	key, err := ks.unlock(password)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(key)
+
+	account, err := encryptAccount(key, label, signer)
+	if err != nil {
+		return err
+	}
+	ks.Accounts = append(ks.Accounts, account)
+	return nil
+}
+
+func (ks *EncryptedKeystore) RemoveAccount(label string) error {
+	for i, account := range ks.Accounts {
+		if account.Label == label {
+			ks.Accounts = append(ks.Accounts[:i], ks.Accounts[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrAccountNotFound, label)
+}
+
+// Signer decrypts the account into a Signer. The caller should call `Signer.Wipe()` once it's done,
+// or use `WithSigner()` instead.
+func (ks *EncryptedKeystore) Signer(label string, password string) (*Signer, error) {
+	account, err := ks.Account(label)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.Kdf.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	defer wipeBytes(key)
+	return decryptAccount(key, account)
+}
+
+// WithSigner decrypts the account, calls fn with it and wipes the private key afterwards
+func (ks *EncryptedKeystore) WithSigner(label string, password string, fn func(*Signer) error) error {
+	signer, err := ks.Signer(label, password)
+	if err != nil {
+		return err
+	}
+	defer signer.Wipe()
+	return fn(signer)
+}
+
+// ChangePassword re-encrypts all the accounts under newPassword with a new salt
+func (ks *EncryptedKeystore) ChangePassword(oldPassword string, newPassword string) error {
+	oldKey, err := ks.unlock(oldPassword)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(oldKey)
+
+	newKdf := ks.Kdf
+	newKdf.Salt = make([]byte, kdfSaltLength)
+	if _, err := rand.Read(newKdf.Salt); err != nil {
+		return err
+	}
+	newKey, err := newKdf.deriveKey(newPassword)
+	if err != nil {
+		return err
+	}
+	defer wipeBytes(newKey)
+
+	accounts := make([]*EncryptedAccount, len(ks.Accounts))
+	for i, account := range ks.Accounts {
+		signer, err := decryptAccount(oldKey, account)
+		if err != nil {
+			return err
+		}
+		accounts[i], err = encryptAccount(newKey, account.Label, signer)
+		signer.Wipe()
+		if err != nil {
+			return err
+		}
+	}
+	ks.Kdf = newKdf
+	ks.Accounts = accounts
+	return nil
+}
+
+// unlock derives the encryption key and checks it against the existing accounts
+func (ks *EncryptedKeystore) unlock(password string) ([]byte, error) {
+	key, err := ks.Kdf.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	if len(ks.Accounts) > 0 {
+		signer, err := decryptAccount(key, ks.Accounts[0])
+		if err != nil {
+			wipeBytes(key)
+			return nil, err
+		}
+		signer.Wipe()
+	}
+	return key, nil
+}
+
+func encryptAccount(key []byte, label string, signer *Signer) (*EncryptedAccount, error) {
+	plaintext, err := signer.flaggedPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	defer wipeBytes(plaintext)
+
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+	account := &EncryptedAccount{
+		Label:   label,
+		Address: signer.Address,
+		Scheme:  signer.Scheme(),
+		Nonce:   make([]byte, aead.NonceSize()),
+	}
+	if _, err := rand.Read(account.Nonce); err != nil {
+		return nil, err
+	}
+	account.Ciphertext = aead.Seal(nil, account.Nonce, plaintext, account.additionalData())
+	return account, nil
+}
+
+func decryptAccount(key []byte, account *EncryptedAccount) (*Signer, error) {
+	aead, err := newAead(key)
+	if err != nil {
+		return nil, err
+	}
+	if account.Address == nil || len(account.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid account: %s", account.Label)
+	}
+	plaintext, err := aead.Open(nil, account.Nonce, account.Ciphertext, account.additionalData())
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	defer wipeBytes(plaintext)
+
+	signer, err := newSignerWithFlaggedPrivateKey(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if *signer.Address != *account.Address {
+		signer.Wipe()
+		return nil, fmt.Errorf("address mismatch of account: %s", account.Label)
+	}
+	return signer, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func wipeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
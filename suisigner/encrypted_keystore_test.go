@@ -0,0 +1,78 @@
+package suisigner_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// cheap parameters to keep the tests fast
+var testKdfParams = []suisigner.KdfParams{
+	{Name: suisigner.KdfScrypt, N: 1 << 10, R: 8, P: 1},
+	{Name: suisigner.KdfArgon2id, Time: 1, Memory: 1024, Threads: 1},
+}
+
+func TestEncryptedKeystore(t *testing.T) {
+	for _, kdf := range testKdfParams {
+		t.Run(kdf.Name, func(t *testing.T) {
+			ed25519Signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagEd25519)
+			require.NoError(t, err)
+			secp256k1Signer, err := suisigner.NewSignerWithPrivateKey(suisigner.KeySchemeFlagSecp256k1, suisigner.TEST_SEED)
+			require.NoError(t, err)
+
+			ks, err := suisigner.NewEncryptedKeystore(kdf)
+			require.NoError(t, err)
+			require.NoError(t, ks.AddAccount("hot", ed25519Signer, "password"))
+			require.NoError(t, ks.AddAccount("cold", secp256k1Signer, "password"))
+			require.ErrorIs(t, ks.AddAccount("hot", secp256k1Signer, "password"), suisigner.ErrAccountExists)
+			require.ErrorIs(t, ks.AddAccount("other", secp256k1Signer, "wrong"), suisigner.ErrWrongPassword)
+
+			path := filepath.Join(t.TempDir(), "keystore.json")
+			require.NoError(t, ks.Save(path))
+			loaded, err := suisigner.LoadEncryptedKeystore(path)
+			require.NoError(t, err)
+			require.Equal(t, []string{"hot", "cold"}, loaded.Labels())
+
+			err = loaded.WithSigner("cold", "password", func(signer *suisigner.Signer) error {
+				require.Equal(t, secp256k1Signer.Address, signer.Address)
+				return nil
+			})
+			require.NoError(t, err)
+			_, err = loaded.Signer("hot", "wrong")
+			require.ErrorIs(t, err, suisigner.ErrWrongPassword)
+			_, err = loaded.Signer("unknown", "password")
+			require.ErrorIs(t, err, suisigner.ErrAccountNotFound)
+
+			// swapping the ciphertext of two accounts must be detected
+			loaded.Accounts[0].Ciphertext, loaded.Accounts[1].Ciphertext = loaded.Accounts[1].Ciphertext, loaded.Accounts[0].Ciphertext
+			_, err = loaded.Signer("hot", "password")
+			require.ErrorIs(t, err, suisigner.ErrWrongPassword)
+		})
+	}
+}
+
+func TestEncryptedKeystoreChangePassword(t *testing.T) {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagEd25519)
+	require.NoError(t, err)
+
+	ks, err := suisigner.NewEncryptedKeystore(testKdfParams[0])
+	require.NoError(t, err)
+	require.NoError(t, ks.AddAccount("main", signer, "old password"))
+	oldSalt := ks.Kdf.Salt
+
+	require.ErrorIs(t, ks.ChangePassword("wrong", "new password"), suisigner.ErrWrongPassword)
+	require.NoError(t, ks.ChangePassword("old password", "new password"))
+	require.NotEqual(t, oldSalt, ks.Kdf.Salt)
+
+	_, err = ks.Signer("main", "old password")
+	require.ErrorIs(t, err, suisigner.ErrWrongPassword)
+	decrypted, err := ks.Signer("main", "new password")
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, decrypted.Address)
+
+	decrypted.Wipe()
+	require.Nil(t, decrypted.PrivateKey())
+}
@@ -16,11 +16,10 @@ type Intent struct {
 	AppId AppId
 }
 
-func DefaultIntent() Intent {
+// NewIntent creates an intent of the given scope for the Sui app with intent version V0
+func NewIntent(scope IntentScope) Intent {
 	return Intent{
-		Scope: IntentScope{
-			TransactionData: &sui.EmptyEnum{},
-		},
+		Scope: scope,
 		Version: IntentVersion{
 			V0: &sui.EmptyEnum{},
 		},
@@ -30,6 +29,15 @@ func DefaultIntent() Intent {
 	}
 }
 
+func DefaultIntent() Intent {
+	return NewIntent(IntentScope{TransactionData: &sui.EmptyEnum{}})
+}
+
+// PersonalMessageIntent is the intent used to sign and verify personal messages
+func PersonalMessageIntent() Intent {
+	return NewIntent(IntentScope{PersonalMessage: &sui.EmptyEnum{}})
+}
+
 func (i *Intent) Bytes() []byte {
 	b, err := bcs.Marshal(i)
 	if err != nil {
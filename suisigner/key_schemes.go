@@ -1,8 +1,14 @@
 package suisigner
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"math"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/pattonkan/sui-go/sui"
+	"golang.org/x/crypto/blake2b"
 )
 
 type KeySchemeFlag byte
@@ -16,6 +22,7 @@ const (
 	KeySchemeFlagMultiSig
 	KeySchemeFlagBLS12381
 	KeySchemeFlagZkLoginAuthenticator
+	KeySchemeFlagPasskey
 
 	KeySchemeFlagIotaEd25519 = math.MaxUint8 - 1 // special case for iota ed25519
 	KeySchemeFlagError       = math.MaxUint8
@@ -28,6 +35,12 @@ func (k KeySchemeFlag) Byte() byte {
 const (
 	PublicKeyLengthEd25519   = 32
 	PublicKeyLengthSecp256k1 = 33
+	PublicKeyLengthSecp256r1 = 33
+)
+
+const (
+	SignatureLengthSecp256k1 = 64
+	SignatureLengthSecp256r1 = 64
 )
 
 const (
@@ -47,3 +60,34 @@ func NewKeypairEd25519(prikey ed25519.PrivateKey, pubkey ed25519.PublicKey) *Key
 		PubKey: pubkey,
 	}
 }
+
+type KeypairSecp256k1 struct {
+	PriKey *secp256k1.PrivateKey
+	PubKey []byte // compressed public key
+}
+
+func NewKeypairSecp256k1(prikey *secp256k1.PrivateKey) *KeypairSecp256k1 {
+	return &KeypairSecp256k1{
+		PriKey: prikey,
+		PubKey: prikey.PubKey().SerializeCompressed(),
+	}
+}
+
+type KeypairSecp256r1 struct {
+	PriKey *ecdsa.PrivateKey
+	PubKey []byte // compressed public key
+}
+
+func NewKeypairSecp256r1(prikey *ecdsa.PrivateKey) *KeypairSecp256r1 {
+	return &KeypairSecp256r1{
+		PriKey: prikey,
+		PubKey: elliptic.MarshalCompressed(elliptic.P256(), prikey.X, prikey.Y),
+	}
+}
+
+// AddressFromPublicKey derives the Sui address of a public key, which is `blake2b(flag || pubkey)`
+func AddressFromPublicKey(flag KeySchemeFlag, pubkey []byte) *sui.Address {
+	buf := append([]byte{flag.Byte()}, pubkey...)
+	addr := sui.Address(blake2b.Sum256(buf))
+	return &addr
+}
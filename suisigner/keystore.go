@@ -0,0 +1,121 @@
+package suisigner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// SuiPrivateKeyPrefix is the human readable part of bech32 encoded private keys, e.g. `suiprivkey1...`
+const SuiPrivateKeyPrefix = "suiprivkey"
+
+// NewSignerWithBech32PrivateKey creates a signer from a bech32 encoded private key `suiprivkey1...`,
+// which is exported by `sui keytool export` and the wallets
+func NewSignerWithBech32PrivateKey(str string) (*Signer, error) {
+	hrp, data, err := bech32.Decode(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bech32 private key: %w", err)
+	}
+	if hrp != SuiPrivateKeyPrefix {
+		return nil, fmt.Errorf("invalid bech32 private key prefix: %s", hrp)
+	}
+	keyBytes, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bech32 private key: %w", err)
+	}
+	return newSignerWithFlaggedPrivateKey(keyBytes)
+}
+
+// Bech32PrivateKey encodes `flag || privkey` of the signer in bech32 with prefix `suiprivkey`
+func (s *Signer) Bech32PrivateKey() (string, error) {
+	data, err := s.flaggedPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	conv, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(SuiPrivateKeyPrefix, conv)
+}
+
+// NewSignerWithKeystoreEntry creates a signer from an entry of `sui.keystore`,
+// which is `flag || privkey` in base64
+func NewSignerWithKeystoreEntry(entry string) (*Signer, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore entry: %w", err)
+	}
+	return newSignerWithFlaggedPrivateKey(keyBytes)
+}
+
+// KeystoreEntry encodes the signer as an entry of `sui.keystore`
+func (s *Signer) KeystoreEntry() (string, error) {
+	data, err := s.flaggedPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// ReadKeystore reads all the keys in a keystore file of Sui CLI, e.g. `~/.sui/sui_config/sui.keystore`.
+// The file is a JSON array of keystore entries.
+func ReadKeystore(path string) ([]*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	var entries []string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %w", err)
+	}
+	signers := make([]*Signer, len(entries))
+	for i, entry := range entries {
+		signers[i], err = NewSignerWithKeystoreEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse keystore entry %d: %w", i, err)
+		}
+	}
+	return signers, nil
+}
+
+// WriteKeystore writes the signers into a keystore file in the format of Sui CLI
+func WriteKeystore(path string, signers []*Signer) error {
+	entries := make([]string, len(signers))
+	for i, signer := range signers {
+		entry, err := signer.KeystoreEntry()
+		if err != nil {
+			return err
+		}
+		entries[i] = entry
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func newSignerWithFlaggedPrivateKey(data []byte) (*Signer, error) {
+	if len(data) != 33 {
+		return nil, fmt.Errorf("invalid private key length: %d", len(data))
+	}
+	return NewSignerWithPrivateKey(KeySchemeFlag(data[0]), data[1:])
+}
+
+func (s *Signer) flaggedPrivateKey() ([]byte, error) {
+	var privateKey []byte
+	switch {
+	case s.ed25519Keypair != nil:
+		privateKey = s.ed25519Keypair.PriKey.Seed()
+	case s.secp256k1Keypair != nil, s.secp256r1Keypair != nil:
+		privateKey = s.PrivateKey()
+	default:
+		return nil, errors.New("signer has no private key")
+	}
+	return append([]byte{s.Scheme().Byte()}, privateKey...), nil
+}
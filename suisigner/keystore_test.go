@@ -0,0 +1,108 @@
+package suisigner_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+func TestBech32PrivateKey(t *testing.T) {
+	// test vectors from the keytool tests in the Sui monorepo
+	testCases := []struct {
+		mnemonic  string
+		bech32Key string
+		address   *sui.Address
+	}{
+		{
+			mnemonic:  "film crazy soon outside stand loop subway crumble thrive popular green nuclear struggle pistol arm wife phrase warfare march wheat nephew ask sunny firm",
+			bech32Key: "suiprivkey1qrwsjvr6gwaxmsvxk4cfun99ra8uwxg3c9pl0nhle7xxpe4s80y05ctazer",
+			address:   sui.MustAddressFromHex("0xa2d14fad60c56049ecf75246a481934691214ce413e6a8ae2fe6834c173a6133"),
+		},
+		{
+			mnemonic:  "require decline left thought grid priority false tiny gasp angle royal system attack beef setup reward aunt skill wasp tray vital bounce inflict level",
+			bech32Key: "suiprivkey1qzdvpa77ct272ultqcy20dkw78dysnfyg90fhcxkdm60el0qht9mvzlsh4j",
+			address:   sui.MustAddressFromHex("0x1ada6e6f3f3e4055096f606c746690f1108fcc2ca479055cc434a3e1d3f758aa"),
+		},
+		{
+			mnemonic:  "organ crash swim stick traffic remember army arctic mesh slice swear summer police vast chaos cradle squirrel hood useless evidence pet hub soap lake",
+			bech32Key: "suiprivkey1qqqscjyyr64jea849dfv9cukurqj2swx0m3rr4hr7sw955jy07tzgcde5ut",
+			address:   sui.MustAddressFromHex("0xe69e896ca10f5a77732769803cc2b5707f0ab9d4407afb5e4b4464b89769af14"),
+		},
+	}
+	for _, tc := range testCases {
+		signer, err := suisigner.NewSignerWithMnemonic(tc.mnemonic, suisigner.KeySchemeFlagEd25519)
+		require.NoError(t, err)
+		require.Equal(t, tc.address, signer.Address)
+
+		bech32Key, err := signer.Bech32PrivateKey()
+		require.NoError(t, err)
+		require.Equal(t, tc.bech32Key, bech32Key)
+
+		imported, err := suisigner.NewSignerWithBech32PrivateKey(tc.bech32Key)
+		require.NoError(t, err)
+		require.Equal(t, tc.address, imported.Address)
+	}
+
+	_, err := suisigner.NewSignerWithBech32PrivateKey("suiprivkey1qrwsjvr6gwaxmsvxk4cfun99ra8uwxg3c9pl0nhle7xxpe4s80y05ctazeq")
+	require.Error(t, err)
+}
+
+func TestReadWriteKeystore(t *testing.T) {
+	ed25519Signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagEd25519)
+	require.NoError(t, err)
+	secp256k1Signer, err := suisigner.NewSignerWithPrivateKey(suisigner.KeySchemeFlagSecp256k1, suisigner.TEST_SEED)
+	require.NoError(t, err)
+	secp256r1Signer, err := suisigner.NewSignerWithPrivateKey(suisigner.KeySchemeFlagSecp256r1, suisigner.TEST_SEED)
+	require.NoError(t, err)
+	signers := []*suisigner.Signer{ed25519Signer, secp256k1Signer, secp256r1Signer}
+
+	dir := t.TempDir()
+	keystorePath := filepath.Join(dir, suisigner.KeystoreFileName)
+	require.NoError(t, suisigner.WriteKeystore(keystorePath, signers))
+
+	loaded, err := suisigner.ReadKeystore(keystorePath)
+	require.NoError(t, err)
+	require.Len(t, loaded, len(signers))
+	for i, signer := range signers {
+		require.Equal(t, signer.Address, loaded[i].Address)
+		require.Equal(t, signer.Scheme(), loaded[i].Scheme())
+
+		txBytes := []byte("I want to have some bubble tea")
+		signature, err := loaded[i].SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+		require.NoError(t, err)
+		addr, err := suisigner.VerifyTransactionSignature(&signature, txBytes)
+		require.NoError(t, err)
+		require.Equal(t, signer.Address, addr)
+	}
+
+	clientConfig := `---
+keystore:
+  File: sui.keystore
+envs:
+  - alias: testnet
+    rpc: "https://fullnode.testnet.sui.io:443"
+    ws: ~
+    basic_auth: ~
+active_env: testnet
+active_address: "` + secp256k1Signer.Address.String() + `"
+`
+	configPath := filepath.Join(dir, suisigner.ClientConfigFileName)
+	require.NoError(t, os.WriteFile(configPath, []byte(clientConfig), 0600))
+	config, err := suisigner.LoadClientConfig(configPath)
+	require.NoError(t, err)
+
+	env, err := config.GetActiveEnv()
+	require.NoError(t, err)
+	require.Equal(t, "https://fullnode.testnet.sui.io:443", env.Rpc)
+	require.Nil(t, env.Ws)
+
+	activeSigner, err := config.GetActiveSigner()
+	require.NoError(t, err)
+	require.Equal(t, secp256k1Signer.Address, activeSigner.Address)
+	require.Equal(t, suisigner.KeySchemeFlagSecp256k1, activeSigner.Scheme())
+}
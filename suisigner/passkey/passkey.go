@@ -0,0 +1,212 @@
+// Package passkey builds and verifies the Sui passkey authenticators, which are WebAuthn
+// assertions of secp256r1 credentials over the intent message digest.
+package passkey
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// ClientDataTypeGet is the `type` of clientDataJSON in an assertion, `navigator.credentials.get()`
+const ClientDataTypeGet = "webauthn.get"
+
+// ClientData is the subset of the WebAuthn clientDataJSON that Sui checks
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// PasskeyAuthenticator is the passkey authenticator in BCS. UserSignature is a secp256r1 Sui signature,
+// `flag || signature || pubkey`, signed over `authenticatorData || sha256(clientDataJSON)`.
+type PasskeyAuthenticator struct {
+	AuthenticatorData []byte
+	ClientDataJSON    string
+	UserSignature     []byte
+}
+
+// AddressFromPublicKey derives the passkey address from the 33 bytes compressed secp256r1 public key
+func AddressFromPublicKey(pubkey []byte) *sui.Address {
+	return suisigner.AddressFromPublicKey(suisigner.KeySchemeFlagPasskey, pubkey)
+}
+
+// Challenge returns the WebAuthn challenge for signing `message` under `intent`, which is the
+// base64url of `blake2b(intent || message)` without padding. `message` is already serialized in BCS.
+func Challenge(intent suisigner.Intent, message []byte) string {
+	digest := blake2b.Sum256(suisigner.MessageWithIntent(intent, message))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// NewPasskeyAuthenticator assembles the authenticator from a WebAuthn assertion. `signature` is the
+// ASN.1 DER signature returned by the authenticator, and `pubkey` is the compressed public key of the credential.
+func NewPasskeyAuthenticator(authenticatorData []byte, clientDataJSON string, signature []byte, pubkey []byte) (*PasskeyAuthenticator, error) {
+	if len(pubkey) != suisigner.PublicKeyLengthSecp256r1 {
+		return nil, fmt.Errorf("invalid secp256r1 public key length: %d", len(pubkey))
+	}
+	r, s, err := parseDERSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	// Sui only accepts signatures in the lower-S form, which WebAuthn doesn't guarantee
+	n := elliptic.P256().Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+
+	userSignature := []byte{suisigner.KeySchemeFlagSecp256r1.Byte()}
+	userSignature = append(userSignature, r.FillBytes(make([]byte, 32))...)
+	userSignature = append(userSignature, s.FillBytes(make([]byte, 32))...)
+	userSignature = append(userSignature, pubkey...)
+	return &PasskeyAuthenticator{
+		AuthenticatorData: authenticatorData,
+		ClientDataJSON:    clientDataJSON,
+		UserSignature:     userSignature,
+	}, nil
+}
+
+// ParsePasskeyAuthenticator parses a serialized passkey authenticator, `flag || bcs(PasskeyAuthenticator)`
+func ParsePasskeyAuthenticator(signature []byte) (*PasskeyAuthenticator, error) {
+	if len(signature) == 0 || signature[0] != suisigner.KeySchemeFlagPasskey.Byte() {
+		return nil, errors.New("not a passkey signature")
+	}
+	var authenticator PasskeyAuthenticator
+	n, err := bcs.Unmarshal(signature[1:], &authenticator)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode passkey signature: %w", err)
+	}
+	if n != len(signature)-1 {
+		return nil, errors.New("trailing bytes in passkey signature")
+	}
+	return &authenticator, nil
+}
+
+// Bytes serializes the authenticator as `flag || bcs(PasskeyAuthenticator)`
+func (p *PasskeyAuthenticator) Bytes() ([]byte, error) {
+	b, err := bcs.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode passkey signature: %w", err)
+	}
+	return append([]byte{suisigner.KeySchemeFlagPasskey.Byte()}, b...), nil
+}
+
+// SuiSignature converts the authenticator to suisigner.Signature, which can be submitted to
+// `sui_executeTransactionBlock` like any other signature
+func (p *PasskeyAuthenticator) SuiSignature() (*suisigner.Signature, error) {
+	b, err := p.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return suisigner.NewSignatureFromBytes(b)
+}
+
+// PublicKey returns the compressed public key of the credential
+func (p *PasskeyAuthenticator) PublicKey() ([]byte, error) {
+	signature, err := p.userSignature()
+	if err != nil {
+		return nil, err
+	}
+	return signature.PublicKey(), nil
+}
+
+// Address returns the passkey address which the authenticator signs for
+func (p *PasskeyAuthenticator) Address() (*sui.Address, error) {
+	pubkey, err := p.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return AddressFromPublicKey(pubkey), nil
+}
+
+// Verify checks that clientDataJSON is an assertion whose challenge is the digest of `message`
+// under `intent`, and verifies the secp256r1 signature. It returns the passkey address of the signer.
+func (p *PasskeyAuthenticator) Verify(intent suisigner.Intent, message []byte) (*sui.Address, error) {
+	var clientData ClientData
+	if err := json.Unmarshal([]byte(p.ClientDataJSON), &clientData); err != nil {
+		return nil, fmt.Errorf("invalid clientDataJSON: %w", err)
+	}
+	if clientData.Type != ClientDataTypeGet {
+		return nil, fmt.Errorf("unexpected clientDataJSON type: %s", clientData.Type)
+	}
+	if clientData.Challenge != Challenge(intent, message) {
+		return nil, fmt.Errorf("%w: challenge mismatch", suisigner.ErrInvalidSignature)
+	}
+
+	signature, err := p.userSignature()
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256([]byte(p.ClientDataJSON))
+	data := append(append([]byte{}, p.AuthenticatorData...), clientDataHash[:]...)
+	if _, err := suisigner.VerifyRawSignature(signature, data); err != nil {
+		return nil, err
+	}
+	return AddressFromPublicKey(signature.PublicKey()), nil
+}
+
+// VerifySignature verifies a passkey signature over `intent || message`, where `message` is already
+// serialized in BCS, and returns the passkey address of the signer
+func VerifySignature(signature *suisigner.Signature, intent suisigner.Intent, message []byte) (*sui.Address, error) {
+	if signature == nil || signature.PasskeySuiSignature == nil {
+		return nil, errors.New("not a passkey signature")
+	}
+	authenticator, err := ParsePasskeyAuthenticator(signature.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return authenticator.Verify(intent, message)
+}
+
+// VerifyTransactionSignature verifies a passkey signature over a BCS encoded `TransactionData`
+func VerifyTransactionSignature(signature *suisigner.Signature, txnBytes []byte) (*sui.Address, error) {
+	return VerifySignature(signature, suisigner.DefaultIntent(), txnBytes)
+}
+
+// VerifyPersonalMessageSignature verifies a passkey signature over a personal message
+func VerifyPersonalMessageSignature(signature *suisigner.Signature, message []byte) (*sui.Address, error) {
+	bcsMessage, err := bcs.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode personal message: %w", err)
+	}
+	return VerifySignature(signature, suisigner.PersonalMessageIntent(), bcsMessage)
+}
+
+func (p *PasskeyAuthenticator) userSignature() (*suisigner.Signature, error) {
+	signature, err := suisigner.NewSignatureFromBytes(p.UserSignature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid passkey user signature: %w", err)
+	}
+	if signature.Secp256r1SuiSignature == nil {
+		return nil, errors.New("passkey user signature must be secp256r1")
+	}
+	return signature, nil
+}
+
+func parseDERSignature(signature []byte) (*big.Int, *big.Int, error) {
+	r, s := new(big.Int), new(big.Int)
+	var inner cryptobyte.String
+	input := cryptobyte.String(signature)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return nil, nil, errors.New("invalid ASN.1 DER signature")
+	}
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return nil, nil, errors.New("invalid ASN.1 DER signature")
+	}
+	return r, s, nil
+}
@@ -0,0 +1,112 @@
+package passkey_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/passkey"
+)
+
+// fakeAuthenticator plays the role of the browser and the WebAuthn authenticator
+type fakeAuthenticator struct {
+	key    *ecdsa.PrivateKey
+	pubkey []byte
+}
+
+func newFakeAuthenticator(t *testing.T) *fakeAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &fakeAuthenticator{
+		key:    key,
+		pubkey: elliptic.MarshalCompressed(elliptic.P256(), key.X, key.Y),
+	}
+}
+
+func (f *fakeAuthenticator) sign(t *testing.T, typ string, challenge string) *passkey.PasskeyAuthenticator {
+	rpIdHash := sha256.Sum256([]byte("example.com"))
+	// rpIdHash || flags (UP|UV) || signCount
+	authenticatorData := append(rpIdHash[:], 0x05, 0, 0, 0, 1)
+	clientDataJSON, err := json.Marshal(passkey.ClientData{
+		Type:      typ,
+		Challenge: challenge,
+		Origin:    "https://example.com",
+	})
+	require.NoError(t, err)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	hash := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	der, err := ecdsa.SignASN1(rand.Reader, f.key, hash[:])
+	require.NoError(t, err)
+
+	authenticator, err := passkey.NewPasskeyAuthenticator(authenticatorData, string(clientDataJSON), der, f.pubkey)
+	require.NoError(t, err)
+	return authenticator
+}
+
+func TestPasskeyTransactionSignature(t *testing.T) {
+	fake := newFakeAuthenticator(t)
+	txBytes := []byte("I want to have some bubble tea")
+
+	authenticator := fake.sign(t, passkey.ClientDataTypeGet, passkey.Challenge(suisigner.DefaultIntent(), txBytes))
+	signature, err := authenticator.SuiSignature()
+	require.NoError(t, err)
+	require.Equal(t, suisigner.KeySchemeFlagPasskey, signature.Scheme())
+
+	addr, err := passkey.VerifyTransactionSignature(signature, txBytes)
+	require.NoError(t, err)
+	require.Equal(t, passkey.AddressFromPublicKey(fake.pubkey), addr)
+	require.Equal(t, suisigner.AddressFromPublicKey(suisigner.KeySchemeFlagPasskey, fake.pubkey), addr)
+
+	expectedAddr, err := authenticator.Address()
+	require.NoError(t, err)
+	require.Equal(t, expectedAddr, addr)
+
+	// the BCS layout round trips
+	parsed, err := passkey.ParsePasskeyAuthenticator(signature.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, authenticator, parsed)
+
+	// the challenge commits to the message and the intent
+	_, err = passkey.VerifyTransactionSignature(signature, []byte("I want to have some milk tea"))
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+	_, err = passkey.VerifyPersonalMessageSignature(signature, txBytes)
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+
+	// suisigner doesn't verify passkey signatures itself
+	_, err = suisigner.VerifyTransactionSignature(signature, txBytes)
+	require.ErrorIs(t, err, suisigner.ErrUnsupportedSignature)
+}
+
+func TestPasskeyInvalidSignature(t *testing.T) {
+	fake := newFakeAuthenticator(t)
+	message := []byte("sign in with sui")
+	bcsMessage := append([]byte{byte(len(message))}, message...)
+	challenge := passkey.Challenge(suisigner.PersonalMessageIntent(), bcsMessage)
+
+	authenticator := fake.sign(t, passkey.ClientDataTypeGet, challenge)
+	_, err := authenticator.Verify(suisigner.PersonalMessageIntent(), bcsMessage)
+	require.NoError(t, err)
+
+	// a registration is not an assertion
+	created := fake.sign(t, "webauthn.create", challenge)
+	_, err = created.Verify(suisigner.PersonalMessageIntent(), bcsMessage)
+	require.Error(t, err)
+
+	// tampered authenticator data
+	authenticator.AuthenticatorData[len(authenticator.AuthenticatorData)-1] ^= 1
+	_, err = authenticator.Verify(suisigner.PersonalMessageIntent(), bcsMessage)
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+
+	// the signature of another credential
+	other := newFakeAuthenticator(t).sign(t, passkey.ClientDataTypeGet, challenge)
+	other.UserSignature = append(other.UserSignature[:1+suisigner.SignatureLengthSecp256r1], fake.pubkey...)
+	_, err = other.Verify(suisigner.PersonalMessageIntent(), bcsMessage)
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+}
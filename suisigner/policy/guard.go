@@ -0,0 +1,337 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// DryRunner dry-runs transactions, which is implemented by `suiclient.ClientImpl`
+type DryRunner interface {
+	DryRunTransaction(ctx context.Context, txDataBytes sui.Base64Data) (*suiclient.DryRunTransactionBlockResponse, error)
+}
+
+// Guard wraps a signer, and only signs the transactions allowed by the policy
+type Guard struct {
+	signer suisigner.TransactionSigner
+	policy *Policy
+	client DryRunner
+
+	// Now is the clock of the spending windows, which defaults to time.Now
+	Now func() time.Time
+
+	mu     sync.Mutex
+	spends []spend
+}
+
+type spend struct {
+	at       time.Time
+	coinType string
+	amount   *big.Int
+}
+
+var _ suisigner.TransactionSigner = (*Guard)(nil)
+
+// NewGuard wraps the signer with the policy. The client is optional, but it is required by
+// the spending limits and RequireDryRun.
+func NewGuard(signer suisigner.TransactionSigner, policy *Policy, client DryRunner) *Guard {
+	return &Guard{
+		signer: signer,
+		policy: policy,
+		client: client,
+		Now:    time.Now,
+	}
+}
+
+func (g *Guard) GetAddress() *sui.Address {
+	return g.signer.GetAddress()
+}
+
+func (g *Guard) SignTransactionBlock(txnBytes []byte, intent suisigner.Intent) (suisigner.Signature, error) {
+	return g.SignTransactionBlockWithContext(context.Background(), txnBytes, intent)
+}
+
+// SignTransactionBlockWithContext evaluates the policy and signs the transaction. The spending
+// is recorded once signed, as the guard can't tell whether the transaction is executed.
+func (g *Guard) SignTransactionBlockWithContext(ctx context.Context, txnBytes []byte, intent suisigner.Intent) (suisigner.Signature, error) {
+	if intent.Scope.TransactionData == nil {
+		return suisigner.Signature{}, violationf(RuleIntent, "only the TransactionData intent can be signed")
+	}
+	spent, err := g.evaluate(ctx, txnBytes)
+	if err != nil {
+		return suisigner.Signature{}, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// the window may be filled by a concurrent signing since the evaluation
+	now := g.Now()
+	if err := g.checkSpendingLimits(now, spent); err != nil {
+		return suisigner.Signature{}, err
+	}
+	signature, err := g.signer.SignTransactionBlock(txnBytes, intent)
+	if err != nil {
+		return suisigner.Signature{}, err
+	}
+	for coinType, amount := range spent {
+		g.spends = append(g.spends, spend{at: now, coinType: coinType, amount: amount})
+	}
+	return signature, nil
+}
+
+// Check evaluates the policy against the transaction without signing it
+func (g *Guard) Check(ctx context.Context, txnBytes []byte) error {
+	spent, err := g.evaluate(ctx, txnBytes)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.checkSpendingLimits(g.Now(), spent)
+}
+
+// evaluate checks the static rules and the dry run, and returns the amounts spent by the signer by coin type
+func (g *Guard) evaluate(ctx context.Context, txnBytes []byte) (map[string]*big.Int, error) {
+	var txData suiptb.TransactionData
+	if _, err := bcs.Unmarshal(txnBytes, &txData); err != nil {
+		return nil, violationf(RuleTransaction, "can't decode transaction: %s", err)
+	}
+	if err := g.checkTransaction(&txData); err != nil {
+		return nil, err
+	}
+
+	if !g.policy.RequireDryRun && len(g.policy.SpendingLimits) == 0 {
+		return nil, nil
+	}
+	if g.client == nil {
+		return nil, violationf(RuleDryRun, "a client is required to dry run the transaction")
+	}
+	resp, err := g.client.DryRunTransaction(ctx, txnBytes)
+	if err != nil {
+		return nil, fmt.Errorf("can't dry run transaction: %w", err)
+	}
+	if resp.Effects.Data.V1 == nil {
+		return nil, fmt.Errorf("dry run returned no effects")
+	}
+	if !resp.Effects.Data.IsSuccess() {
+		return nil, violationf(RuleDryRun, "transaction would fail: %s", resp.Effects.Data.V1.Status.Error)
+	}
+	return g.checkBalanceChanges(resp.BalanceChanges)
+}
+
+func (g *Guard) checkTransaction(txData *suiptb.TransactionData) error {
+	if txData.V1 == nil || txData.V1.Kind.ProgrammableTransaction == nil {
+		return violationf(RuleTransaction, "only programmable transactions are supported")
+	}
+	v1 := txData.V1
+	address := g.signer.GetAddress()
+	if v1.Sender != *address && (v1.GasData.Owner == nil || *v1.GasData.Owner != *address) {
+		return violationf(RuleSender, "signer %s is neither the sender nor the gas owner", address)
+	}
+	if g.policy.MaxGasBudget != 0 && v1.GasData.Budget > g.policy.MaxGasBudget {
+		return violationf(RuleGasBudget, "gas budget %d exceeds %d", v1.GasData.Budget, g.policy.MaxGasBudget)
+	}
+
+	pt := v1.Kind.ProgrammableTransaction
+	for i, command := range pt.Commands {
+		switch {
+		case command.MoveCall != nil:
+			if err := g.checkMoveCall(command.MoveCall); err != nil {
+				return err
+			}
+			if err := g.checkMoveCallRecipient(pt, i, command.MoveCall); err != nil {
+				return err
+			}
+		case command.Publish != nil, command.Upgrade != nil:
+			if !g.policy.AllowPublish {
+				return violationf(RulePublish, "command %d publishes or upgrades a package", i)
+			}
+		case command.TransferObjects != nil:
+			recipient, err := pureAddress(pt, command.TransferObjects.Address)
+			if err != nil {
+				if g.policy.AllowedRecipients == nil {
+					continue
+				}
+				return violationf(RuleRecipient, "command %d: %s", i, err)
+			}
+			if !g.policy.isRecipientAllowed(address, recipient) {
+				return violationf(RuleRecipient, "command %d transfers to %s, which is not allowed", i, recipient)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Guard) checkMoveCall(moveCall *suiptb.ProgrammableMoveCall) error {
+	if g.policy.AllowedMoveCalls == nil {
+		return nil
+	}
+	for _, rule := range g.policy.AllowedMoveCalls {
+		if rule.matches(moveCall.Package, moveCall.Module, moveCall.Function) {
+			return nil
+		}
+	}
+	return violationf(RuleMoveCall, "%s::%s::%s is not allowed", moveCall.Package, moveCall.Module, moveCall.Function)
+}
+
+func (g *Guard) checkBalanceChanges(changes []suiclient.BalanceChange) (map[string]*big.Int, error) {
+	address := g.signer.GetAddress()
+	spent := map[string]*big.Int{}
+	for _, change := range changes {
+		amount, ok := new(big.Int).SetString(change.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance change amount: %s", change.Amount)
+		}
+		coinType := normalizeCoinType(change.CoinType)
+		owner := balanceChangeOwner(change.Owner)
+		switch {
+		case owner != nil && *owner == *address:
+			if amount.Sign() < 0 {
+				if _, ok := spent[coinType]; !ok {
+					spent[coinType] = new(big.Int)
+				}
+				spent[coinType].Sub(spent[coinType], amount)
+			}
+		case amount.Sign() > 0 && g.policy.AllowedRecipients != nil:
+			// with an allowlist, the coins received by a shared object, e.g. a pool, are rejected too
+			if owner == nil || !g.policy.isRecipientAllowed(address, owner) {
+				return nil, violationf(RuleRecipient, "%s of %s would be received by %s, which is not allowed", amount, coinType, ownerString(owner))
+			}
+		}
+	}
+	return spent, nil
+}
+
+// checkSpendingLimits must be called with the mutex held
+func (g *Guard) checkSpendingLimits(now time.Time, spent map[string]*big.Int) error {
+	for _, limit := range g.policy.SpendingLimits {
+		coinType := normalizeCoinType(limit.CoinType)
+		amount, ok := spent[coinType]
+		if !ok {
+			continue
+		}
+		total := new(big.Int).Set(amount)
+		if limit.Window > 0 {
+			for _, s := range g.spends {
+				if s.coinType == coinType && now.Sub(s.at) < limit.Window {
+					total.Add(total, s.amount)
+				}
+			}
+		}
+		if total.Cmp(new(big.Int).SetUint64(limit.Amount)) > 0 {
+			if limit.Window > 0 {
+				return violationf(RuleSpendingLimit, "spending %s of %s would exceed %d within %s", total, coinType, limit.Amount, limit.Window)
+			}
+			return violationf(RuleSpendingLimit, "spending %s of %s exceeds %d", total, coinType, limit.Amount)
+		}
+	}
+	g.pruneSpends(now)
+	return nil
+}
+
+// pruneSpends drops the spends older than the longest window
+func (g *Guard) pruneSpends(now time.Time) {
+	var longest time.Duration
+	for _, limit := range g.policy.SpendingLimits {
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+	kept := g.spends[:0]
+	for _, s := range g.spends {
+		if now.Sub(s.at) < longest {
+			kept = append(kept, s)
+		}
+	}
+	g.spends = kept
+}
+
+// frameworkTransfers are the functions of the Sui framework which send objects to an address argument,
+// by `module::function` and the index of the address
+var frameworkTransfers = map[string]int{
+	"transfer::public_transfer":  1,
+	"transfer::transfer":         1,
+	"pay::split_and_transfer":    2,
+	"pay::join_vec_and_transfer": 1,
+	"sui::transfer":              1,
+	"coin::mint_and_transfer":    2,
+}
+
+// checkMoveCallRecipient checks the recipient of a Move call to a transfer function of the Sui framework
+func (g *Guard) checkMoveCallRecipient(pt *suiptb.ProgrammableTransaction, i int, moveCall *suiptb.ProgrammableMoveCall) error {
+	if g.policy.AllowedRecipients == nil || moveCall.Package == nil || *moveCall.Package != *sui.SuiPackageIdSuiFramework {
+		return nil
+	}
+	idx, ok := frameworkTransfers[fmt.Sprintf("%s::%s", moveCall.Module, moveCall.Function)]
+	if !ok {
+		return nil
+	}
+	if idx >= len(moveCall.Arguments) {
+		return violationf(RuleRecipient, "command %d: missing recipient", i)
+	}
+	recipient, err := pureAddress(pt, moveCall.Arguments[idx])
+	if err != nil {
+		return violationf(RuleRecipient, "command %d: %s", i, err)
+	}
+	if !g.policy.isRecipientAllowed(g.signer.GetAddress(), recipient) {
+		return violationf(RuleRecipient, "command %d transfers to %s, which is not allowed", i, recipient)
+	}
+	return nil
+}
+
+// pureAddress resolves the recipient argument of a transfer, which must be a pure input
+func pureAddress(pt *suiptb.ProgrammableTransaction, arg suiptb.Argument) (*sui.Address, error) {
+	if arg.Input == nil || int(*arg.Input) >= len(pt.Inputs) {
+		return nil, fmt.Errorf("recipient is not a pure input")
+	}
+	input := pt.Inputs[*arg.Input]
+	if input.Pure == nil || len(*input.Pure) != sui.AddressLen {
+		return nil, fmt.Errorf("recipient is not a pure address")
+	}
+	var address sui.Address
+	copy(address[:], *input.Pure)
+	return &address, nil
+}
+
+func balanceChangeOwner(owner suiclient.ObjectOwner) *sui.Address {
+	if owner.ObjectOwnerInternal == nil {
+		return nil
+	}
+	switch {
+	case owner.AddressOwner != nil:
+		return owner.AddressOwner
+	case owner.ObjectOwner != nil:
+		return owner.ObjectOwner
+	default:
+		return nil
+	}
+}
+
+func ownerString(owner *sui.Address) string {
+	if owner == nil {
+		return "a non address owner"
+	}
+	return owner.String()
+}
+
+// normalizeCoinType expands the short address of the coin type, e.g. `0x2::sui::SUI`
+func normalizeCoinType(coinType string) string {
+	addr, rest, ok := strings.Cut(coinType, "::")
+	if !ok {
+		return coinType
+	}
+	address, err := sui.AddressFromHex(addr)
+	if err != nil {
+		return coinType
+	}
+	return address.String() + "::" + rest
+}
@@ -0,0 +1,281 @@
+package policy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/policy"
+)
+
+var (
+	allowedPackage   = sui.MustPackageIdFromHex("0x1234")
+	allowedRecipient = sui.MustAddressFromHex("0xa11ce")
+	unknownRecipient = sui.MustAddressFromHex("0xbad")
+	gasCoin          = &sui.ObjectRef{
+		ObjectId: sui.MustObjectIdFromHex("0x5678"),
+		Version:  1,
+		Digest:   sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"),
+	}
+)
+
+// fakeDryRunner returns the balance changes of the sender spending `spent` SUI
+type fakeDryRunner struct {
+	sender   *sui.Address
+	spent    string
+	receiver *sui.Address
+	// the coins are received by a shared object instead of the receiver
+	shared  bool
+	failure string
+}
+
+func (f *fakeDryRunner) DryRunTransaction(ctx context.Context, txDataBytes sui.Base64Data) (*suiclient.DryRunTransactionBlockResponse, error) {
+	status := suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusSuccess}
+	if f.failure != "" {
+		status = suiclient.ExecutionStatus{Status: suiclient.ExecutionStatusFailure, Error: f.failure}
+	}
+	resp := &suiclient.DryRunTransactionBlockResponse{
+		Effects: suiclient.WrapperTaggedJson[suiclient.SuiTransactionBlockEffects]{
+			Data: suiclient.SuiTransactionBlockEffects{
+				V1: &suiclient.SuiTransactionBlockEffectsV1{Status: status},
+			},
+		},
+		BalanceChanges: []suiclient.BalanceChange{{
+			Owner:    suiclient.ObjectOwner{ObjectOwnerInternal: &suiclient.ObjectOwnerInternal{AddressOwner: f.sender}},
+			CoinType: sui.SuiCoinType,
+			Amount:   "-" + f.spent,
+		}},
+	}
+	if f.receiver != nil {
+		resp.BalanceChanges = append(resp.BalanceChanges, suiclient.BalanceChange{
+			Owner:    suiclient.ObjectOwner{ObjectOwnerInternal: &suiclient.ObjectOwnerInternal{AddressOwner: f.receiver}},
+			CoinType: sui.SuiCoinType,
+			Amount:   f.spent,
+		})
+	}
+	if f.shared {
+		var owner suiclient.ObjectOwner
+		if err := json.Unmarshal([]byte(`{"Shared":{"initial_shared_version":1}}`), &owner); err != nil {
+			return nil, err
+		}
+		resp.BalanceChanges = append(resp.BalanceChanges, suiclient.BalanceChange{
+			Owner:    owner,
+			CoinType: sui.SuiCoinType,
+			Amount:   f.spent,
+		})
+	}
+	return resp, nil
+}
+
+func newSigner(t *testing.T) *suisigner.Signer {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagDefault)
+	require.NoError(t, err)
+	return signer
+}
+
+func buildTx(t *testing.T, sender *sui.Address, gasBudget uint64, build func(ptb *suiptb.ProgrammableTransactionBuilder)) []byte {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	build(ptb)
+	tx := suiptb.NewTransactionData(sender, ptb.Finish(), []*sui.ObjectRef{gasCoin}, gasBudget, 1000)
+	txBytes, err := bcs.Marshal(tx)
+	require.NoError(t, err)
+	return txBytes
+}
+
+func requireViolation(t *testing.T, err error, rule string) {
+	require.ErrorIs(t, err, policy.ErrPolicyViolation)
+	var violation *policy.Violation
+	require.True(t, errors.As(err, &violation))
+	require.Equal(t, rule, violation.Rule)
+}
+
+func TestGuardStaticRules(t *testing.T) {
+	signer := newSigner(t)
+	guard := policy.NewGuard(signer, &policy.Policy{
+		AllowedMoveCalls:  []policy.MoveCallRule{{Package: allowedPackage, Module: "game"}},
+		AllowedRecipients: []*sui.Address{allowedRecipient},
+		MaxGasBudget:      10_000_000,
+	}, nil)
+	require.Equal(t, signer.Address, guard.GetAddress())
+
+	moveCall := func(pkg *sui.PackageId, module sui.Identifier) []byte {
+		return buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+			ptb.ProgrammableMoveCall(pkg, module, "play", nil, []suiptb.Argument{ptb.MustPure(uint64(1))})
+		})
+	}
+	signature, err := guard.SignTransactionBlock(moveCall(allowedPackage, "game"), suisigner.DefaultIntent())
+	require.NoError(t, err)
+	_, err = suisigner.VerifyTransactionSignature(&signature, moveCall(allowedPackage, "game"))
+	require.NoError(t, err)
+
+	_, err = guard.SignTransactionBlock(moveCall(allowedPackage, "bank"), suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleMoveCall)
+	_, err = guard.SignTransactionBlock(moveCall(sui.MustPackageIdFromHex("0x9999"), "game"), suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleMoveCall)
+
+	transfer := func(recipient *sui.Address) []byte {
+		return buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+			require.NoError(t, ptb.TransferObject(recipient, gasCoin))
+		})
+	}
+	_, err = guard.SignTransactionBlock(transfer(allowedRecipient), suisigner.DefaultIntent())
+	require.NoError(t, err)
+	_, err = guard.SignTransactionBlock(transfer(signer.Address), suisigner.DefaultIntent())
+	require.NoError(t, err)
+	_, err = guard.SignTransactionBlock(transfer(unknownRecipient), suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleRecipient)
+
+	expensive := buildTx(t, signer.Address, 20_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		require.NoError(t, ptb.TransferObject(allowedRecipient, gasCoin))
+	})
+	_, err = guard.SignTransactionBlock(expensive, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleGasBudget)
+
+	_, err = guard.SignTransactionBlock(transfer(allowedRecipient), suisigner.PersonalMessageIntent())
+	requireViolation(t, err, policy.RuleIntent)
+
+	// another sender
+	other := buildTx(t, unknownRecipient, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		require.NoError(t, ptb.TransferObject(allowedRecipient, gasCoin))
+	})
+	_, err = guard.SignTransactionBlock(other, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleSender)
+
+	_, err = guard.SignTransactionBlock([]byte{1, 2, 3}, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleTransaction)
+}
+
+func TestGuardMoveCallRecipients(t *testing.T) {
+	signer := newSigner(t)
+	guard := policy.NewGuard(signer, &policy.Policy{AllowedRecipients: []*sui.Address{allowedRecipient}}, nil)
+
+	publicTransfer := func(recipient *sui.Address) []byte {
+		return buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+			ptb.ProgrammableMoveCall(sui.SuiPackageIdSuiFramework, "transfer", "public_transfer",
+				[]sui.TypeTag{*sui.MustNewTypeTag("0x2::coin::Coin<0x2::sui::SUI>")},
+				[]suiptb.Argument{ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: gasCoin}), ptb.MustPure(recipient)})
+		})
+	}
+	_, err := guard.SignTransactionBlock(publicTransfer(unknownRecipient), suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleRecipient)
+	_, err = guard.SignTransactionBlock(publicTransfer(allowedRecipient), suisigner.DefaultIntent())
+	require.NoError(t, err)
+
+	splitAndTransfer := buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		ptb.ProgrammableMoveCall(sui.SuiPackageIdSuiFramework, "pay", "split_and_transfer",
+			[]sui.TypeTag{*sui.MustNewTypeTag("0x2::sui::SUI")},
+			[]suiptb.Argument{ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: gasCoin}), ptb.MustPure(uint64(100)), ptb.MustPure(unknownRecipient)})
+	})
+	_, err = guard.SignTransactionBlock(splitAndTransfer, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleRecipient)
+
+	// a recipient which isn't known before the execution
+	fromResult := buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		ptb.ProgrammableMoveCall(allowedPackage, "registry", "owner", nil, nil)
+		ptb.ProgrammableMoveCall(sui.SuiPackageIdSuiFramework, "transfer", "public_transfer",
+			[]sui.TypeTag{*sui.MustNewTypeTag("0x2::coin::Coin<0x2::sui::SUI>")},
+			[]suiptb.Argument{ptb.MustObj(suiptb.ObjectArg{ImmOrOwnedObject: gasCoin}), {Result: new(uint16)}})
+	})
+	_, err = guard.SignTransactionBlock(fromResult, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleRecipient)
+}
+
+func TestGuardSpendingLimit(t *testing.T) {
+	signer := newSigner(t)
+	dryRunner := &fakeDryRunner{sender: signer.Address, spent: "400"}
+	guard := policy.NewGuard(signer, &policy.Policy{
+		SpendingLimits: []policy.SpendingLimit{{
+			CoinType: "0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI",
+			Amount:   1000,
+			Window:   time.Hour,
+		}},
+	}, dryRunner)
+	now := time.Unix(1700000000, 0)
+	guard.Now = func() time.Time { return now }
+
+	txBytes := buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		require.NoError(t, ptb.TransferObject(signer.Address, gasCoin))
+	})
+	_, err := guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	// 1200 within the hour
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleSpendingLimit)
+	requireViolation(t, guard.Check(context.Background(), txBytes), policy.RuleSpendingLimit)
+
+	// the window slides
+	now = now.Add(time.Hour)
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+
+	// a single transaction over the limit
+	dryRunner.spent = "1001"
+	now = now.Add(2 * time.Hour)
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleSpendingLimit)
+
+	// the failing transactions are rejected
+	dryRunner.spent = "1"
+	dryRunner.failure = "InsufficientGas"
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleDryRun)
+
+	// the limits can't be evaluated without a client
+	noClient := policy.NewGuard(signer, &policy.Policy{SpendingLimits: []policy.SpendingLimit{{CoinType: sui.SuiCoinType, Amount: 1}}}, nil)
+	_, err = noClient.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleDryRun)
+}
+
+func TestGuardDryRunRecipients(t *testing.T) {
+	signer := newSigner(t)
+	dryRunner := &fakeDryRunner{sender: signer.Address, spent: "10", receiver: unknownRecipient}
+	guard := policy.NewGuard(signer, &policy.Policy{
+		AllowedRecipients: []*sui.Address{allowedRecipient},
+		RequireDryRun:     true,
+	}, dryRunner)
+
+	// the coins received by an unknown address are caught by the dry run, even through a move call
+	txBytes := buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		ptb.ProgrammableMoveCall(allowedPackage, "bank", "pay", nil, []suiptb.Argument{ptb.MustPure(uint64(1))})
+	})
+	_, err := guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleRecipient)
+
+	dryRunner.receiver = allowedRecipient
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+}
+
+func TestGuardDryRunSharedRecipient(t *testing.T) {
+	signer := newSigner(t)
+	dryRunner := &fakeDryRunner{sender: signer.Address, spent: "10", shared: true}
+	txBytes := buildTx(t, signer.Address, 1_000_000, func(ptb *suiptb.ProgrammableTransactionBuilder) {
+		ptb.ProgrammableMoveCall(allowedPackage, "pool", "deposit", nil, []suiptb.Argument{ptb.MustPure(uint64(1))})
+	})
+
+	// the deposit into a shared pool is only limited by the spending limit
+	guard := policy.NewGuard(signer, &policy.Policy{
+		SpendingLimits: []policy.SpendingLimit{{CoinType: sui.SuiCoinType, Amount: 100}},
+	}, dryRunner)
+	_, err := guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+
+	// the shared pool isn't an allowed recipient
+	guard = policy.NewGuard(signer, &policy.Policy{
+		AllowedRecipients: []*sui.Address{allowedRecipient},
+		RequireDryRun:     true,
+	}, dryRunner)
+	_, err = guard.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	requireViolation(t, err, policy.RuleRecipient)
+}
@@ -0,0 +1,99 @@
+// Package policy guards a signer with declarative rules, so a hot wallet refuses the transactions
+// that it isn't supposed to sign.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pattonkan/sui-go/sui"
+)
+
+var ErrPolicyViolation = errors.New("policy violation")
+
+// the rules which can be violated
+const (
+	RuleIntent        = "intent"
+	RuleSender        = "sender"
+	RuleTransaction   = "transaction"
+	RuleMoveCall      = "move_call"
+	RulePublish       = "publish"
+	RuleRecipient     = "recipient"
+	RuleGasBudget     = "gas_budget"
+	RuleSpendingLimit = "spending_limit"
+	RuleDryRun        = "dry_run"
+)
+
+// Violation is the error returned when a transaction breaks a rule of the policy
+type Violation struct {
+	Rule   string
+	Reason string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("policy violation of %s: %s", v.Rule, v.Reason)
+}
+
+func (v *Violation) Unwrap() error {
+	return ErrPolicyViolation
+}
+
+func violationf(rule string, format string, args ...any) *Violation {
+	return &Violation{
+		Rule:   rule,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// MoveCallRule allows the calls to a package. Empty Module or Function matches any.
+type MoveCallRule struct {
+	Package  *sui.PackageId
+	Module   sui.Identifier
+	Function sui.Identifier
+}
+
+func (r MoveCallRule) matches(pkg *sui.PackageId, module sui.Identifier, function sui.Identifier) bool {
+	if r.Package == nil || pkg == nil || *r.Package != *pkg {
+		return false
+	}
+	return (r.Module == "" || r.Module == module) && (r.Function == "" || r.Function == function)
+}
+
+// SpendingLimit caps the amount of a coin the signer can spend within a sliding window.
+// A zero Window caps every single transaction. The spending of SUI includes the gas fee.
+type SpendingLimit struct {
+	CoinType sui.ObjectType
+	Amount   uint64
+	Window   time.Duration
+}
+
+// Policy is the set of rules. A nil allowlist disables the rule, and an empty one allows nothing.
+type Policy struct {
+	// the Move functions the transactions may call
+	AllowedMoveCalls []MoveCallRule
+	// the addresses that may receive objects and coins, besides the signer itself. The recipients of
+	// TransferObjects and of the transfer functions of the Sui framework, e.g. `0x2::transfer::public_transfer`,
+	// are checked without a dry run, and the coins sent by other Move calls are caught by a dry run.
+	AllowedRecipients []*sui.Address
+	// whether the transactions may publish or upgrade packages
+	AllowPublish bool
+	// the max gas budget, 0 means unlimited
+	MaxGasBudget uint64
+	// the spending caps, which are evaluated with the balance changes of a dry run
+	SpendingLimits []SpendingLimit
+	// dry-run every transaction, and reject the ones that would fail
+	RequireDryRun bool
+}
+
+func (p *Policy) isRecipientAllowed(signer *sui.Address, recipient *sui.Address) bool {
+	if p.AllowedRecipients == nil || *recipient == *signer {
+		return true
+	}
+	for _, allowed := range p.AllowedRecipients {
+		if *allowed == *recipient {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// Client talks to a signing Server
+type Client struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func NewClient(url string, token string) *Client {
+	return &Client{
+		url:        strings.TrimRight(url, "/"),
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. for the TLS settings
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+// Addresses lists the addresses the server signs for
+func (c *Client) Addresses(ctx context.Context) ([]*sui.Address, error) {
+	var resp AddressesResponse
+	if err := c.call(ctx, http.MethodGet, PathAddresses, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Addresses, nil
+}
+
+// Signer returns the remote signer of the address
+func (c *Client) Signer(address *sui.Address) *Signer {
+	return &Signer{client: c, address: address}
+}
+
+func (c *Client) sign(ctx context.Context, req *SignRequest) (*suisigner.Signature, error) {
+	var resp SignResponse
+	if err := c.call(ctx, http.MethodPost, PathSign, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Signature == nil {
+		return nil, fmt.Errorf("empty signature from the server")
+	}
+	return resp.Signature, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, path string, reqBody any, respBody any) error {
+	var body bytes.Buffer
+	if reqBody != nil {
+		if err := json.NewEncoder(&body).Encode(reqBody); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			return ErrUnauthorized
+		case http.StatusForbidden:
+			return fmt.Errorf("%w: %s", ErrRejected, errResp.Error)
+		default:
+			return fmt.Errorf("signing server returned %d: %s", resp.StatusCode, errResp.Error)
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(respBody)
+}
+
+// Signer signs with a key held by a signing server, and can be used in place of a local suisigner.Signer
+type Signer struct {
+	client  *Client
+	address *sui.Address
+}
+
+var _ suisigner.TransactionSigner = (*Signer)(nil)
+
+func (s *Signer) GetAddress() *sui.Address {
+	return s.address
+}
+
+func (s *Signer) SignTransactionBlock(txnBytes []byte, intent suisigner.Intent) (suisigner.Signature, error) {
+	return s.SignTransactionBlockWithContext(context.Background(), txnBytes, intent)
+}
+
+// SignTransactionBlockWithContext asks the server to sign the transaction, and verifies the returned
+// signature is of the expected address
+func (s *Signer) SignTransactionBlockWithContext(ctx context.Context, txnBytes []byte, intent suisigner.Intent) (suisigner.Signature, error) {
+	scope, err := scopeOf(intent)
+	if err != nil {
+		return suisigner.Signature{}, err
+	}
+	if scope != ScopeTransactionData {
+		return suisigner.Signature{}, fmt.Errorf("transaction must be signed under the TransactionData intent")
+	}
+	signature, err := s.client.sign(ctx, &SignRequest{Address: s.address, Scope: scope, Message: txnBytes})
+	if err != nil {
+		return suisigner.Signature{}, err
+	}
+	if err := s.verify(signature, intent, txnBytes); err != nil {
+		return suisigner.Signature{}, err
+	}
+	return *signature, nil
+}
+
+// SignPersonalMessage asks the server to sign the personal message
+func (s *Signer) SignPersonalMessage(ctx context.Context, message []byte) (suisigner.Signature, error) {
+	signature, err := s.client.sign(ctx, &SignRequest{Address: s.address, Scope: ScopePersonalMessage, Message: message})
+	if err != nil {
+		return suisigner.Signature{}, err
+	}
+	bcsMessage, err := bcs.Marshal(message)
+	if err != nil {
+		return suisigner.Signature{}, err
+	}
+	if err := s.verify(signature, suisigner.PersonalMessageIntent(), bcsMessage); err != nil {
+		return suisigner.Signature{}, err
+	}
+	return *signature, nil
+}
+
+func (s *Signer) verify(signature *suisigner.Signature, intent suisigner.Intent, message []byte) error {
+	address, err := suisigner.VerifySignature(signature, intent, message)
+	if err != nil {
+		return fmt.Errorf("invalid signature from the server: %w", err)
+	}
+	if *address != *s.address {
+		return fmt.Errorf("signature from the server is of %s, not %s", address, s.address)
+	}
+	return nil
+}
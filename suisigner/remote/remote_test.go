@@ -0,0 +1,143 @@
+package remote_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/suiclient"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/policy"
+	"github.com/pattonkan/sui-go/suisigner/remote"
+)
+
+var (
+	allowedRecipient = sui.MustAddressFromHex("0xa11ce")
+	unknownRecipient = sui.MustAddressFromHex("0xbad")
+	gasCoin          = &sui.ObjectRef{
+		ObjectId: sui.MustObjectIdFromHex("0x5678"),
+		Version:  1,
+		Digest:   sui.MustNewDigest("4vJ9JU1bJJE96FWSJKvHsmmFADCg4gpZQff4P3bkLKi"),
+	}
+)
+
+func newTestServer(t *testing.T, allowPersonalMessages bool) (*suisigner.Signer, *httptest.Server, *bytes.Buffer) {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagDefault)
+	require.NoError(t, err)
+	var auditLog bytes.Buffer
+	server, err := remote.NewServer(&remote.ServerConfig{
+		Signers: []*suisigner.Signer{signer},
+		Tokens:  map[string]string{"backend": "secret-token"},
+		Policy: &policy.Policy{
+			AllowedRecipients: []*sui.Address{allowedRecipient},
+		},
+		AllowPersonalMessages: allowPersonalMessages,
+		AuditLog:              &auditLog,
+	})
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return signer, httpServer, &auditLog
+}
+
+func transferTx(t *testing.T, sender *sui.Address, recipient *sui.Address) []byte {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	amount := uint64(100)
+	require.NoError(t, ptb.TransferSui(recipient, &amount))
+	tx := suiptb.NewTransactionData(sender, ptb.Finish(), []*sui.ObjectRef{gasCoin}, suiclient.DefaultGasBudget, suiclient.DefaultGasPrice)
+	txBytes, err := bcs.Marshal(tx)
+	require.NoError(t, err)
+	return txBytes
+}
+
+func TestAddresses(t *testing.T) {
+	signer, httpServer, _ := newTestServer(t, false)
+	client := remote.NewClient(httpServer.URL, "secret-token")
+	addresses, err := client.Addresses(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []*sui.Address{signer.Address}, addresses)
+}
+
+func TestSignTransactionBlock(t *testing.T) {
+	signer, httpServer, auditLog := newTestServer(t, false)
+	remoteSigner := remote.NewClient(httpServer.URL, "secret-token").Signer(signer.Address)
+	var _ suisigner.TransactionSigner = remoteSigner
+
+	txBytes := transferTx(t, signer.Address, allowedRecipient)
+	signature, err := remoteSigner.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	localSignature, err := signer.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+	require.Equal(t, localSignature, signature)
+
+	address, err := suisigner.VerifyTransactionSignature(&signature, txBytes)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, address)
+
+	var record remote.AuditRecord
+	require.NoError(t, json.Unmarshal(auditLog.Bytes(), &record))
+	require.Equal(t, "backend", record.Caller)
+	require.Equal(t, signer.Address, record.Address)
+	require.Equal(t, remote.ScopeTransactionData, record.Scope)
+	require.True(t, record.Signed)
+	require.Len(t, record.Digest, 64)
+}
+
+func TestSignRejected(t *testing.T) {
+	signer, httpServer, auditLog := newTestServer(t, false)
+
+	t.Run("bad token", func(t *testing.T) {
+		remoteSigner := remote.NewClient(httpServer.URL, "wrong-token").Signer(signer.Address)
+		_, err := remoteSigner.SignTransactionBlock(transferTx(t, signer.Address, allowedRecipient), suisigner.DefaultIntent())
+		require.ErrorIs(t, err, remote.ErrUnauthorized)
+	})
+
+	t.Run("policy violation", func(t *testing.T) {
+		auditLog.Reset()
+		remoteSigner := remote.NewClient(httpServer.URL, "secret-token").Signer(signer.Address)
+		_, err := remoteSigner.SignTransactionBlock(transferTx(t, signer.Address, unknownRecipient), suisigner.DefaultIntent())
+		require.ErrorIs(t, err, remote.ErrRejected)
+		require.Contains(t, err.Error(), policy.RuleRecipient)
+
+		var record remote.AuditRecord
+		require.NoError(t, json.Unmarshal(auditLog.Bytes(), &record))
+		require.False(t, record.Signed)
+		require.Contains(t, record.Reason, policy.RuleRecipient)
+	})
+
+	t.Run("unknown address", func(t *testing.T) {
+		other := sui.MustAddressFromHex("0x1")
+		remoteSigner := remote.NewClient(httpServer.URL, "secret-token").Signer(other)
+		_, err := remoteSigner.SignTransactionBlock(transferTx(t, other, allowedRecipient), suisigner.DefaultIntent())
+		require.Error(t, err)
+		require.False(t, errors.Is(err, remote.ErrRejected))
+	})
+
+	t.Run("personal message not allowed", func(t *testing.T) {
+		remoteSigner := remote.NewClient(httpServer.URL, "secret-token").Signer(signer.Address)
+		_, err := remoteSigner.SignPersonalMessage(context.Background(), []byte("hello"))
+		require.ErrorIs(t, err, remote.ErrRejected)
+	})
+}
+
+func TestSignPersonalMessage(t *testing.T) {
+	signer, httpServer, auditLog := newTestServer(t, true)
+	remoteSigner := remote.NewClient(httpServer.URL, "secret-token").Signer(signer.Address)
+
+	message := []byte("sign in with sui")
+	signature, err := remoteSigner.SignPersonalMessage(context.Background(), message)
+	require.NoError(t, err)
+	address, err := suisigner.VerifyPersonalMessageSignature(&signature, message)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, address)
+	require.True(t, strings.Contains(auditLog.String(), remote.ScopePersonalMessage))
+}
@@ -0,0 +1,229 @@
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/policy"
+	"golang.org/x/crypto/blake2b"
+)
+
+// the max size of a request body, which is above the max transaction size of Sui
+const maxRequestSize = 1 << 20
+
+type ServerConfig struct {
+	// the signers to serve
+	Signers []*suisigner.Signer
+	// the bearer tokens of the callers, by caller name
+	Tokens map[string]string
+	// the policy of the transactions, which is optional
+	Policy *policy.Policy
+	// the client to dry-run the transactions, which is required by some of the policy rules
+	DryRunner policy.DryRunner
+	// whether the callers may sign personal messages
+	AllowPersonalMessages bool
+	// where the audit log is written to in JSON lines, which is optional
+	AuditLog io.Writer
+}
+
+// Server signs the intent messages for the authenticated callers
+type Server struct {
+	signers               map[sui.Address]*suisigner.Signer
+	guards                map[sui.Address]*policy.Guard
+	tokens                map[[sha256.Size]byte]string
+	allowPersonalMessages bool
+
+	auditMu  sync.Mutex
+	auditLog io.Writer
+	mux      *http.ServeMux
+}
+
+// AuditRecord is a line of the audit log
+type AuditRecord struct {
+	Time    time.Time    `json:"time"`
+	Caller  string       `json:"caller"`
+	Address *sui.Address `json:"address,omitempty"`
+	Scope   string       `json:"scope,omitempty"`
+	// the hex of `blake2b(intent || message)`, which is the transaction digest for transactions
+	Digest string `json:"digest,omitempty"`
+	Signed bool   `json:"signed"`
+	Reason string `json:"reason,omitempty"`
+}
+
+var _ http.Handler = (*Server)(nil)
+
+func NewServer(config *ServerConfig) (*Server, error) {
+	if len(config.Tokens) == 0 {
+		return nil, errors.New("at least one caller token is required")
+	}
+	s := &Server{
+		signers:               make(map[sui.Address]*suisigner.Signer),
+		guards:                make(map[sui.Address]*policy.Guard),
+		tokens:                make(map[[sha256.Size]byte]string),
+		allowPersonalMessages: config.AllowPersonalMessages,
+		auditLog:              config.AuditLog,
+		mux:                   http.NewServeMux(),
+	}
+	for caller, token := range config.Tokens {
+		if token == "" {
+			return nil, fmt.Errorf("empty token of caller %s", caller)
+		}
+		s.tokens[sha256.Sum256([]byte(token))] = caller
+	}
+	p := config.Policy
+	if p == nil {
+		p = &policy.Policy{}
+	}
+	for _, signer := range config.Signers {
+		s.signers[*signer.Address] = signer
+		s.guards[*signer.Address] = policy.NewGuard(signer, p, config.DryRunner)
+	}
+	s.mux.HandleFunc(PathAddresses, s.handleAddresses)
+	s.mux.HandleFunc(PathSign, s.handleSign)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// authenticate returns the caller of the bearer token
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	// the tokens are looked up by their hashes, and compared in constant time
+	hash := sha256.Sum256([]byte(token))
+	for known, caller := range s.tokens {
+		if subtle.ConstantTimeCompare(known[:], hash[:]) == 1 {
+			return caller, true
+		}
+	}
+	return "", false
+}
+
+func (s *Server) handleAddresses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if _, ok := s.authenticate(r); !ok {
+		writeError(w, http.StatusUnauthorized, ErrUnauthorized.Error())
+		return
+	}
+	resp := AddressesResponse{Addresses: []*sui.Address{}}
+	for _, signer := range s.signers {
+		resp.Addresses = append(resp.Addresses, signer.Address)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	caller, ok := s.authenticate(r)
+	if !ok {
+		s.audit(AuditRecord{Reason: ErrUnauthorized.Error()})
+		writeError(w, http.StatusUnauthorized, ErrUnauthorized.Error())
+		return
+	}
+	var req SignRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %s", err))
+		return
+	}
+	record := AuditRecord{Caller: caller, Address: req.Address, Scope: req.Scope}
+	if req.Address == nil {
+		writeError(w, http.StatusBadRequest, "missing address")
+		return
+	}
+	signer, ok := s.signers[*req.Address]
+	if !ok {
+		record.Reason = "unknown address"
+		s.audit(record)
+		writeError(w, http.StatusNotFound, record.Reason)
+		return
+	}
+
+	signature, digest, err := s.sign(r.Context(), signer, &req)
+	record.Digest = digest
+	if err != nil {
+		record.Reason = err.Error()
+		s.audit(record)
+		status := http.StatusBadRequest
+		if errors.Is(err, policy.ErrPolicyViolation) {
+			status = http.StatusForbidden
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+	record.Signed = true
+	s.audit(record)
+	writeJSON(w, http.StatusOK, SignResponse{Signature: &signature})
+}
+
+func (s *Server) sign(ctx context.Context, signer *suisigner.Signer, req *SignRequest) (suisigner.Signature, string, error) {
+	switch req.Scope {
+	case ScopeTransactionData:
+		intent := suisigner.DefaultIntent()
+		digest := blake2b.Sum256(suisigner.MessageWithIntent(intent, req.Message))
+		signature, err := s.guards[*signer.Address].SignTransactionBlockWithContext(ctx, req.Message, intent)
+		return signature, hex.EncodeToString(digest[:]), err
+	case ScopePersonalMessage:
+		bcsMessage, err := bcs.Marshal([]byte(req.Message))
+		if err != nil {
+			return suisigner.Signature{}, "", err
+		}
+		digest := blake2b.Sum256(suisigner.MessageWithIntent(suisigner.PersonalMessageIntent(), bcsMessage))
+		if !s.allowPersonalMessages {
+			return suisigner.Signature{}, hex.EncodeToString(digest[:]), &policy.Violation{
+				Rule:   policy.RuleIntent,
+				Reason: "personal messages are not allowed",
+			}
+		}
+		signature, err := signer.SignPersonalMessage(req.Message)
+		return signature, hex.EncodeToString(digest[:]), err
+	default:
+		return suisigner.Signature{}, "", fmt.Errorf("unsupported scope: %s", req.Scope)
+	}
+}
+
+func (s *Server) audit(record AuditRecord) {
+	if s.auditLog == nil {
+		return
+	}
+	record.Time = time.Now().UTC()
+	line, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	_, _ = s.auditLog.Write(append(line, '\n'))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg})
+}
@@ -0,0 +1,58 @@
+// Package remote runs the signers in a separate process. Server wraps the local signers behind an
+// authenticated HTTP API, and Signer is the matching client side TransactionSigner.
+package remote
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+const (
+	PathAddresses = "/v1/addresses"
+	PathSign      = "/v1/sign"
+)
+
+// the intent scopes which can be signed remotely
+const (
+	ScopeTransactionData = "TransactionData"
+	ScopePersonalMessage = "PersonalMessage"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRejected is returned when the server refuses to sign, e.g. for a policy violation
+	ErrRejected = errors.New("signing rejected")
+)
+
+type AddressesResponse struct {
+	Addresses []*sui.Address `json:"addresses"`
+}
+
+type SignRequest struct {
+	Address *sui.Address `json:"address"`
+	Scope   string       `json:"scope"`
+	// the BCS encoded TransactionData, or the raw personal message
+	Message sui.Base64Data `json:"message"`
+}
+
+type SignResponse struct {
+	Signature *suisigner.Signature `json:"signature"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func scopeOf(intent suisigner.Intent) (string, error) {
+	switch {
+	case intent.Scope.TransactionData != nil:
+		return ScopeTransactionData, nil
+	case intent.Scope.PersonalMessage != nil:
+		return ScopePersonalMessage, nil
+	default:
+		return "", fmt.Errorf("unsupported intent scope for remote signing")
+	}
+}
@@ -3,18 +3,28 @@ package suisigner
 import (
 	"bytes"
 	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"math/big"
+
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 )
 
 type Signature struct {
 	*Ed25519SuiSignature
 	*Secp256k1SuiSignature
 	*Secp256r1SuiSignature
+	*ZkLoginSuiSignature
+	*PasskeySuiSignature
 }
 
 const (
-	SizeEd25519SuiSignature = ed25519.PublicKeySize + ed25519.SignatureSize + 1
+	SizeEd25519SuiSignature   = ed25519.PublicKeySize + ed25519.SignatureSize + 1
+	SizeSecp256k1SuiSignature = PublicKeyLengthSecp256k1 + SignatureLengthSecp256k1 + 1
+	SizeSecp256r1SuiSignature = PublicKeyLengthSecp256r1 + SignatureLengthSecp256r1 + 1
 )
 
 type Secp256k1SuiSignature struct {
@@ -25,10 +35,90 @@ type Secp256r1SuiSignature struct {
 	Signature []byte //secp256k1.pubKey + Secp256k1Signature + 1
 }
 
+// ZkLoginSuiSignature is a serialized zkLogin authenticator, `flag || bcs(ZkLoginSignature)`.
+// See the package `suisigner/zklogin` to assemble one.
+type ZkLoginSuiSignature struct {
+	Signature []byte
+}
+
+// PasskeySuiSignature is a serialized passkey authenticator, `flag || bcs(PasskeyAuthenticator)`.
+// See the package `suisigner/passkey` to assemble one.
+type PasskeySuiSignature struct {
+	Signature []byte
+}
+
 type Ed25519SuiSignature struct {
 	Signature [SizeEd25519SuiSignature]byte
 }
 
+// NewSignatureFromBytes parses a serialized Sui signature, which is `flag || signature || pubkey`
+func NewSignatureFromBytes(signature []byte) (*Signature, error) {
+	if len(signature) == 0 {
+		return nil, errors.New("empty signature")
+	}
+	switch KeySchemeFlag(signature[0]) {
+	case KeySchemeFlagEd25519:
+		if len(signature) != SizeEd25519SuiSignature {
+			return nil, errors.New("invalid ed25519 signature")
+		}
+		var signatureBytes [SizeEd25519SuiSignature]byte
+		copy(signatureBytes[:], signature)
+		return &Signature{
+			Ed25519SuiSignature: &Ed25519SuiSignature{
+				Signature: signatureBytes,
+			},
+		}, nil
+	case KeySchemeFlagSecp256k1:
+		if len(signature) != SizeSecp256k1SuiSignature {
+			return nil, errors.New("invalid secp256k1 signature")
+		}
+		return &Signature{
+			Secp256k1SuiSignature: &Secp256k1SuiSignature{
+				Signature: bytes.Clone(signature),
+			},
+		}, nil
+	case KeySchemeFlagSecp256r1:
+		if len(signature) != SizeSecp256r1SuiSignature {
+			return nil, errors.New("invalid secp256r1 signature")
+		}
+		return &Signature{
+			Secp256r1SuiSignature: &Secp256r1SuiSignature{
+				Signature: bytes.Clone(signature),
+			},
+		}, nil
+	case KeySchemeFlagZkLoginAuthenticator:
+		if len(signature) == 1 {
+			return nil, errors.New("invalid zklogin signature")
+		}
+		return &Signature{
+			ZkLoginSuiSignature: &ZkLoginSuiSignature{
+				Signature: bytes.Clone(signature),
+			},
+		}, nil
+	case KeySchemeFlagPasskey:
+		if len(signature) == 1 {
+			return nil, errors.New("invalid passkey signature")
+		}
+		return &Signature{
+			PasskeySuiSignature: &PasskeySuiSignature{
+				Signature: bytes.Clone(signature),
+			},
+		}, nil
+	default:
+		return nil, errors.New("not supported signature")
+	}
+}
+
+// NewSignatureFromBase64 parses a serialized Sui signature in base64, which is the format
+// returned by wallets and accepted by the JSON RPC APIs
+func NewSignatureFromBase64(str string) (*Signature, error) {
+	signature, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignatureFromBytes(signature)
+}
+
 func (s Signature) Bytes() []byte {
 	switch {
 	case s.Ed25519SuiSignature != nil:
@@ -37,6 +127,48 @@ func (s Signature) Bytes() []byte {
 		return s.Secp256k1SuiSignature.Signature[:]
 	case s.Secp256r1SuiSignature != nil:
 		return s.Secp256r1SuiSignature.Signature[:]
+	case s.ZkLoginSuiSignature != nil:
+		return s.ZkLoginSuiSignature.Signature
+	case s.PasskeySuiSignature != nil:
+		return s.PasskeySuiSignature.Signature
+	default:
+		return nil
+	}
+}
+
+// Scheme returns the key scheme flag of the signature, or KeySchemeFlagError for an empty signature
+func (s Signature) Scheme() KeySchemeFlag {
+	b := s.Bytes()
+	if len(b) == 0 {
+		return KeySchemeFlagError
+	}
+	return KeySchemeFlag(b[0])
+}
+
+// RawSignature returns the signature without the flag and the public key.
+// It is nil for the authenticators like zkLogin and passkey, which don't carry a public key.
+func (s Signature) RawSignature() []byte {
+	switch {
+	case s.Ed25519SuiSignature != nil:
+		return s.Ed25519SuiSignature.Signature[1 : 1+ed25519.SignatureSize]
+	case s.Secp256k1SuiSignature != nil:
+		return s.Secp256k1SuiSignature.Signature[1 : 1+SignatureLengthSecp256k1]
+	case s.Secp256r1SuiSignature != nil:
+		return s.Secp256r1SuiSignature.Signature[1 : 1+SignatureLengthSecp256r1]
+	default:
+		return nil
+	}
+}
+
+// PublicKey returns the public key carried by the signature
+func (s Signature) PublicKey() []byte {
+	switch {
+	case s.Ed25519SuiSignature != nil:
+		return s.Ed25519SuiSignature.Signature[1+ed25519.SignatureSize:]
+	case s.Secp256k1SuiSignature != nil:
+		return s.Secp256k1SuiSignature.Signature[1+SignatureLengthSecp256k1:]
+	case s.Secp256r1SuiSignature != nil:
+		return s.Secp256r1SuiSignature.Signature[1+SignatureLengthSecp256r1:]
 	default:
 		return nil
 	}
@@ -50,6 +182,10 @@ func (s Signature) MarshalJSON() ([]byte, error) {
 		return json.Marshal(s.Secp256k1SuiSignature.Signature[:])
 	case s.Secp256r1SuiSignature != nil:
 		return json.Marshal(s.Secp256r1SuiSignature.Signature[:])
+	case s.ZkLoginSuiSignature != nil:
+		return json.Marshal(s.ZkLoginSuiSignature.Signature)
+	case s.PasskeySuiSignature != nil:
+		return json.Marshal(s.PasskeySuiSignature.Signature)
 	default:
 		return nil, errors.New("nil signature")
 	}
@@ -61,19 +197,11 @@ func (s *Signature) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	switch signature[0] {
-	case 0:
-		if len(signature) != ed25519.PublicKeySize+ed25519.SignatureSize+1 {
-			return errors.New("invalid ed25519 signature")
-		}
-		var signatureBytes [ed25519.PublicKeySize + ed25519.SignatureSize + 1]byte
-		copy(signatureBytes[:], signature)
-		s.Ed25519SuiSignature = &Ed25519SuiSignature{
-			Signature: signatureBytes,
-		}
-	default:
-		return errors.New("not supported signature")
+	parsed, err := NewSignatureFromBytes(signature)
+	if err != nil {
+		return err
 	}
+	*s = *parsed
 	return nil
 }
 
@@ -89,3 +217,79 @@ func NewEd25519SuiSignature(s *Signer, msg []byte) *Ed25519SuiSignature {
 		Signature: [SizeEd25519SuiSignature]byte(sigBuffer.Bytes()),
 	}
 }
+
+// NewSecp256k1SuiSignature signs `sha256(msg)` with RFC6979 nonce in the lower-S form
+func NewSecp256k1SuiSignature(s *Signer, msg []byte) *Secp256k1SuiSignature {
+	hash := sha256.Sum256(msg)
+	// the first byte of the compact signature is the recovery code
+	compact := secp256k1ecdsa.SignCompact(s.secp256k1Keypair.PriKey, hash[:], true)
+
+	sigBuffer := bytes.NewBuffer([]byte{})
+	sigBuffer.WriteByte(byte(KeySchemeFlagSecp256k1))
+	sigBuffer.Write(compact[1:])
+	sigBuffer.Write(s.secp256k1Keypair.PubKey)
+
+	return &Secp256k1SuiSignature{
+		Signature: sigBuffer.Bytes(),
+	}
+}
+
+// NewSecp256r1SuiSignature signs `sha256(msg)` with RFC6979 nonce in the lower-S form
+func NewSecp256r1SuiSignature(s *Signer, msg []byte) *Secp256r1SuiSignature {
+	hash := sha256.Sum256(msg)
+	priKey := s.secp256r1Keypair.PriKey
+	n := priKey.Curve.Params().N
+	k := nonceRFC6979(priKey.D, n, hash[:])
+	x, _ := priKey.Curve.ScalarBaseMult(k.FillBytes(make([]byte, 32)))
+	r := x.Mod(x, n)
+	// s = k^-1 * (hash + r * d) mod n
+	sigS := new(big.Int).Mul(r, priKey.D)
+	sigS.Add(sigS, new(big.Int).SetBytes(hash[:]))
+	sigS.Mul(sigS, k.ModInverse(k, n))
+	sigS.Mod(sigS, n)
+	if sigS.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		sigS.Sub(n, sigS)
+	}
+
+	sigBuffer := bytes.NewBuffer([]byte{})
+	sigBuffer.WriteByte(byte(KeySchemeFlagSecp256r1))
+	sigBuffer.Write(r.FillBytes(make([]byte, 32)))
+	sigBuffer.Write(sigS.FillBytes(make([]byte, 32)))
+	sigBuffer.Write(s.secp256r1Keypair.PubKey)
+
+	return &Secp256r1SuiSignature{
+		Signature: sigBuffer.Bytes(),
+	}
+}
+
+// nonceRFC6979 generates the nonce of RFC6979 section 3.2 by HMAC-SHA256, for a private key d
+// of a curve whose order n is 256 bits like secp256r1
+func nonceRFC6979(d *big.Int, n *big.Int, hash []byte) *big.Int {
+	mac := func(key []byte, data ...[]byte) []byte {
+		h := hmac.New(sha256.New, key)
+		for _, b := range data {
+			h.Write(b)
+		}
+		return h.Sum(nil)
+	}
+	x := d.FillBytes(make([]byte, 32))
+	h1 := new(big.Int).SetBytes(hash)
+	h1 = h1.Mod(h1, n)
+	h1Bytes := h1.FillBytes(make([]byte, 32))
+
+	v := bytes.Repeat([]byte{1}, 32)
+	k := make([]byte, 32)
+	k = mac(k, v, []byte{0}, x, h1Bytes)
+	v = mac(k, v)
+	k = mac(k, v, []byte{1}, x, h1Bytes)
+	v = mac(k, v)
+	for {
+		v = mac(k, v)
+		nonce := new(big.Int).SetBytes(v)
+		if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+			return nonce
+		}
+		k = mac(k, v, []byte{0})
+		v = mac(k, v)
+	}
+}
@@ -1,9 +1,16 @@
 package suisigner
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/fardream/go-bcs/bcs"
 	"github.com/pattonkan/sui-go/sui"
 	"github.com/tyler-smith/go-bip39"
 	"golang.org/x/crypto/blake2b"
@@ -24,11 +31,24 @@ var (
 	TEST_ADDRESS  = sui.MustAddressFromHex("0x1a02d61c6434b4d0ff252a880c04050b5f27c8b574026c98dd72268865c0ede5")
 )
 
-// FIXME support more than ed25519
+var ErrSignerWiped = errors.New("signer is wiped")
+
+// TransactionSigner signs transactions for an address. Signer implements it, and it can be
+// wrapped, e.g. by a policy guard, or backed by a key held elsewhere.
+type TransactionSigner interface {
+	GetAddress() *sui.Address
+	SignTransactionBlock(txnBytes []byte, intent Intent) (Signature, error)
+}
+
+var _ TransactionSigner = (*Signer)(nil)
+
 type Signer struct {
-	ed25519Keypair *KeypairEd25519
-	// secp256k1Keypair *KeypairSecp256k1
-	Address *sui.Address
+	ed25519Keypair   *KeypairEd25519
+	secp256k1Keypair *KeypairSecp256k1
+	secp256r1Keypair *KeypairSecp256r1
+	Address          *sui.Address
+	// the private key was overwritten by Wipe
+	wiped bool
 }
 
 func NewSigner(seed []byte, flag KeySchemeFlag) *Signer {
@@ -60,7 +80,8 @@ func NewSigner(seed []byte, flag KeySchemeFlag) *Signer {
 	}
 }
 
-// there are only 256 different signers can be generated
+// there are only 256 different signers can be generated, and they aren't BIP-44 compatible.
+// Use Wallet to derive the accounts in `m/44'/784'/{account}'/0'/{index}'`.
 func NewSignerByIndex(seed []byte, flag KeySchemeFlag, index int) *Signer {
 	seed[0] = seed[0] + byte(index)
 	return NewSigner(seed, flag)
@@ -83,10 +104,92 @@ func NewSignerWithMnemonic(mnemonic string, flag KeySchemeFlag) (*Signer, error)
 	return NewSigner(key.Key, flag), nil
 }
 
+// NewSignerWithPrivateKey creates a signer of the given key scheme from a 32 bytes private key,
+// which is the format stored in `sui.keystore`. For ed25519 the private key is the seed.
+func NewSignerWithPrivateKey(flag KeySchemeFlag, privateKey []byte) (*Signer, error) {
+	if len(privateKey) != 32 {
+		return nil, fmt.Errorf("invalid private key length: %d", len(privateKey))
+	}
+	switch flag {
+	case KeySchemeFlagEd25519:
+		return NewSigner(privateKey, flag), nil
+	case KeySchemeFlagSecp256k1:
+		var scalar secp256k1.ModNScalar
+		if overflow := scalar.SetByteSlice(privateKey); overflow || scalar.IsZero() {
+			return nil, errors.New("invalid secp256k1 private key")
+		}
+		keypair := NewKeypairSecp256k1(secp256k1.NewPrivateKey(&scalar))
+		return &Signer{
+			secp256k1Keypair: keypair,
+			Address:          AddressFromPublicKey(flag, keypair.PubKey),
+		}, nil
+	case KeySchemeFlagSecp256r1:
+		curve := elliptic.P256()
+		d := new(big.Int).SetBytes(privateKey)
+		if d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+			return nil, errors.New("invalid secp256r1 private key")
+		}
+		prikey := &ecdsa.PrivateKey{D: d}
+		prikey.Curve = curve
+		prikey.X, prikey.Y = curve.ScalarBaseMult(privateKey)
+		keypair := NewKeypairSecp256r1(prikey)
+		return &Signer{
+			secp256r1Keypair: keypair,
+			Address:          AddressFromPublicKey(flag, keypair.PubKey),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key scheme flag: %d", flag)
+	}
+}
+
+// Wipe overwrites the private key in memory. The signer can't sign after it is wiped, and its signing
+// methods return ErrSignerWiped. It is best effort, since copies made by the Go runtime can't be reached.
+func (s *Signer) Wipe() {
+	s.wiped = true
+	switch {
+	case s.ed25519Keypair != nil:
+		wipeBytes(s.ed25519Keypair.PriKey)
+		s.ed25519Keypair = nil
+	case s.secp256k1Keypair != nil:
+		s.secp256k1Keypair.PriKey.Zero()
+		s.secp256k1Keypair = nil
+	case s.secp256r1Keypair != nil:
+		words := s.secp256r1Keypair.PriKey.D.Bits()
+		for i := range words {
+			words[i] = 0
+		}
+		s.secp256r1Keypair = nil
+	}
+}
+
+func (s *Signer) GetAddress() *sui.Address {
+	return s.Address
+}
+
+// Scheme returns the key scheme of the signer
+func (s *Signer) Scheme() KeySchemeFlag {
+	switch {
+	case s.ed25519Keypair != nil:
+		return KeySchemeFlagEd25519
+	case s.secp256k1Keypair != nil:
+		return KeySchemeFlagSecp256k1
+	case s.secp256r1Keypair != nil:
+		return KeySchemeFlagSecp256r1
+	default:
+		return KeySchemeFlagError
+	}
+}
+
+// PrivateKey returns the private key. For ed25519 it is the 64 bytes `seed || pubkey`,
+// and for secp256k1/secp256r1 it is the 32 bytes scalar.
 func (s *Signer) PrivateKey() []byte {
 	switch {
 	case s.ed25519Keypair != nil:
 		return s.ed25519Keypair.PriKey
+	case s.secp256k1Keypair != nil:
+		return s.secp256k1Keypair.PriKey.Serialize()
+	case s.secp256r1Keypair != nil:
+		return s.secp256r1Keypair.PriKey.D.FillBytes(make([]byte, 32))
 	default:
 		return nil
 	}
@@ -96,21 +199,56 @@ func (s *Signer) PublicKey() []byte {
 	switch {
 	case s.ed25519Keypair != nil:
 		return s.ed25519Keypair.PubKey
+	case s.secp256k1Keypair != nil:
+		return s.secp256k1Keypair.PubKey
+	case s.secp256r1Keypair != nil:
+		return s.secp256r1Keypair.PubKey
 	default:
 		return nil
 	}
 }
 
+// Sign signs the data as is. It returns an empty Signature if the signer is wiped,
+// see SignWithIntent which returns ErrSignerWiped instead.
 func (s *Signer) Sign(data []byte) Signature {
-	// FIXME support more than ed25519
-	return Signature{
-		Ed25519SuiSignature: NewEd25519SuiSignature(s, data),
+	switch {
+	case s.wiped:
+		return Signature{}
+	case s.secp256k1Keypair != nil:
+		return Signature{
+			Secp256k1SuiSignature: NewSecp256k1SuiSignature(s, data),
+		}
+	case s.secp256r1Keypair != nil:
+		return Signature{
+			Secp256r1SuiSignature: NewSecp256r1SuiSignature(s, data),
+		}
+	default:
+		return Signature{
+			Ed25519SuiSignature: NewEd25519SuiSignature(s, data),
+		}
 	}
 }
 
-// FIXME support more than ed25519
 func (a *Signer) SignTransactionBlock(txnBytes []byte, intent Intent) (Signature, error) {
-	data := MessageWithIntent(intent, bcsBytes(txnBytes))
+	return a.SignWithIntent(bcsBytes(txnBytes), intent)
+}
+
+// SignPersonalMessage signs a personal message. The message is wrapped as `vector<u8>` in BCS
+// and signed under the `PersonalMessage` intent scope, the same as the TypeScript and Rust SDKs.
+func (a *Signer) SignPersonalMessage(message []byte) (Signature, error) {
+	bcsMessage, err := bcs.Marshal(message)
+	if err != nil {
+		return Signature{}, fmt.Errorf("can't encode personal message: %w", err)
+	}
+	return a.SignWithIntent(bcsMessage, PersonalMessageIntent())
+}
+
+// SignWithIntent signs `blake2b(intent || message)`, where `message` is already serialized in BCS.
+func (a *Signer) SignWithIntent(message []byte, intent Intent) (Signature, error) {
+	if a.wiped {
+		return Signature{}, ErrSignerWiped
+	}
+	data := MessageWithIntent(intent, message)
 	hash := blake2b.Sum256(data)
 	return a.Sign(hash[:]), nil
 }
@@ -1,15 +1,20 @@
 package suisigner_test
 
 import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/pattonkan/sui-go/sui"
 	"github.com/pattonkan/sui-go/suisigner"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
 )
 
 func TestNewSigner(t *testing.T) {
@@ -40,6 +45,155 @@ func TestSignatureMarshalUnmarshal(t *testing.T) {
 	require.Equal(t, signature1, signature2)
 }
 
+func TestSignPersonalMessage(t *testing.T) {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagDefault)
+	require.NoError(t, err)
+
+	msg := []byte("hello world")
+	signature, err := signer.SignPersonalMessage(msg)
+	require.NoError(t, err)
+
+	// intent (PersonalMessage, V0, Sui) || uleb128(len(msg)) || msg
+	expected := append([]byte{3, 0, 0, byte(len(msg))}, msg...)
+	digest := blake2b.Sum256(expected)
+	require.True(t, ed25519.Verify(signer.PublicKey(), digest[:], signature.RawSignature()))
+
+	addr, err := suisigner.VerifyPersonalMessageSignature(&signature, msg)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, addr)
+}
+
+func TestSignPersonalMessageVectors(t *testing.T) {
+	// ed25519 signs blake2b(intent || bcs(msg)) deterministically, so the serialized signatures
+	// `flag || signature || pubkey` are fixed for the private key of ExampleSigner
+	privKey, err := hex.DecodeString("4ec5a9eefc0bb86027a6f3ba718793c813505acc25ed09447caf6a069accdd4b")
+	require.NoError(t, err)
+	signer := suisigner.NewSigner(privKey, suisigner.KeySchemeFlagEd25519)
+
+	longMsg := make([]byte, 200)
+	for i := range longMsg {
+		longMsg[i] = byte(i)
+	}
+	tests := []struct {
+		name      string
+		msg       []byte
+		signature string
+	}{
+		{
+			name:      "hello world",
+			msg:       []byte("hello world"),
+			signature: "ADazhGadlnjAFLmisdrNvrTwgY8JeXFUUiq5fRQ11Tc0Mud1EBLgb5l+6nQQbyssrU+gj4pvPVAQ+q9pATp6iQ+TQvplUH9c9h8bj7O5SlqoD6my4saJY+MNaKJmClDFfg==",
+		},
+		{
+			name:      "empty",
+			msg:       []byte{},
+			signature: "ACC4IC95UD8T9NmaESJf90OtRS+ZClo85tFsQbZJPU3Wc3ulMjV67+QGWQawMTkOSL0M0fp9bpMXbG9NuUkWDQCTQvplUH9c9h8bj7O5SlqoD6my4saJY+MNaKJmClDFfg==",
+		},
+		{
+			// the length takes 2 bytes in uleb128
+			name:      "200 bytes",
+			msg:       longMsg,
+			signature: "AJ2WlyedF8Ocrq8cGK+fDXbTvJvzFJEcxr4BTxKhNl4Z5sArQ58rlTgj8A8Bi6Hm4yqCZk6g6T2Vz7xUYDPw/AWTQvplUH9c9h8bj7O5SlqoD6my4saJY+MNaKJmClDFfg==",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, err := signer.SignPersonalMessage(tt.msg)
+			require.NoError(t, err)
+			require.Equal(t, tt.signature, base64.StdEncoding.EncodeToString(signature.Bytes()))
+
+			expected, err := base64.StdEncoding.DecodeString(tt.signature)
+			require.NoError(t, err)
+			parsed, err := suisigner.NewSignatureFromBytes(expected)
+			require.NoError(t, err)
+			addr, err := suisigner.VerifyPersonalMessageSignature(parsed, tt.msg)
+			require.NoError(t, err)
+			require.Equal(t, signer.Address, addr)
+		})
+	}
+}
+
+func TestSignWithIntent(t *testing.T) {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagDefault)
+	require.NoError(t, err)
+
+	intent := suisigner.NewIntent(suisigner.IntentScope{TransactionEffects: &sui.EmptyEnum{}})
+	require.Equal(t, []byte{1, 0, 0}, intent.Bytes())
+	defaultIntent := suisigner.DefaultIntent()
+	require.Equal(t, []byte{0, 0, 0}, defaultIntent.Bytes())
+
+	msg := []byte("some effects")
+	signature, err := signer.SignWithIntent(msg, intent)
+	require.NoError(t, err)
+	digest := blake2b.Sum256(append([]byte{1, 0, 0}, msg...))
+	require.True(t, ed25519.Verify(signer.PublicKey(), digest[:], signature.RawSignature()))
+
+	addr, err := suisigner.VerifySignature(&signature, intent, msg)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, addr)
+}
+
+func TestSignAfterWipe(t *testing.T) {
+	for _, flag := range []suisigner.KeySchemeFlag{
+		suisigner.KeySchemeFlagEd25519,
+		suisigner.KeySchemeFlagSecp256k1,
+		suisigner.KeySchemeFlagSecp256r1,
+	} {
+		signer, err := suisigner.NewSignerWithPrivateKey(flag, suisigner.TEST_SEED)
+		require.NoError(t, err)
+		signer.Wipe()
+
+		_, err = signer.SignWithIntent([]byte("msg"), suisigner.DefaultIntent())
+		require.ErrorIs(t, err, suisigner.ErrSignerWiped)
+		_, err = signer.SignTransactionBlock([]byte("tx"), suisigner.DefaultIntent())
+		require.ErrorIs(t, err, suisigner.ErrSignerWiped)
+		_, err = signer.SignPersonalMessage([]byte("msg"))
+		require.ErrorIs(t, err, suisigner.ErrSignerWiped)
+		require.Equal(t, suisigner.Signature{}, signer.Sign([]byte("msg")))
+	}
+}
+
+func TestSignSecp256r1RFC6979(t *testing.T) {
+	// the P-256 and SHA-256 vectors of RFC6979 A.2.5, whose high s is flipped to the lower-S form
+	privKey, err := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	require.NoError(t, err)
+	signer, err := suisigner.NewSignerWithPrivateKey(suisigner.KeySchemeFlagSecp256r1, privKey)
+	require.NoError(t, err)
+
+	n := elliptic.P256().Params().N
+	tests := []struct {
+		msg string
+		r   string
+		s   string
+	}{
+		{
+			msg: "sample",
+			r:   "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716",
+			s:   "f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8",
+		},
+		{
+			msg: "test",
+			r:   "f1abb023518351cd71d881567b1ea663ed3efcf6c5132b354f28d3b0b7d38367",
+			s:   "019f4113742a2b14bd25926b49c649155f267e60d3814b4c0cc84250e46f0083",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			signature := signer.Sign([]byte(tt.msg))
+			raw := signature.RawSignature()
+			require.Equal(t, tt.r, hex.EncodeToString(raw[:32]))
+
+			s, ok := new(big.Int).SetString(tt.s, 16)
+			require.True(t, ok)
+			if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+				s.Sub(n, s)
+			}
+			require.Equal(t, s.FillBytes(make([]byte, 32)), raw[32:])
+			require.Equal(t, signature, signer.Sign([]byte(tt.msg)))
+		})
+	}
+}
+
 func ExampleSigner() {
 	// Create a suisigner.Signer with mnemonic
 	mnemonic := "ordinary cry margin host traffic bulb start zone mimic wage fossil eight diagram clay say remove add atom"
@@ -0,0 +1,127 @@
+package suisigner
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"golang.org/x/crypto/blake2b"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	// zkLogin signatures require the JWKs of the OAuth providers and the groth16 verifying key,
+	// which are only available on chain. Passkey signatures are verified by `suisigner/passkey`.
+	ErrUnsupportedSignature = errors.New("unsupported signature scheme for verification")
+)
+
+// VerifySignature verifies the signature over `intent || message`, where `message` is already
+// serialized in BCS, and returns the Sui address of the signer.
+// It follows `Signature::new_secure()` in Sui: the signed data is `blake2b(intent || message)`,
+// and secp256k1/secp256r1 hash it once more with sha256 before signing.
+func VerifySignature(signature *Signature, intent Intent, message []byte) (*sui.Address, error) {
+	if signature == nil {
+		return nil, ErrInvalidSignature
+	}
+	digest := blake2b.Sum256(MessageWithIntent(intent, message))
+	return VerifyRawSignature(signature, digest[:])
+}
+
+// VerifyRawSignature verifies the signature over `data` as it is, which is the counterpart of
+// `Signer.Sign()`, and returns the Sui address of the signer
+func VerifyRawSignature(signature *Signature, data []byte) (*sui.Address, error) {
+	if signature == nil {
+		return nil, ErrInvalidSignature
+	}
+	pubkey := signature.PublicKey()
+	rawSignature := signature.RawSignature()
+
+	switch {
+	case signature.Ed25519SuiSignature != nil:
+		if !ed25519.Verify(pubkey, data, rawSignature) {
+			return nil, ErrInvalidSignature
+		}
+		return AddressFromPublicKey(KeySchemeFlagEd25519, pubkey), nil
+	case signature.Secp256k1SuiSignature != nil:
+		if err := verifySecp256k1(pubkey, data, rawSignature); err != nil {
+			return nil, err
+		}
+		return AddressFromPublicKey(KeySchemeFlagSecp256k1, pubkey), nil
+	case signature.Secp256r1SuiSignature != nil:
+		if err := verifySecp256r1(pubkey, data, rawSignature); err != nil {
+			return nil, err
+		}
+		return AddressFromPublicKey(KeySchemeFlagSecp256r1, pubkey), nil
+	case signature.ZkLoginSuiSignature != nil, signature.PasskeySuiSignature != nil:
+		return nil, ErrUnsupportedSignature
+	default:
+		return nil, ErrInvalidSignature
+	}
+}
+
+// VerifyTransactionSignature verifies a signature over a BCS encoded `TransactionData`
+// and returns the Sui address of the signer
+func VerifyTransactionSignature(signature *Signature, txnBytes []byte) (*sui.Address, error) {
+	return VerifySignature(signature, DefaultIntent(), txnBytes)
+}
+
+// VerifyPersonalMessageSignature verifies a signature over a personal message and returns the Sui
+// address of the signer. The message is wrapped as `vector<u8>` in BCS before verification.
+func VerifyPersonalMessageSignature(signature *Signature, message []byte) (*sui.Address, error) {
+	bcsMessage, err := bcs.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode personal message: %w", err)
+	}
+	return VerifySignature(signature, PersonalMessageIntent(), bcsMessage)
+}
+
+func verifySecp256k1(pubkey []byte, digest []byte, rawSignature []byte) error {
+	pk, err := secp256k1.ParsePubKey(pubkey)
+	if err != nil {
+		return fmt.Errorf("invalid secp256k1 public key: %w", err)
+	}
+	var r, s secp256k1.ModNScalar
+	if overflow := r.SetByteSlice(rawSignature[:32]); overflow || r.IsZero() {
+		return ErrInvalidSignature
+	}
+	if overflow := s.SetByteSlice(rawSignature[32:]); overflow || s.IsZero() {
+		return ErrInvalidSignature
+	}
+	// Sui only accepts signatures in the lower-S form
+	if s.IsOverHalfOrder() {
+		return ErrInvalidSignature
+	}
+	hash := sha256.Sum256(digest)
+	if !secp256k1ecdsa.NewSignature(&r, &s).Verify(hash[:], pk) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func verifySecp256r1(pubkey []byte, digest []byte, rawSignature []byte) error {
+	curve := elliptic.P256()
+	x, y := elliptic.UnmarshalCompressed(curve, pubkey)
+	if x == nil {
+		return errors.New("invalid secp256r1 public key")
+	}
+	r := new(big.Int).SetBytes(rawSignature[:32])
+	s := new(big.Int).SetBytes(rawSignature[32:])
+	// Sui only accepts signatures in the lower-S form
+	halfOrder := new(big.Int).Rsh(curve.Params().N, 1)
+	if s.Cmp(halfOrder) > 0 {
+		return ErrInvalidSignature
+	}
+	hash := sha256.Sum256(digest)
+	if !ecdsa.Verify(&ecdsa.PublicKey{Curve: curve, X: x, Y: y}, hash[:], r, s) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package suisigner_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+func TestVerifyTransactionSignature(t *testing.T) {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagDefault)
+	require.NoError(t, err)
+
+	txBytes := []byte("I want to have some bubble tea")
+	signature, err := signer.SignTransactionBlock(txBytes, suisigner.DefaultIntent())
+	require.NoError(t, err)
+
+	addr, err := suisigner.VerifyTransactionSignature(&signature, txBytes)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, addr)
+
+	_, err = suisigner.VerifyTransactionSignature(&signature, []byte("I want to have some milk tea"))
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+
+	// the same bytes signed under another intent must not pass as a transaction
+	_, err = suisigner.VerifyPersonalMessageSignature(&signature, txBytes)
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+}
+
+func TestVerifyPersonalMessageSignature(t *testing.T) {
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagDefault)
+	require.NoError(t, err)
+
+	msg := []byte("sign in with sui")
+	signature, err := signer.SignPersonalMessage(msg)
+	require.NoError(t, err)
+
+	serialized, err := signature.MarshalJSON()
+	require.NoError(t, err)
+	parsed, err := suisigner.NewSignatureFromBase64(string(serialized[1 : len(serialized)-1]))
+	require.NoError(t, err)
+
+	addr, err := suisigner.VerifyPersonalMessageSignature(parsed, msg)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, addr)
+}
+
+func TestVerifySecp256k1Signature(t *testing.T) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	pubKey := privKey.PubKey().SerializeCompressed()
+
+	txBytes := []byte("I want to have some bubble tea")
+	digest := blake2b.Sum256(suisigner.MessageWithIntent(suisigner.DefaultIntent(), txBytes))
+	hash := sha256.Sum256(digest[:])
+	compact := secp256k1ecdsa.SignCompact(privKey, hash[:], true)
+
+	serialized := append([]byte{suisigner.KeySchemeFlagSecp256k1.Byte()}, compact[1:]...)
+	serialized = append(serialized, pubKey...)
+	signature, err := suisigner.NewSignatureFromBytes(serialized)
+	require.NoError(t, err)
+	require.Equal(t, suisigner.KeySchemeFlagSecp256k1, signature.Scheme())
+
+	addr, err := suisigner.VerifyTransactionSignature(signature, txBytes)
+	require.NoError(t, err)
+	require.Equal(t, suisigner.AddressFromPublicKey(suisigner.KeySchemeFlagSecp256k1, pubKey), addr)
+
+	_, err = suisigner.VerifyTransactionSignature(signature, []byte("tampered"))
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+}
+
+func TestVerifySecp256r1Signature(t *testing.T) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubKey := elliptic.MarshalCompressed(elliptic.P256(), privKey.X, privKey.Y)
+
+	txBytes := []byte("I want to have some bubble tea")
+	digest := blake2b.Sum256(suisigner.MessageWithIntent(suisigner.DefaultIntent(), txBytes))
+	hash := sha256.Sum256(digest[:])
+	r, s, err := ecdsa.Sign(rand.Reader, privKey, hash[:])
+	require.NoError(t, err)
+	highS := new(big.Int).Set(s)
+	halfOrder := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+	if s.Cmp(halfOrder) > 0 {
+		s.Sub(elliptic.P256().Params().N, s)
+	} else {
+		highS.Sub(elliptic.P256().Params().N, s)
+	}
+
+	serialize := func(s *big.Int) []byte {
+		buf := []byte{suisigner.KeySchemeFlagSecp256r1.Byte()}
+		buf = append(buf, r.FillBytes(make([]byte, 32))...)
+		buf = append(buf, s.FillBytes(make([]byte, 32))...)
+		return append(buf, pubKey...)
+	}
+
+	signature, err := suisigner.NewSignatureFromBytes(serialize(s))
+	require.NoError(t, err)
+	addr, err := suisigner.VerifyTransactionSignature(signature, txBytes)
+	require.NoError(t, err)
+	require.Equal(t, suisigner.AddressFromPublicKey(suisigner.KeySchemeFlagSecp256r1, pubKey), addr)
+
+	// the malleated signature is rejected
+	signature, err = suisigner.NewSignatureFromBytes(serialize(highS))
+	require.NoError(t, err)
+	_, err = suisigner.VerifyTransactionSignature(signature, txBytes)
+	require.ErrorIs(t, err, suisigner.ErrInvalidSignature)
+}
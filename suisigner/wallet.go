@@ -0,0 +1,145 @@
+package suisigner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultDiscoveryGapLimit is the number of consecutive unused accounts after which the discovery stops, the same as BIP-44
+const DefaultDiscoveryGapLimit = 20
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// mnemonic word count to the entropy size in bits
+var mnemonicEntropyBits = map[int]int{
+	12: 128,
+	15: 160,
+	18: 192,
+	21: 224,
+	24: 256,
+}
+
+// NewMnemonic generates a BIP-39 mnemonic with 12, 15, 18, 21 or 24 words
+func NewMnemonic(words int) (string, error) {
+	bits, ok := mnemonicEntropyBits[words]
+	if !ok {
+		return "", fmt.Errorf("unsupported mnemonic word count: %d", words)
+	}
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the word count, the words and the checksum of a BIP-39 mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	words := len(strings.Fields(mnemonic))
+	if _, ok := mnemonicEntropyBits[words]; !ok {
+		return fmt.Errorf("%w: unsupported word count %d", ErrInvalidMnemonic, words)
+	}
+	if _, err := bip39.MnemonicToByteArray(mnemonic); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidMnemonic, err)
+	}
+	return nil
+}
+
+// WalletDerivationPath returns the BIP-44 path of the ed25519 account, `m/44'/784'/{account}'/0'/{index}'`
+func WalletDerivationPath(account uint32, index uint32) string {
+	return fmt.Sprintf("m/44'/784'/%d'/0'/%d'", account, index)
+}
+
+// Wallet is a hierarchical deterministic wallet of ed25519 accounts, compatible with the Sui CLI and wallets.
+type Wallet struct {
+	seed []byte
+}
+
+// WalletAccount is an account derived from a Wallet
+type WalletAccount struct {
+	Account uint32
+	Index   uint32
+	Path    string
+	Signer  *Signer
+}
+
+// NewWallet creates a wallet from a BIP-39 mnemonic and an optional passphrase
+func NewWallet(mnemonic string, passphrase string) (*Wallet, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidMnemonic, err)
+	}
+	return &Wallet{seed: seed}, nil
+}
+
+// Account derives the signer at `m/44'/784'/{account}'/0'/{index}'`
+func (w *Wallet) Account(account uint32, index uint32) (*WalletAccount, error) {
+	if account >= FirstHardenedIndex || index >= FirstHardenedIndex {
+		return nil, ErrInvalidPath
+	}
+	path := WalletDerivationPath(account, index)
+	key, err := DeriveForPath(path, w.seed)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletAccount{
+		Account: account,
+		Index:   index,
+		Path:    path,
+		Signer:  NewSigner(key.Key, KeySchemeFlagEd25519),
+	}, nil
+}
+
+// Signer derives the signer of the account at `m/44'/784'/{account}'/0'/0'`, which is the address
+// that the Sui wallets show for the account
+func (w *Wallet) Signer(account uint32) (*Signer, error) {
+	walletAccount, err := w.Account(account, 0)
+	if err != nil {
+		return nil, err
+	}
+	return walletAccount.Signer, nil
+}
+
+// Wipe overwrites the seed in memory. The wallet can't be used after it is wiped.
+func (w *Wallet) Wipe() {
+	wipeBytes(w.seed)
+	w.seed = nil
+}
+
+// AccountActivityChecker reports whether an address has been used on chain.
+// `suiclient.ClientImpl.AddressHasActivity` is the default implementation.
+type AccountActivityChecker func(ctx context.Context, address *sui.Address) (bool, error)
+
+// DiscoverAccounts scans the accounts `m/44'/784'/{account}'/0'/0'` from account 0, and stops once
+// `gapLimit` consecutive accounts have no activity. It returns the accounts with activity.
+func (w *Wallet) DiscoverAccounts(ctx context.Context, gapLimit int, hasActivity AccountActivityChecker) ([]*WalletAccount, error) {
+	if gapLimit <= 0 {
+		gapLimit = DefaultDiscoveryGapLimit
+	}
+	var accounts []*WalletAccount
+	gap := 0
+	for account := uint32(0); gap < gapLimit; account++ {
+		walletAccount, err := w.Account(account, 0)
+		if err != nil {
+			return nil, err
+		}
+		active, err := hasActivity(ctx, walletAccount.Signer.Address)
+		if err != nil {
+			return nil, fmt.Errorf("can't check the activity of account %d: %w", account, err)
+		}
+		if active {
+			accounts = append(accounts, walletAccount)
+			gap = 0
+		} else {
+			gap++
+		}
+	}
+	return accounts, nil
+}
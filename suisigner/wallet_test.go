@@ -0,0 +1,92 @@
+package suisigner_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+func TestNewMnemonic(t *testing.T) {
+	for _, words := range []int{12, 15, 18, 21, 24} {
+		mnemonic, err := suisigner.NewMnemonic(words)
+		require.NoError(t, err)
+		require.Len(t, strings.Fields(mnemonic), words)
+		require.NoError(t, suisigner.ValidateMnemonic(mnemonic))
+	}
+	_, err := suisigner.NewMnemonic(13)
+	require.Error(t, err)
+
+	require.ErrorIs(t, suisigner.ValidateMnemonic("ordinary cry margin"), suisigner.ErrInvalidMnemonic)
+	// a wrong checksum
+	words := strings.Fields(suisigner.TEST_MNEMONIC)
+	words[len(words)-1] = "abandon"
+	require.ErrorIs(t, suisigner.ValidateMnemonic(strings.Join(words, " ")), suisigner.ErrInvalidMnemonic)
+}
+
+func TestWallet(t *testing.T) {
+	wallet, err := suisigner.NewWallet(suisigner.TEST_MNEMONIC, "")
+	require.NoError(t, err)
+
+	// account 0 is the one imported by the Sui CLI
+	signer, err := suisigner.NewSignerWithMnemonic(suisigner.TEST_MNEMONIC, suisigner.KeySchemeFlagEd25519)
+	require.NoError(t, err)
+	account0, err := wallet.Signer(0)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address, account0.Address)
+
+	// the accounts aren't limited to 256
+	account1000, err := wallet.Account(1000, 3)
+	require.NoError(t, err)
+	require.Equal(t, "m/44'/784'/1000'/0'/3'", account1000.Path)
+	require.NotEqual(t, account0.Address, account1000.Signer.Address)
+
+	// the passphrase leads to another wallet
+	walletWithPassphrase, err := suisigner.NewWallet(suisigner.TEST_MNEMONIC, "bubble tea")
+	require.NoError(t, err)
+	other, err := walletWithPassphrase.Signer(0)
+	require.NoError(t, err)
+	require.NotEqual(t, account0.Address, other.Address)
+
+	_, err = wallet.Account(suisigner.FirstHardenedIndex, 0)
+	require.Error(t, err)
+}
+
+func TestWalletDiscoverAccounts(t *testing.T) {
+	wallet, err := suisigner.NewWallet(suisigner.TEST_MNEMONIC, "")
+	require.NoError(t, err)
+
+	used := map[sui.Address]bool{}
+	for _, account := range []uint32{0, 1, 4} {
+		signer, err := wallet.Signer(account)
+		require.NoError(t, err)
+		used[*signer.Address] = true
+	}
+	checked := 0
+	checker := func(ctx context.Context, address *sui.Address) (bool, error) {
+		checked++
+		return used[*address], nil
+	}
+
+	accounts, err := wallet.DiscoverAccounts(context.Background(), 3, checker)
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+	require.Equal(t, uint32(4), accounts[2].Account)
+	// accounts 0 to 4 and the gap of 5, 6 and 7
+	require.Equal(t, 8, checked)
+
+	// the gap of 2 ends the discovery before account 4
+	accounts, err = wallet.DiscoverAccounts(context.Background(), 2, checker)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+
+	_, err = wallet.DiscoverAccounts(context.Background(), 2, func(ctx context.Context, address *sui.Address) (bool, error) {
+		return false, errors.New("rpc failure")
+	})
+	require.Error(t, err)
+}
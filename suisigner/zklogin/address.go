@@ -0,0 +1,140 @@
+package zklogin
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/iden3/go-iden3-crypto/poseidon"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"golang.org/x/crypto/blake2b"
+)
+
+// the max lengths supported by the zkLogin circuit
+const (
+	MaxKeyClaimNameLength  = 32
+	MaxKeyClaimValueLength = 115
+	MaxAudValueLength      = 145
+)
+
+const (
+	// DefaultKeyClaimName is the claim that identifies the user, which most of the OAuth providers use
+	DefaultKeyClaimName = "sub"
+
+	packWidth = 248
+)
+
+// PoseidonHash is the poseidon hash over BN254 used by zkLogin. Inputs longer than 16 are hashed
+// in two halves, and at most 32 inputs are supported.
+func PoseidonHash(inputs []*big.Int) (*big.Int, error) {
+	switch {
+	case len(inputs) == 0:
+		return nil, errors.New("empty poseidon inputs")
+	case len(inputs) <= 16:
+		return poseidon.Hash(inputs)
+	case len(inputs) <= 32:
+		hash1, err := poseidon.Hash(inputs[:16])
+		if err != nil {
+			return nil, err
+		}
+		hash2, err := poseidon.Hash(inputs[16:])
+		if err != nil {
+			return nil, err
+		}
+		return poseidon.Hash([]*big.Int{hash1, hash2})
+	default:
+		return nil, fmt.Errorf("too many poseidon inputs: %d", len(inputs))
+	}
+}
+
+// HashASCIIStrToField pads str with zeros to maxSize bytes, packs the bytes into 248-bit big-endian
+// field elements aligned from the end, and hashes them with poseidon
+func HashASCIIStrToField(str string, maxSize int) (*big.Int, error) {
+	if len(str) > maxSize {
+		return nil, fmt.Errorf("string %s is longer than %d", str, maxSize)
+	}
+	padded := make([]byte, maxSize)
+	copy(padded, str)
+
+	chunkSize := packWidth / 8
+	numChunks := (len(padded) + chunkSize - 1) / chunkSize
+	packed := make([]*big.Int, numChunks)
+	end := len(padded)
+	for i := numChunks - 1; i >= 0; i-- {
+		start := end - chunkSize
+		if start < 0 {
+			start = 0
+		}
+		packed[i] = new(big.Int).SetBytes(padded[start:end])
+		end = start
+	}
+	return PoseidonHash(packed)
+}
+
+// GenAddressSeed computes the address seed, which is
+// `poseidon(hash(keyClaimName), hash(keyClaimValue), hash(aud), poseidon(userSalt))`
+func GenAddressSeed(userSalt *big.Int, keyClaimName string, keyClaimValue string, aud string) (*big.Int, error) {
+	nameHash, err := HashASCIIStrToField(keyClaimName, MaxKeyClaimNameLength)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key claim name: %w", err)
+	}
+	valueHash, err := HashASCIIStrToField(keyClaimValue, MaxKeyClaimValueLength)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key claim value: %w", err)
+	}
+	audHash, err := HashASCIIStrToField(aud, MaxAudValueLength)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aud: %w", err)
+	}
+	saltHash, err := PoseidonHash([]*big.Int{userSalt})
+	if err != nil {
+		return nil, fmt.Errorf("invalid user salt: %w", err)
+	}
+	return PoseidonHash([]*big.Int{nameHash, valueHash, audHash, saltHash})
+}
+
+// ComputeAddressFromSeed derives the zkLogin address, which is
+// `blake2b(flag || len(iss) || iss || addressSeed)` with the address seed in 32 bytes big-endian
+func ComputeAddressFromSeed(addressSeed *big.Int, iss string) (*sui.Address, error) {
+	if addressSeed.Sign() < 0 || addressSeed.BitLen() > 256 {
+		return nil, errors.New("invalid address seed")
+	}
+	return computeAddress(addressSeed.FillBytes(make([]byte, 32)), iss), nil
+}
+
+// ComputeLegacyAddressFromSeed derives the zkLogin address with the leading zeros of the address seed
+// trimmed, which was how the early versions of the TypeScript SDK derived the addresses
+func ComputeLegacyAddressFromSeed(addressSeed *big.Int, iss string) (*sui.Address, error) {
+	if addressSeed.Sign() < 0 || addressSeed.BitLen() > 256 {
+		return nil, errors.New("invalid address seed")
+	}
+	return computeAddress(addressSeed.Bytes(), iss), nil
+}
+
+// ComputeAddress derives the zkLogin address of the user identified by `keyClaimName` (usually "sub")
+// and `keyClaimValue`, who signs in the app `aud` with the OAuth provider `iss`
+func ComputeAddress(iss string, aud string, keyClaimName string, keyClaimValue string, userSalt *big.Int) (*sui.Address, error) {
+	addressSeed, err := GenAddressSeed(userSalt, keyClaimName, keyClaimValue, aud)
+	if err != nil {
+		return nil, err
+	}
+	return ComputeAddressFromSeed(addressSeed, iss)
+}
+
+func computeAddress(addressSeed []byte, iss string) *sui.Address {
+	iss = normalizeIss(iss)
+	buf := []byte{suisigner.KeySchemeFlagZkLoginAuthenticator.Byte(), byte(len(iss))}
+	buf = append(buf, iss...)
+	buf = append(buf, addressSeed...)
+	addr := sui.Address(blake2b.Sum256(buf))
+	return &addr
+}
+
+// Google issues JWTs both with and without the scheme, and the circuit uses the one with it
+func normalizeIss(iss string) string {
+	if iss == "accounts.google.com" {
+		return "https://accounts.google.com"
+	}
+	return iss
+}
@@ -0,0 +1,188 @@
+package zklogin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+)
+
+// ProofPoints is the groth16 proof returned by the zkLogin prover
+type ProofPoints struct {
+	A []string   `json:"a"`
+	B [][]string `json:"b"`
+	C []string   `json:"c"`
+}
+
+// Claim is a claim in the JWT payload. The value is the base64url substring of the payload
+// which contains the claim, and IndexMod4 is the offset of the substring in the payload modulo 4.
+type Claim struct {
+	Value     string `json:"value"`
+	IndexMod4 uint8  `json:"indexMod4"`
+}
+
+// ZkLoginInputs is the response of the zkLogin prover, with the address seed filled by the caller
+type ZkLoginInputs struct {
+	ProofPoints      ProofPoints `json:"proofPoints"`
+	IssBase64Details Claim       `json:"issBase64Details"`
+	HeaderBase64     string      `json:"headerBase64"`
+	AddressSeed      string      `json:"addressSeed"`
+}
+
+// ZkLoginSignature is the zkLogin authenticator. UserSignature is the serialized signature
+// of the ephemeral key, `flag || signature || pubkey`.
+type ZkLoginSignature struct {
+	Inputs        ZkLoginInputs
+	MaxEpoch      uint64
+	UserSignature []byte
+}
+
+// NewZkLoginSignature assembles the zkLogin authenticator from the prover response and the signature
+// of the ephemeral key, which is valid until `maxEpoch`
+func NewZkLoginSignature(inputs *ZkLoginInputs, maxEpoch uint64, userSignature *suisigner.Signature) (*ZkLoginSignature, error) {
+	if inputs == nil || userSignature == nil {
+		return nil, errors.New("nil zklogin inputs or user signature")
+	}
+	if inputs.AddressSeed == "" {
+		return nil, errors.New("empty address seed")
+	}
+	switch userSignature.Scheme() {
+	case suisigner.KeySchemeFlagEd25519, suisigner.KeySchemeFlagSecp256k1, suisigner.KeySchemeFlagSecp256r1:
+	default:
+		return nil, fmt.Errorf("unsupported ephemeral signature scheme: %d", userSignature.Scheme())
+	}
+	return &ZkLoginSignature{
+		Inputs:        *inputs,
+		MaxEpoch:      maxEpoch,
+		UserSignature: userSignature.Bytes(),
+	}, nil
+}
+
+// ParseZkLoginSignature parses a serialized zkLogin authenticator, `flag || bcs(ZkLoginSignature)`
+func ParseZkLoginSignature(signature []byte) (*ZkLoginSignature, error) {
+	if len(signature) == 0 || signature[0] != suisigner.KeySchemeFlagZkLoginAuthenticator.Byte() {
+		return nil, errors.New("not a zklogin signature")
+	}
+	var sig ZkLoginSignature
+	n, err := bcs.Unmarshal(signature[1:], &sig)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode zklogin signature: %w", err)
+	}
+	if n != len(signature)-1 {
+		return nil, errors.New("trailing bytes in zklogin signature")
+	}
+	return &sig, nil
+}
+
+// Bytes serializes the authenticator as `flag || bcs(ZkLoginSignature)`
+func (z *ZkLoginSignature) Bytes() ([]byte, error) {
+	b, err := bcs.Marshal(z)
+	if err != nil {
+		return nil, fmt.Errorf("can't encode zklogin signature: %w", err)
+	}
+	return append([]byte{suisigner.KeySchemeFlagZkLoginAuthenticator.Byte()}, b...), nil
+}
+
+// SuiSignature converts the authenticator to suisigner.Signature, which can be submitted to
+// `sui_executeTransactionBlock` like any other signature
+func (z *ZkLoginSignature) SuiSignature() (*suisigner.Signature, error) {
+	b, err := z.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return suisigner.NewSignatureFromBytes(b)
+}
+
+// Iss decodes the `iss` claim from the inputs
+func (z *ZkLoginSignature) Iss() (string, error) {
+	return z.Inputs.IssBase64Details.Decode("iss")
+}
+
+// Address returns the zkLogin address which the authenticator signs for
+func (z *ZkLoginSignature) Address() (*sui.Address, error) {
+	iss, err := z.Iss()
+	if err != nil {
+		return nil, err
+	}
+	addressSeed, ok := new(big.Int).SetString(z.Inputs.AddressSeed, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid address seed: %s", z.Inputs.AddressSeed)
+	}
+	return ComputeAddressFromSeed(addressSeed, iss)
+}
+
+// Decode extracts the claim `name` from the base64url substring. The substring decodes to
+// `"name":value` followed by either ',' or '}'.
+func (c Claim) Decode(name string) (string, error) {
+	extended, err := decodeBase64URL(c.Value, int(c.IndexMod4))
+	if err != nil {
+		return "", err
+	}
+	extended = strings.TrimSpace(extended)
+	if len(extended) == 0 || (extended[len(extended)-1] != ',' && extended[len(extended)-1] != '}') {
+		return "", fmt.Errorf("invalid extended claim: %s", extended)
+	}
+	var claim map[string]string
+	if err := json.Unmarshal([]byte("{"+extended[:len(extended)-1]+"}"), &claim); err != nil {
+		return "", fmt.Errorf("invalid extended claim: %w", err)
+	}
+	value, ok := claim[name]
+	if !ok {
+		return "", fmt.Errorf("claim %s not found", name)
+	}
+	return value, nil
+}
+
+// decodeBase64URL decodes a base64url substring which starts at the offset `indexMod4` of the
+// original string, so the bits of the partial characters at both ends are dropped
+func decodeBase64URL(s string, indexMod4 int) (string, error) {
+	if len(s) < 2 {
+		return "", errors.New("base64url string is too short")
+	}
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	bits := make([]byte, 0, len(s)*6)
+	for _, c := range []byte(s) {
+		v := strings.IndexByte(alphabet, c)
+		if v < 0 {
+			return "", fmt.Errorf("invalid base64url character: %c", c)
+		}
+		for i := 5; i >= 0; i-- {
+			bits = append(bits, byte(v>>i)&1)
+		}
+	}
+
+	switch indexMod4 % 4 {
+	case 0:
+	case 1:
+		bits = bits[2:]
+	case 2:
+		bits = bits[4:]
+	default:
+		return "", errors.New("invalid first character offset")
+	}
+	switch (indexMod4 + len(s) - 1) % 4 {
+	case 3:
+	case 2:
+		bits = bits[:len(bits)-2]
+	case 1:
+		bits = bits[:len(bits)-4]
+	default:
+		return "", errors.New("invalid last character offset")
+	}
+	if len(bits)%8 != 0 {
+		return "", errors.New("invalid base64url bit length")
+	}
+
+	out := make([]byte, len(bits)/8)
+	for i := range out {
+		for j := 0; j < 8; j++ {
+			out[i] = out[i]<<1 | bits[i*8+j]
+		}
+	}
+	return string(out), nil
+}
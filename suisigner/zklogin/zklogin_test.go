@@ -0,0 +1,161 @@
+package zklogin_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/suisigner"
+	"github.com/pattonkan/sui-go/suisigner/zklogin"
+)
+
+func TestPoseidonHash(t *testing.T) {
+	hash, err := zklogin.PoseidonHash([]*big.Int{big.NewInt(1)})
+	require.NoError(t, err)
+	require.Equal(t, "18586133768512220936620570745912940619677854269274689475585506675881198879027", hash.String())
+
+	hash, err = zklogin.PoseidonHash([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	require.NoError(t, err)
+	require.Equal(t, "7853200120776062878684798364095072458815029376092732009249414926327459813530", hash.String())
+
+	inputs := make([]*big.Int, 20)
+	for i := range inputs {
+		inputs[i] = big.NewInt(int64(i))
+	}
+	_, err = zklogin.PoseidonHash(inputs)
+	require.NoError(t, err)
+	_, err = zklogin.PoseidonHash(make([]*big.Int, 33))
+	require.Error(t, err)
+}
+
+func TestHashASCIIStrToField(t *testing.T) {
+	// the vector of fastcrypto
+	hash, err := zklogin.HashASCIIStrToField("test@gmail.com", 30)
+	require.NoError(t, err)
+	require.Equal(t, "13606676331558803166736332982602687405662978305929711411606106012181987145625", hash.String())
+
+	_, err = zklogin.HashASCIIStrToField("sub", zklogin.MaxKeyClaimNameLength)
+	require.NoError(t, err)
+	_, err = zklogin.HashASCIIStrToField(string(make([]byte, 33)), zklogin.MaxKeyClaimNameLength)
+	require.Error(t, err)
+}
+
+func TestComputeAddressFromSeed(t *testing.T) {
+	iss := "https://accounts.google.com"
+
+	// the vectors of the TypeScript SDK
+	seed, ok := new(big.Int).SetString("13322897930163218532266430409510394316985274769125667290600321564259466511711", 10)
+	require.True(t, ok)
+	addr, err := zklogin.ComputeAddressFromSeed(seed, iss)
+	require.NoError(t, err)
+	require.Equal(t, sui.MustAddressFromHex("0xf7badc2b245c7f74d7509a4aa357ecf80a29e7713fb4c44b0e7541ec43885ee1"), addr)
+	// a seed of 32 bytes has the same legacy address
+	legacy, err := zklogin.ComputeLegacyAddressFromSeed(seed, iss)
+	require.NoError(t, err)
+	require.Equal(t, addr, legacy)
+
+	// the legacy address of a seed with a leading zero byte drops it, and the padded one keeps it
+	seed, ok = new(big.Int).SetString("380704556853533152350240698167704405529973457670972223618755249929828551006", 10)
+	require.True(t, ok)
+	legacy, err = zklogin.ComputeLegacyAddressFromSeed(seed, iss)
+	require.NoError(t, err)
+	require.Equal(t, sui.MustAddressFromHex("0xbd8b8ed42d90aebc71518385d8a899af14cef8b5a171c380434dd6f5bbfe7bf3"), legacy)
+	addr, err = zklogin.ComputeAddressFromSeed(seed, iss)
+	require.NoError(t, err)
+	require.Equal(t, sui.MustAddressFromHex("0x3f8f50fc9440351a8d16a6b473493099dc988758e9edef64a93abfe7d435d527"), addr)
+}
+
+func TestComputeAddress(t *testing.T) {
+	salt, ok := new(big.Int).SetString("129390038577185583942388216820280642146", 10)
+	require.True(t, ok)
+	iss := "https://accounts.google.com"
+	aud := "25769832374-famecqrhe2gkebt5fvqms2263046lj96.apps.googleusercontent.com"
+	sub := "106294049240999307923"
+
+	// pinned as a regression vector, no SDK vector of the whole tuple is known to us. The steps are
+	// checked by the vectors of fastcrypto and the TypeScript SDK above.
+	addressSeed, err := zklogin.GenAddressSeed(salt, zklogin.DefaultKeyClaimName, sub, aud)
+	require.NoError(t, err)
+	require.Equal(t, "6108066888816152909779260797647401844352456009615316883911918765612489541289", addressSeed.String())
+	addr, err := zklogin.ComputeAddress(iss, aud, zklogin.DefaultKeyClaimName, sub, salt)
+	require.NoError(t, err)
+	require.Equal(t, sui.MustAddressFromHex("0x22c206f1ac4ae07069ccc61a9fc9ccc8f85dfb25ceff57869e40804ce71a7bfe"), addr)
+
+	// google issues the JWTs with and without the scheme
+	addr2, err := zklogin.ComputeAddress("accounts.google.com", aud, zklogin.DefaultKeyClaimName, sub, salt)
+	require.NoError(t, err)
+	require.Equal(t, addr, addr2)
+
+	// different salts lead to different addresses
+	addr3, err := zklogin.ComputeAddress(iss, aud, zklogin.DefaultKeyClaimName, sub, big.NewInt(1))
+	require.NoError(t, err)
+	require.NotEqual(t, addr, addr3)
+}
+
+func TestClaimDecode(t *testing.T) {
+	claim := zklogin.Claim{
+		Value:     "wiaXNzIjoiaHR0cHM6Ly9hY2NvdW50cy5nb29nbGUuY29tIiw",
+		IndexMod4: 2,
+	}
+	iss, err := claim.Decode("iss")
+	require.NoError(t, err)
+	require.Equal(t, "https://accounts.google.com", iss)
+
+	_, err = claim.Decode("aud")
+	require.Error(t, err)
+}
+
+func TestZkLoginSignature(t *testing.T) {
+	// an ed25519 signature, `flag || signature || pubkey`
+	userSignature, err := suisigner.NewSignatureFromBytes(append(append([]byte{0}, bytes.Repeat([]byte{1}, 64)...), bytes.Repeat([]byte{2}, 32)...))
+	require.NoError(t, err)
+
+	inputs := &zklogin.ZkLoginInputs{
+		ProofPoints: zklogin.ProofPoints{
+			A: []string{"1", "2", "1"},
+			B: [][]string{{"1", "2"}, {"3", "4"}, {"1", "0"}},
+			C: []string{"5", "6", "1"},
+		},
+		IssBase64Details: zklogin.Claim{
+			Value:     "wiaXNzIjoiaHR0cHM6Ly9hY2NvdW50cy5nb29nbGUuY29tIiw",
+			IndexMod4: 2,
+		},
+		HeaderBase64: "eyJhbGciOiJSUzI1NiIsImtpZCI6IjEiLCJ0eXAiOiJKV1QifQ",
+		AddressSeed:  "12345678901234567890",
+	}
+	zkSig, err := zklogin.NewZkLoginSignature(inputs, 42, userSignature)
+	require.NoError(t, err)
+
+	// encoded by hand from the ZkLoginAuthenticator of sui-types, where the field elements are decimal strings
+	b, err := zkSig.Bytes()
+	require.NoError(t, err)
+	expected, err := hex.DecodeString("0503013101320131030201310132020133013402013101300301350136013131776961584e7a496a6f696148523063484d364c7939685932" +
+		"4e76645735306379356e6232396e6247557559323974496977023265794a68624763694f694a53557a49314e694973496d74705a43493649" +
+		"6a45694c434a30655841694f694a4b5631516966511431323334353637383930313233343536373839302a00000000000000610001010101" +
+		"0101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101010101" +
+		"010101010202020202020202020202020202020202020202020202020202020202020202")
+	require.NoError(t, err)
+	require.Equal(t, expected, b)
+
+	parsed, err := zklogin.ParseZkLoginSignature(b)
+	require.NoError(t, err)
+	require.Equal(t, zkSig, parsed)
+
+	sig, err := zkSig.SuiSignature()
+	require.NoError(t, err)
+	require.Equal(t, suisigner.KeySchemeFlagZkLoginAuthenticator, sig.Scheme())
+	require.Equal(t, b, sig.Bytes())
+	_, err = suisigner.VerifyTransactionSignature(sig, []byte("txn bytes"))
+	require.ErrorIs(t, err, suisigner.ErrUnsupportedSignature)
+
+	addr, err := zkSig.Address()
+	require.NoError(t, err)
+	seed, _ := new(big.Int).SetString(inputs.AddressSeed, 10)
+	expectedAddr, err := zklogin.ComputeAddressFromSeed(seed, "https://accounts.google.com")
+	require.NoError(t, err)
+	require.Equal(t, expectedAddr, addr)
+}
@@ -52,9 +52,12 @@ func (m *IndexMap[K, V]) Get(key K) (V, bool) {
 	return value, exists
 }
 
+// Find returns the insertion index of the key. Keys are compared by their hashes as in the map,
+// so keys holding pointers to equal values are the same key.
 func (m *IndexMap[K, V]) Find(key K) (int, bool) {
+	hash := GetHash(key)
 	for i, v := range m.InsertOrderList {
-		if v == key {
+		if GetHash(v) == hash {
 			return i, true
 		}
 	}
@@ -76,4 +76,24 @@ func TestIndexMap(t *testing.T) {
 		})
 		require.Equal(t, targetList, testList)
 	})
+
+	t.Run("keys with equal pointees", func(t *testing.T) {
+		// the keys of pure inputs point to different slices of the same bytes, which are the same key in the map
+		m := indexmap.NewIndexMap[suiptb.BuilderArg, suiptb.CallArg]()
+		first := []byte{1, 2, 3}
+		second := []byte{1, 2, 3}
+		third := []byte{4, 5, 6}
+		require.Equal(t, 0, m.InsertFull(suiptb.BuilderArg{Pure: &first}, suiptb.CallArg{Pure: &first}))
+		require.Equal(t, 1, m.InsertFull(suiptb.BuilderArg{Pure: &third}, suiptb.CallArg{Pure: &third}))
+		// the update of an existing key returns the index of its first insertion, instead of panicking
+		require.Equal(t, 0, m.InsertFull(suiptb.BuilderArg{Pure: &second}, suiptb.CallArg{Pure: &second}))
+		require.Equal(t, 2, m.Len())
+
+		idx, ok := m.Find(suiptb.BuilderArg{Pure: &second})
+		require.True(t, ok)
+		require.Equal(t, 0, idx)
+		missing := []byte{7}
+		_, ok = m.Find(suiptb.BuilderArg{Pure: &missing})
+		require.False(t, ok)
+	})
 }
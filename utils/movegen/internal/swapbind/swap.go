@@ -0,0 +1,135 @@
+// Code generated by movegen. DO NOT EDIT.
+
+package swapbind
+
+import (
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+)
+
+// PackageId is the package the MoveCalls are sent to
+var PackageId = sui.MustPackageIdFromHex("0x8b1c2b2c0b5e0e5b3a4c0f6f3b4b2b1a0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f")
+
+// SwapLSP is the Move struct `swap::LSP<phantom T0>`
+type SwapLSP[T0 any] struct {
+	DummyField bool
+}
+
+// SwapPool is the Move struct `swap::Pool<phantom T0>`
+type SwapPool[T0 any] struct {
+	Id         *sui.ObjectId
+	Sui        movebcs.MoveBalance
+	Token      movebcs.MoveBalance
+	LspSupply  movebcs.MoveSupply
+	FeePercent uint64
+}
+
+// SwapAddLiquidity adds a MoveCall of `swap::add_liquidity` to the PTB
+func SwapAddLiquidity(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument, arg2 suiptb.Argument) suiptb.Result[movebcs.MoveCoin] {
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "add_liquidity", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1, arg2})
+	return suiptb.Result[movebcs.MoveCoin]{Argument: result}
+}
+
+// SwapAddLiquidity_ adds a MoveCall of `swap::add_liquidity_` to the PTB
+func SwapAddLiquidity_(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument, arg2 suiptb.Argument) {
+	ptb.ProgrammableMoveCall(PackageId, "swap", "add_liquidity_", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1, arg2})
+}
+
+// SwapCreatePool adds a MoveCall of `swap::create_pool` to the PTB
+func SwapCreatePool(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument, arg2 uint64) (suiptb.Result[movebcs.MoveCoin], error) {
+	input2, err := ptb.Pure(arg2)
+	if err != nil {
+		return suiptb.Result[movebcs.MoveCoin]{}, err
+	}
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "create_pool", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1, input2})
+	return suiptb.Result[movebcs.MoveCoin]{Argument: result}, nil
+}
+
+// SwapGetAmounts adds a MoveCall of `swap::get_amounts` to the PTB
+func SwapGetAmounts(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument) (suiptb.Result[uint64], suiptb.Result[uint64], suiptb.Result[uint64]) {
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "get_amounts", []sui.TypeTag{t0}, []suiptb.Argument{arg0})
+	nested := suiptb.NestedResults(result, 3)
+	return suiptb.Result[uint64]{Argument: nested[0]}, suiptb.Result[uint64]{Argument: nested[1]}, suiptb.Result[uint64]{Argument: nested[2]}
+}
+
+// SwapGetInputPrice adds a MoveCall of `swap::get_input_price` to the PTB
+func SwapGetInputPrice(ptb *suiptb.ProgrammableTransactionBuilder, arg0 uint64, arg1 uint64, arg2 uint64, arg3 uint64) (suiptb.Result[uint64], error) {
+	input0, err := ptb.Pure(arg0)
+	if err != nil {
+		return suiptb.Result[uint64]{}, err
+	}
+	input1, err := ptb.Pure(arg1)
+	if err != nil {
+		return suiptb.Result[uint64]{}, err
+	}
+	input2, err := ptb.Pure(arg2)
+	if err != nil {
+		return suiptb.Result[uint64]{}, err
+	}
+	input3, err := ptb.Pure(arg3)
+	if err != nil {
+		return suiptb.Result[uint64]{}, err
+	}
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "get_input_price", nil, []suiptb.Argument{input0, input1, input2, input3})
+	return suiptb.Result[uint64]{Argument: result}, nil
+}
+
+// SwapInitForTesting adds a MoveCall of `swap::init_for_testing` to the PTB
+func SwapInitForTesting(ptb *suiptb.ProgrammableTransactionBuilder) {
+	ptb.ProgrammableMoveCall(PackageId, "swap", "init_for_testing", nil, []suiptb.Argument{})
+}
+
+// SwapRemoveLiquidity adds a MoveCall of `swap::remove_liquidity` to the PTB
+func SwapRemoveLiquidity(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument) (suiptb.Result[movebcs.MoveCoin], suiptb.Result[movebcs.MoveCoin]) {
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "remove_liquidity", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1})
+	nested := suiptb.NestedResults(result, 2)
+	return suiptb.Result[movebcs.MoveCoin]{Argument: nested[0]}, suiptb.Result[movebcs.MoveCoin]{Argument: nested[1]}
+}
+
+// SwapRemoveLiquidity_ adds a MoveCall of `swap::remove_liquidity_` to the PTB
+func SwapRemoveLiquidity_(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument) {
+	ptb.ProgrammableMoveCall(PackageId, "swap", "remove_liquidity_", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1})
+}
+
+// SwapSuiPrice adds a MoveCall of `swap::sui_price` to the PTB
+func SwapSuiPrice(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 uint64) (suiptb.Result[uint64], error) {
+	input1, err := ptb.Pure(arg1)
+	if err != nil {
+		return suiptb.Result[uint64]{}, err
+	}
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "sui_price", []sui.TypeTag{t0}, []suiptb.Argument{arg0, input1})
+	return suiptb.Result[uint64]{Argument: result}, nil
+}
+
+// SwapSwapSui adds a MoveCall of `swap::swap_sui` to the PTB
+func SwapSwapSui(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument) suiptb.Result[movebcs.MoveCoin] {
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "swap_sui", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1})
+	return suiptb.Result[movebcs.MoveCoin]{Argument: result}
+}
+
+// SwapSwapSui_ adds a MoveCall of `swap::swap_sui_` to the PTB
+func SwapSwapSui_(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument) {
+	ptb.ProgrammableMoveCall(PackageId, "swap", "swap_sui_", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1})
+}
+
+// SwapSwapToken adds a MoveCall of `swap::swap_token` to the PTB
+func SwapSwapToken(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument) suiptb.Result[movebcs.MoveCoin] {
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "swap_token", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1})
+	return suiptb.Result[movebcs.MoveCoin]{Argument: result}
+}
+
+// SwapSwapToken_ adds a MoveCall of `swap::swap_token_` to the PTB
+func SwapSwapToken_(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 suiptb.Argument) {
+	ptb.ProgrammableMoveCall(PackageId, "swap", "swap_token_", []sui.TypeTag{t0}, []suiptb.Argument{arg0, arg1})
+}
+
+// SwapTokenPrice adds a MoveCall of `swap::token_price` to the PTB
+func SwapTokenPrice(ptb *suiptb.ProgrammableTransactionBuilder, t0 sui.TypeTag, arg0 suiptb.Argument, arg1 uint64) (suiptb.Result[uint64], error) {
+	input1, err := ptb.Pure(arg1)
+	if err != nil {
+		return suiptb.Result[uint64]{}, err
+	}
+	result := ptb.ProgrammableMoveCall(PackageId, "swap", "token_price", []sui.TypeTag{t0}, []suiptb.Argument{arg0, input1})
+	return suiptb.Result[uint64]{Argument: result}, nil
+}
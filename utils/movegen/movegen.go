@@ -0,0 +1,294 @@
+// Package movegen generates the Go bindings of a Move package from its normalized modules, which are
+// returned by `GetNormalizedMoveModulesByPackage`. The bindings contain the BCS structs of the Move
+// structs, and the functions which add the MoveCalls to a ProgrammableTransactionBuilder.
+package movegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// Options of the generated Go package
+type Options struct {
+	// the name of the Go package
+	PackageName string
+	// the package the MoveCalls are sent to, which defaults to the address of the modules.
+	// The bindings of an upgraded package should be generated with the latest package id.
+	PackageId *sui.PackageId
+}
+
+const (
+	importBcs     = "github.com/fardream/go-bcs/bcs"
+	importSui     = "github.com/pattonkan/sui-go/sui"
+	importSuiptb  = "github.com/pattonkan/sui-go/sui/suiptb"
+	importMovebcs = "github.com/pattonkan/sui-go/sui/movebcs"
+)
+
+// Generate returns the gofmt-ed Go source of the bindings. The structs with a field of an unsupported
+// type, e.g. a struct of another package, are skipped with a comment.
+func Generate(modules map[sui.Identifier]*sui.MoveNormalizedModule, opts Options) ([]byte, error) {
+	if opts.PackageName == "" {
+		return nil, fmt.Errorf("the Go package name is required")
+	}
+	var address *sui.Address
+	for _, module := range modules {
+		address = module.Address
+		break
+	}
+	if address == nil {
+		return nil, fmt.Errorf("no module to generate")
+	}
+	packageId := opts.PackageId
+	if packageId == nil {
+		packageId = address
+	}
+	g := &generator{
+		address: address,
+		modules: modules,
+		imports: map[string]bool{importSui: true},
+		structs: map[string]error{},
+		names:   map[string]string{},
+	}
+	for _, moduleName := range sortedKeys(modules) {
+		if err := g.module(modules[moduleName]); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by movegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", opts.PackageName)
+	fmt.Fprintf(&out, "import (\n")
+	for _, path := range sortedKeys(g.imports) {
+		fmt.Fprintf(&out, "\t%q\n", path)
+	}
+	fmt.Fprintf(&out, ")\n\n")
+	fmt.Fprintf(&out, "// PackageId is the package the MoveCalls are sent to\n")
+	fmt.Fprintf(&out, "var PackageId = sui.MustPackageIdFromHex(%q)\n", packageId.String())
+	out.Write(g.buf.Bytes())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can't format the generated source: %w", err)
+	}
+	return source, nil
+}
+
+type generator struct {
+	// the original address of the modules, which the types of the package refer to
+	address *sui.Address
+	modules map[sui.Identifier]*sui.MoveNormalizedModule
+	imports map[string]bool
+	// the structs of the package which can't be bound, by `module::Name`
+	structs map[string]error
+	// the Move names by the generated Go names, to detect the collisions
+	names map[string]string
+	buf   bytes.Buffer
+}
+
+func (g *generator) module(module *sui.MoveNormalizedModule) error {
+	for _, name := range sortedKeys(module.Structs) {
+		if err := g.declare(module.Name, name); err != nil {
+			return err
+		}
+		if err := g.bindable(module.Name, name); err != nil {
+			fmt.Fprintf(&g.buf, "\n// %s is skipped: %s\n", goName(module.Name, name), err)
+			continue
+		}
+		g.structDecl(module.Name, name, module.Structs[name])
+	}
+	for _, name := range sortedKeys(module.ExposedFunctions) {
+		function := module.ExposedFunctions[name]
+		// only the public and entry functions can be called by a PTB
+		if function.Visibility != sui.MoveVisibilityPublic && !function.IsEntry {
+			continue
+		}
+		if err := g.declare(module.Name, name); err != nil {
+			return err
+		}
+		g.functionDecl(module.Name, name, function)
+	}
+	return nil
+}
+
+func (g *generator) declare(module sui.Identifier, name sui.Identifier) error {
+	goName := goName(module, name)
+	moveName := fmt.Sprintf("%s::%s", module, name)
+	if other, ok := g.names[goName]; ok {
+		return fmt.Errorf("both %s and %s are generated as %s", other, moveName, goName)
+	}
+	g.names[goName] = moveName
+	return nil
+}
+
+// bindable checks whether all the fields of the struct have a Go type. Move structs can't be recursive.
+func (g *generator) bindable(module sui.Identifier, name sui.Identifier) error {
+	key := fmt.Sprintf("%s::%s", module, name)
+	if err, ok := g.structs[key]; ok {
+		return err
+	}
+	s, err := g.lookup(module, name)
+	if err == nil {
+		for _, field := range s.Fields {
+			if _, err = g.goType(field.Type); err != nil {
+				err = fmt.Errorf("field %s: %w", field.Name, err)
+				break
+			}
+		}
+	}
+	g.structs[key] = err
+	return err
+}
+
+func (g *generator) lookup(module sui.Identifier, name sui.Identifier) (*sui.MoveNormalizedStruct, error) {
+	m, ok := g.modules[module]
+	if !ok {
+		return nil, fmt.Errorf("module %s not found", module)
+	}
+	s, ok := m.Structs[name]
+	if !ok {
+		return nil, fmt.Errorf("struct %s::%s not found", module, name)
+	}
+	return s, nil
+}
+
+func (g *generator) structDecl(module sui.Identifier, name sui.Identifier, s *sui.MoveNormalizedStruct) {
+	var params []string
+	for i, param := range s.TypeParameters {
+		if param.IsPhantom {
+			params = append(params, "phantom "+typeParamName(i))
+		} else {
+			params = append(params, typeParamName(i))
+		}
+	}
+	moveName := string(name)
+	if len(params) > 0 {
+		moveName += "<" + strings.Join(params, ", ") + ">"
+	}
+	fmt.Fprintf(&g.buf, "\n// %s is the Move struct `%s::%s`\n", goName(module, name), module, moveName)
+	fmt.Fprintf(&g.buf, "type %s%s struct {\n", goName(module, name), typeParamsDecl(len(s.TypeParameters)))
+	for _, field := range s.Fields {
+		// the fields are checked by bindable
+		t, _ := g.goType(field.Type)
+		g.use(t)
+		fmt.Fprintf(&g.buf, "\t%s %s\n", camelCase(string(field.Name)), t.expr)
+	}
+	fmt.Fprintf(&g.buf, "}\n")
+}
+
+func (g *generator) functionDecl(module sui.Identifier, name sui.Identifier, function *sui.MoveNormalizedFunction) {
+	g.imports[importSuiptb] = true
+	fnName := goName(module, name)
+
+	params := []string{"ptb *suiptb.ProgrammableTransactionBuilder"}
+	var typeArgs []string
+	for i := range function.TypeParameters {
+		typeArg := strings.ToLower(typeParamName(i))
+		params = append(params, typeArg+" sui.TypeTag")
+		typeArgs = append(typeArgs, typeArg)
+	}
+
+	var results []string
+	for _, ret := range function.Return {
+		results = append(results, g.resultType(ret.Dereference()))
+	}
+	zeros := make([]string, 0, len(results)+1)
+	for _, result := range results {
+		zeros = append(zeros, result+"{}")
+	}
+
+	// the pure arguments are encoded into the inputs, and the others are passed as they are
+	var body strings.Builder
+	var args []string
+	hasPure := false
+	for i, param := range function.Parameters {
+		if param.IsTxContext() {
+			continue
+		}
+		arg := fmt.Sprintf("arg%d", i)
+		t, err := g.goType(param.Dereference())
+		if err != nil || !t.pure {
+			params = append(params, arg+" suiptb.Argument")
+			args = append(args, arg)
+			continue
+		}
+		hasPure = true
+		g.use(t)
+		params = append(params, arg+" "+t.expr)
+		args = append(args, "input"+fmt.Sprint(i))
+		fmt.Fprintf(&body, "\tinput%d, err := ptb.Pure(%s)\n", i, arg)
+		fmt.Fprintf(&body, "\tif err != nil {\n\t\treturn %s\n\t}\n", strings.Join(append(zeros, "err"), ", "))
+	}
+
+	if hasPure {
+		results = append(results, "error")
+	}
+
+	typeArgsExpr := "nil"
+	if len(typeArgs) > 0 {
+		typeArgsExpr = "[]sui.TypeTag{" + strings.Join(typeArgs, ", ") + "}"
+	}
+	moveCall := fmt.Sprintf("ptb.ProgrammableMoveCall(PackageId, %q, %q, %s, []suiptb.Argument{%s})",
+		module, name, typeArgsExpr, strings.Join(args, ", "))
+
+	var returns []string
+	switch len(function.Return) {
+	case 0:
+		fmt.Fprintf(&body, "\t%s\n", moveCall)
+	case 1:
+		fmt.Fprintf(&body, "\tresult := %s\n", moveCall)
+		returns = append(returns, wrapResult(results[0], "result"))
+	default:
+		fmt.Fprintf(&body, "\tresult := %s\n", moveCall)
+		fmt.Fprintf(&body, "\tnested := suiptb.NestedResults(result, %d)\n", len(function.Return))
+		for i := range function.Return {
+			returns = append(returns, wrapResult(results[i], fmt.Sprintf("nested[%d]", i)))
+		}
+	}
+	if hasPure {
+		returns = append(returns, "nil")
+	}
+	if len(returns) > 0 {
+		fmt.Fprintf(&body, "\treturn %s\n", strings.Join(returns, ", "))
+	}
+
+	resultsDecl := strings.Join(results, ", ")
+	if len(results) > 1 {
+		resultsDecl = "(" + resultsDecl + ")"
+	}
+	fmt.Fprintf(&g.buf, "\n// %s adds a MoveCall of `%s::%s` to the PTB\n", fnName, module, name)
+	fmt.Fprintf(&g.buf, "func %s(%s) %s {\n", fnName, strings.Join(params, ", "), resultsDecl)
+	g.buf.WriteString(body.String())
+	fmt.Fprintf(&g.buf, "}\n")
+}
+
+// resultType is a typed result when the Go type is known and has no type parameter
+func (g *generator) resultType(t *sui.MoveNormalizedType) string {
+	goType, err := g.goType(t)
+	if err != nil || goType.generic {
+		return "suiptb.Argument"
+	}
+	g.use(goType)
+	return "suiptb.Result[" + goType.expr + "]"
+}
+
+func wrapResult(resultType string, arg string) string {
+	if resultType == "suiptb.Argument" {
+		return arg
+	}
+	return resultType + "{Argument: " + arg + "}"
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,142 @@
+package movegen_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/fardream/go-bcs/bcs"
+	"github.com/pattonkan/sui-go/sui"
+	"github.com/pattonkan/sui-go/sui/movebcs"
+	"github.com/pattonkan/sui-go/sui/suiptb"
+	"github.com/pattonkan/sui-go/utils/movegen"
+	"github.com/pattonkan/sui-go/utils/movegen/internal/swapbind"
+
+	"github.com/stretchr/testify/require"
+)
+
+func loadModules(t *testing.T, path string) map[sui.Identifier]*sui.MoveNormalizedModule {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var modules map[sui.Identifier]*sui.MoveNormalizedModule
+	require.NoError(t, json.Unmarshal(data, &modules))
+	return modules
+}
+
+// the bindings in internal/swapbind are generated by
+// `go run ./cmd/movegen -json utils/movegen/testdata/swap.json -pkg swapbind -o utils/movegen/internal/swapbind/swap.go`
+func TestGenerateSwap(t *testing.T) {
+	modules := loadModules(t, "testdata/swap.json")
+	source, err := movegen.Generate(modules, movegen.Options{PackageName: "swapbind"})
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("internal/swapbind/swap.go")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(source))
+}
+
+func TestGeneratedBindings(t *testing.T) {
+	ptb := suiptb.NewTransactionDataTransactionBuilder()
+	coinType := sui.MustNewTypeTag("0x2::sui::SUI")
+	pool := ptb.MustObj(suiptb.ObjectArg{SharedObject: &suiptb.SharedObjectArg{
+		Id:                   sui.MustObjectIdFromHex("0x123"),
+		InitialSharedVersion: 1,
+		Mutable:              true,
+	}})
+
+	price, err := swapbind.SwapSuiPrice(ptb, *coinType, pool, 100)
+	require.NoError(t, err)
+	require.Equal(t, uint16(0), *price.Result)
+	suiAmount, _, lspAmount := swapbind.SwapGetAmounts(ptb, *coinType, pool)
+	require.Equal(t, &suiptb.NestedResult{Cmd: 1, Result: 0}, suiAmount.NestedResult)
+	require.Equal(t, &suiptb.NestedResult{Cmd: 1, Result: 2}, lspAmount.NestedResult)
+
+	pt := ptb.Finish()
+	require.Len(t, pt.Commands, 2)
+	moveCall := pt.Commands[0].MoveCall
+	require.Equal(t, swapbind.PackageId, moveCall.Package)
+	require.Equal(t, sui.Identifier("swap"), moveCall.Module)
+	require.Equal(t, sui.Identifier("sui_price"), moveCall.Function)
+	require.Equal(t, []sui.TypeTag{*coinType}, moveCall.TypeArguments)
+	require.Len(t, moveCall.Arguments, 2)
+	amount := pt.Inputs[*moveCall.Arguments[1].Input].Pure
+	require.Equal(t, []byte{100, 0, 0, 0, 0, 0, 0, 0}, *amount)
+
+	// the generated structs decode the BCS of the Move objects
+	expected := swapbind.SwapPool[any]{
+		Id:         sui.MustObjectIdFromHex("0x456"),
+		Sui:        movebcs.MoveBalance{Value: 1000},
+		Token:      movebcs.MoveBalance{Value: 2000},
+		LspSupply:  movebcs.MoveSupply{Value: 3000},
+		FeePercent: 30,
+	}
+	b, err := bcs.Marshal(&expected)
+	require.NoError(t, err)
+	var decoded swapbind.SwapPool[any]
+	_, err = bcs.Unmarshal(b, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, expected, decoded)
+}
+
+func TestGenerateTypes(t *testing.T) {
+	address := sui.MustAddressFromHex("0xabc")
+	structType := func(address string, module, name sui.Identifier, args ...sui.MoveNormalizedType) sui.MoveNormalizedType {
+		return sui.MoveNormalizedType{Struct: &sui.MoveNormalizedTypeStructType{
+			Address:       sui.MustAddressFromHex(address),
+			Module:        module,
+			Name:          name,
+			TypeArguments: args,
+		}}
+	}
+	u64 := sui.MoveNormalizedType{U64: &sui.EmptyEnum{}}
+	modules := map[sui.Identifier]*sui.MoveNormalizedModule{
+		"vault": {
+			Address: address,
+			Name:    "vault",
+			Structs: map[sui.Identifier]*sui.MoveNormalizedStruct{
+				"Entry": {
+					TypeParameters: []*sui.MoveStructTypeParameter{{}},
+					Fields: []*sui.MoveNormalizedField{
+						{Name: "key", Type: &sui.MoveNormalizedType{TypeParameter: sui.NewMoveTypeParameterIndex(0)}},
+						{Name: "memo", Type: ptr(structType("0x1", "option", "Option", structType("0x1", "string", "String")))},
+						{Name: "amounts", Type: &sui.MoveNormalizedType{Vector: &u64}},
+					},
+				},
+				// a struct of another package can't be bound, nor the structs using it
+				"Foreign": {
+					Fields: []*sui.MoveNormalizedField{{Name: "table", Type: ptr(structType("0x2", "table", "Table", u64, u64))}},
+				},
+				"Wrapper": {
+					Fields: []*sui.MoveNormalizedField{{Name: "inner", Type: ptr(structType("0xabc", "vault", "Foreign"))}},
+				},
+			},
+			ExposedFunctions: map[sui.Identifier]*sui.MoveNormalizedFunction{
+				"deposit": {
+					Visibility: sui.MoveVisibilityPublic,
+					Parameters: []sui.MoveNormalizedType{
+						{Reference: ptr(structType("0xabc", "vault", "Wrapper"))},
+						{Vector: &sui.MoveNormalizedType{Address: &sui.EmptyEnum{}}},
+						structType("0x1", "option", "Option", u64),
+					},
+					Return: []sui.MoveNormalizedType{structType("0xabc", "vault", "Entry", u64)},
+				},
+				"internal": {Visibility: sui.MoveVisibilityFriend},
+			},
+		},
+	}
+	source, err := movegen.Generate(modules, movegen.Options{PackageName: "vault"})
+	require.NoError(t, err)
+	code := string(source)
+	require.Contains(t, code, "type VaultEntry[T0 any] struct {\n\tKey     T0\n\tMemo    movebcs.Option[string]\n\tAmounts []uint64\n}")
+	require.Contains(t, code, "// VaultForeign is skipped: field table: unsupported type 0x2::table::Table")
+	require.Contains(t, code, "// VaultWrapper is skipped: field inner: vault::Foreign is skipped")
+	require.Contains(t, code, "func VaultDeposit(ptb *suiptb.ProgrammableTransactionBuilder, arg0 suiptb.Argument, arg1 []*sui.Address, arg2 movebcs.Option[uint64]) (suiptb.Result[VaultEntry[uint64]], error)")
+	require.NotContains(t, code, "VaultInternal")
+
+	_, err = movegen.Generate(modules, movegen.Options{})
+	require.Error(t, err)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
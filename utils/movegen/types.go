@@ -0,0 +1,183 @@
+package movegen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pattonkan/sui-go/sui"
+)
+
+// goType is the Go type of a Move type
+type goType struct {
+	expr string
+	// whether the type refers to a type parameter, like `T0`
+	generic bool
+	// whether the value can be passed as a pure input
+	pure    bool
+	imports []string
+}
+
+// knownStruct binds a struct of the Move stdlib or the Sui framework. The type arguments are dropped
+// unless wrap is set, e.g. `Coin<T>` is decoded the same for all `T`.
+type knownStruct struct {
+	expr    string
+	pure    bool
+	imports []string
+	// wrap the Go type of the single type argument, e.g. `movebcs.Option[%s]`
+	wrap bool
+}
+
+var knownStructs = map[string]knownStruct{
+	moveStructKey(sui.SuiPackageIdMoveStdlib, "string", "String"):      {expr: "string", pure: true},
+	moveStructKey(sui.SuiPackageIdMoveStdlib, "ascii", "String"):       {expr: "string", pure: true},
+	moveStructKey(sui.SuiPackageIdMoveStdlib, "option", "Option"):      {expr: "movebcs.Option[%s]", pure: true, imports: []string{importMovebcs}, wrap: true},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "object", "ID"):        {expr: "*sui.ObjectId", pure: true, imports: []string{importSui}},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "object", "UID"):       {expr: "*sui.ObjectId", imports: []string{importSui}},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "url", "Url"):          {expr: "string"},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "balance", "Balance"):  {expr: "movebcs.MoveBalance", imports: []string{importMovebcs}},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "balance", "Supply"):   {expr: "movebcs.MoveSupply", imports: []string{importMovebcs}},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "coin", "Coin"):        {expr: "movebcs.MoveCoin", imports: []string{importMovebcs}},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "coin", "TreasuryCap"): {expr: "movebcs.MoveTreasuryCap", imports: []string{importMovebcs}},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "coin", "CoinMetadata"): {
+		expr: "movebcs.MoveCoinMetadata", imports: []string{importMovebcs},
+	},
+	moveStructKey(sui.SuiPackageIdSuiFramework, "clock", "Clock"): {expr: "movebcs.Clock", imports: []string{importMovebcs}},
+}
+
+func moveStructKey(address *sui.Address, module sui.Identifier, name sui.Identifier) string {
+	return fmt.Sprintf("%s::%s::%s", address, module, name)
+}
+
+// goType maps a Move type, which must not be a reference, to its Go type in the generated package
+func (g *generator) goType(t *sui.MoveNormalizedType) (goType, error) {
+	switch {
+	case t.Bool != nil:
+		return goType{expr: "bool", pure: true}, nil
+	case t.U8 != nil:
+		return goType{expr: "uint8", pure: true}, nil
+	case t.U16 != nil:
+		return goType{expr: "uint16", pure: true}, nil
+	case t.U32 != nil:
+		return goType{expr: "uint32", pure: true}, nil
+	case t.U64 != nil:
+		return goType{expr: "uint64", pure: true}, nil
+	case t.U128 != nil:
+		return goType{expr: "bcs.Uint128", pure: true, imports: []string{importBcs}}, nil
+	case t.U256 != nil:
+		// little endian, as go-bcs has no 256 bits integer
+		return goType{expr: "[32]uint8", pure: true}, nil
+	case t.Address != nil:
+		return goType{expr: "*sui.Address", pure: true, imports: []string{importSui}}, nil
+	case t.Vector != nil:
+		elem, err := g.goType(t.Vector)
+		if err != nil {
+			return goType{}, err
+		}
+		elem.expr = "[]" + elem.expr
+		return elem, nil
+	case t.TypeParameter != nil:
+		return goType{expr: typeParamName(int(*t.TypeParameter)), generic: true}, nil
+	case t.Struct != nil:
+		return g.structType(t.Struct)
+	case t.Signer != nil:
+		return goType{}, fmt.Errorf("signer has no value")
+	case t.Reference != nil, t.MutableReference != nil:
+		return goType{}, fmt.Errorf("reference has no value")
+	default:
+		return goType{}, fmt.Errorf("empty MoveNormalizedType")
+	}
+}
+
+func (g *generator) structType(s *sui.MoveNormalizedTypeStructType) (goType, error) {
+	if s.Address != nil && *s.Address == *g.address {
+		if err := g.bindable(s.Module, s.Name); err != nil {
+			return goType{}, fmt.Errorf("%s::%s is skipped", s.Module, s.Name)
+		}
+		t := goType{expr: goName(s.Module, s.Name)}
+		if len(s.TypeArguments) > 0 {
+			exprs := make([]string, len(s.TypeArguments))
+			for i, typeArg := range s.TypeArguments {
+				arg, err := g.goType(&typeArg)
+				if err != nil {
+					return goType{}, err
+				}
+				exprs[i] = arg.expr
+				t.generic = t.generic || arg.generic
+				t.imports = append(t.imports, arg.imports...)
+			}
+			t.expr += "[" + strings.Join(exprs, ", ") + "]"
+		}
+		return t, nil
+	}
+
+	known, ok := knownStructs[moveStructKey(s.Address, s.Module, s.Name)]
+	if !ok {
+		return goType{}, fmt.Errorf("unsupported type %s::%s::%s", s.Address.ShortString(), s.Module, s.Name)
+	}
+	t := goType{expr: known.expr, pure: known.pure, imports: known.imports}
+	if known.wrap {
+		if len(s.TypeArguments) != 1 {
+			return goType{}, fmt.Errorf("%s::%s expects 1 type argument", s.Module, s.Name)
+		}
+		arg, err := g.goType(&s.TypeArguments[0])
+		if err != nil {
+			return goType{}, err
+		}
+		t.expr = fmt.Sprintf(known.expr, arg.expr)
+		t.generic = arg.generic
+		t.pure = t.pure && arg.pure
+		t.imports = append(append([]string{}, t.imports...), arg.imports...)
+	}
+	return t, nil
+}
+
+// use adds the imports of the type to the generated file
+func (g *generator) use(t goType) {
+	for _, path := range t.imports {
+		g.imports[path] = true
+	}
+}
+
+// goName is the exported Go name of a Move struct or function, prefixed by the module
+func goName(module sui.Identifier, name sui.Identifier) string {
+	return camelCase(string(module)) + camelCase(string(name))
+}
+
+// camelCase converts `swap_sui` into `SwapSui`. The leading and trailing underscores are kept,
+// so `swap_sui_` doesn't collide with `swap_sui`.
+func camelCase(s string) string {
+	trimmed := strings.Trim(s, "_")
+	if trimmed == "" {
+		return "X" + s
+	}
+	var b strings.Builder
+	b.WriteString(s[:strings.Index(s, trimmed)])
+	for _, word := range strings.Split(trimmed, "_") {
+		if word == "" {
+			b.WriteString("_")
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	b.WriteString(s[strings.Index(s, trimmed)+len(trimmed):])
+	name := b.String()
+	if name[0] == '_' {
+		name = "X" + name
+	}
+	return name
+}
+
+func typeParamName(i int) string {
+	return fmt.Sprintf("T%d", i)
+}
+
+func typeParamsDecl(n int) string {
+	if n == 0 {
+		return ""
+	}
+	params := make([]string, n)
+	for i := range params {
+		params[i] = typeParamName(i)
+	}
+	return "[" + strings.Join(params, ", ") + " any]"
+}